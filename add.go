@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// noteItem is the bullet or checkbox of a Markdown list item, so notes
+// can be piped in as they are
+var noteItem = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?`)
+
+// parseQuickAdd reads the quick-add syntax out of a task title:
+//
+//	Fix login +web !high due:tomorrow @office
+//
+// +name sets the project, !name (a column name, or number from 1) the
+// priority, a lone ! marks the task urgent, ~2h is the estimate and due:
+// takes the dates queries take.
+// @contexts stay in the title, where they live.
+func parseQuickAdd(line string, task *Task, now time.Time) error {
+	var title []string
+	for _, word := range strings.Fields(line) {
+		switch {
+		case word == "!":
+			task.Urgent = true
+		case strings.HasPrefix(word, "!"):
+			// Numbers count columns from 1, as the column keys do
+			p, ok := findColumn(word[1:])
+			if !ok {
+				return fmt.Errorf("no column %q", word[1:])
+			}
+			task.Priority = p
+		case strings.HasPrefix(word, "+") && len(word) > 1:
+			task.Project = word[1:]
+		case strings.HasPrefix(word, "~") && len(word) > 1:
+			d, err := parseAge(word[1:])
+			if err != nil {
+				return err
+			}
+			task.Estimate = int(d.Minutes())
+		case strings.HasPrefix(word, "due:"):
+			due, err := queryDay(strings.TrimPrefix(word, "due:"), now)
+			if err != nil {
+				return err
+			}
+			task.Due = &due
+		default:
+			title = append(title, word)
+		}
+	}
+	task.Title = strings.Join(title, " ")
+	return nil
+}
+
+// readTaskLines creates a task per non-empty line of r. A Markdown list
+// item loses its bullet, and a checked one is added done.
+func readTaskLines(r io.Reader, base Task, parse bool, now time.Time) ([]Task, error) {
+	var tasks []Task
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		done := false
+		if m := noteItem.FindStringSubmatch(line); m != nil {
+			done = strings.EqualFold(m[1], "x")
+			line = line[len(m[0]):]
+		}
+		if line == "" {
+			continue
+		}
+		task := base
+		task.ID = generateID()
+		task.Title = line
+		task.CreatedAt = now
+		if parse {
+			if err := parseQuickAdd(line, &task, now); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			if task.Title == "" {
+				return nil, fmt.Errorf("line %d: no title left", n)
+			}
+		}
+		if done {
+			setCompleted(&task, true)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// cmdAdd adds tasks from the command line or, with --stdin, one per line
+// of the input:
+//
+//	grep -rn TODO . | basket add --stdin --project cleanup
+func cmdAdd(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	stdin := fs.Bool("stdin", false, "add a task per non-empty line of the input")
+	parse := fs.Bool("parse", false, "read +project, !priority, !, ~estimate and due: out of titles")
+	priority := fs.String("priority", "", "column to add to, the board's default column (the middle one) unless given")
+	project := fs.String("project", "", "project of the new tasks")
+	words := parseInterspersed(fs, args)
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	base := Task{Priority: settingsOf(taskList).defaultColumn(), Project: *project}
+	if *priority != "" {
+		p, ok := parsePriorityName(*priority)
+		if !ok {
+			return fmt.Errorf("no column %q", *priority)
+		}
+		base.Priority = p
+	}
+
+	var input io.Reader
+	switch {
+	case *stdin && len(words) > 0:
+		return fmt.Errorf("give a title or --stdin, not both")
+	case *stdin:
+		input = os.Stdin
+	case len(words) > 0:
+		input = strings.NewReader(strings.Join(words, " "))
+	default:
+		return fmt.Errorf("usage: basket add <title>... or basket add --stdin")
+	}
+	added, err := readTaskLines(input, base, *parse, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(added) == 0 {
+		return fmt.Errorf("nothing to add")
+	}
+
+	before := cloneTasks(taskList.Tasks)
+	taskList.Tasks = append(taskList.Tasks, added...)
+	touchTasks(before, taskList.Tasks, time.Now())
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	for _, task := range added {
+		fmt.Printf("%-6s  %-8s %s\n", shortID(task.ID), task.Priority, task.Title)
+	}
+	return nil
+}
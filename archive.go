@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// archiveCompleted moves tasks that were completed more than days ago
+// from the board into its archive. Completed tasks from before completion
+// times were recorded are stamped now, so they age out from here on.
+// It reports whether the board changed.
+func archiveCompleted(taskList *TaskList, days int, now time.Time) bool {
+	cutoff := now.AddDate(0, 0, -days)
+	changed := false
+
+	kept := taskList.Tasks[:0]
+	for _, task := range taskList.Tasks {
+		if task.Completed && task.CompletedAt == nil {
+			stamp := now
+			task.CompletedAt = &stamp
+			changed = true
+		}
+		if task.Completed && task.CompletedAt.Before(cutoff) {
+			stamp := now
+			task.ArchivedAt = &stamp
+			taskList.Archive = append(taskList.Archive, task)
+			changed = true
+			continue
+		}
+		kept = append(kept, task)
+	}
+	taskList.Tasks = kept
+	return changed
+}
+
+// autoArchive applies the archive_after_days setting to a freshly loaded
+// board and persists the result. A zero setting disables it. When the
+// board cannot be written it is left as it was, so nothing looks
+// archived that is not.
+func autoArchive(path string, taskList *TaskList, days int) error {
+	if days <= 0 {
+		return nil
+	}
+	before := *taskList
+	before.Tasks = cloneTasks(taskList.Tasks)
+	if !archiveCompleted(taskList, days, time.Now()) {
+		return nil
+	}
+	if err := saveBoard(path, *taskList); err != nil {
+		*taskList = before
+		return err
+	}
+	return nil
+}
+
+// archivedAt is when task was archived, as near as the board knows: tasks
+// archived before that was recorded fall back to when they were completed
+// or last changed. ok is false when there is nothing to go on.
+func archivedAt(task Task) (time.Time, bool) {
+	for _, at := range []*time.Time{task.ArchivedAt, task.CompletedAt, task.UpdatedAt} {
+		if at != nil {
+			return *at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// purgeArchive drops archived tasks archived before cutoff and returns
+// them. Tasks of unknown age are kept.
+func purgeArchive(taskList *TaskList, cutoff time.Time) []Task {
+	var purged []Task
+	kept := taskList.Archive[:0]
+	for _, task := range taskList.Archive {
+		if at, ok := archivedAt(task); ok && at.Before(cutoff) {
+			purged = append(purged, task)
+			continue
+		}
+		kept = append(kept, task)
+	}
+	taskList.Archive = kept
+	return purged
+}
+
+func cmdPurge(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	olderThan := fs.String("older-than", "30d", "purge archived tasks archived longer ago than this")
+	dryRun := fs.Bool("dry-run", false, "show what would be removed without changing the board")
+	fs.Parse(args)
+
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		return err
+	}
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+
+	purged := purgeArchive(&taskList, time.Now().Add(-age))
+	for _, task := range purged {
+		fmt.Printf("%-6s  %s\n", shortID(task.ID), task.Title)
+	}
+
+	if *dryRun {
+		fmt.Printf("would purge %d archived task(s) from %s\n", len(purged), path)
+		return nil
+	}
+
+	// Rewrite even when nothing was purged so the file is normalized
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	fmt.Printf("purged %d archived task(s) from %s\n", len(purged), path)
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// currentUser is who "only mine" filters for: the config's user, else the
+// git author name, else the login name
+func currentUser(cfg Config) string {
+	if cfg.User != "" {
+		return cfg.User
+	}
+	if out, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(out)); name != "" {
+			return name
+		}
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// sameAssignee compares assignees the way people type them
+func sameAssignee(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// initials shortens a name for a card: "Ada Lovelace" is "AL", "ada" is
+// "AD"
+func initials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '.' || r == '-' || r == '_' || r == '@'
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) == 1 {
+		runes := []rune(words[0])
+		if len(runes) > 2 {
+			runes = runes[:2]
+		}
+		return strings.ToUpper(string(runes))
+	}
+	first := []rune(words[0])[0]
+	last := []rune(words[len(words)-1])[0]
+	return strings.ToUpper(string([]rune{first, last}))
+}
+
+// openAssign prompts for the selected task's assignee, suggesting the
+// current user for unassigned tasks
+func (m *model) openAssign() tea.Cmd {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return nil
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
+			m.mode = ViewAssign
+			m.editingTask = &m.tasks[i]
+			m.inputErr = ""
+			m.input.Reset()
+			m.input.Placeholder = T("assign.placeholder")
+			value := m.tasks[i].Assignee
+			if value == "" {
+				value = m.user
+			}
+			m.input.SetValue(value)
+			m.input.CursorEnd()
+			return m.input.Focus()
+		}
+	}
+	return nil
+}
+
+func (m model) updateAssign(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = ViewBoard
+		m.editingTask = nil
+		return m, nil
+
+	case key.Matches(msg, m.keys.Confirm):
+		if m.editingTask != nil {
+			m.editingTask.Assignee = strings.TrimSpace(m.input.Value())
+			if m.editingTask.Assignee == "" {
+				m.commit(T("status.unassigned"))
+			} else {
+				m.commit(fmt.Sprintf(T("status.assigned"), m.editingTask.Assignee))
+			}
+		}
+		m.mode = ViewBoard
+		m.editingTask = nil
+		m.clampSelection()
+		return m, nil
+	}
+
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewAssign() string {
+	title := T("assign.title")
+	if m.editingTask != nil {
+		title = fmt.Sprintf(T("assign.task_title"), truncate(m.editingTask.Title, 40))
+	}
+	styledTitle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent).
+		Render(title)
+
+	return fmt.Sprintf(
+		"%s\n\n%s\n\n%s",
+		styledTitle,
+		m.input.View(),
+		m.renderFooter(),
+	)
+}
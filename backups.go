@@ -0,0 +1,393 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// backupTimeFormat names backup files so they sort chronologically
+const backupTimeFormat = "20060102T150405.000"
+
+// backupRetention is how many backups are kept per board
+var backupRetention = 10
+
+// backupInfo describes one backup file
+type backupInfo struct {
+	ID     string // "<board key>/<timestamp>", accepted by restore
+	Path   string
+	Source string // the board file the backup was taken from
+	Time   time.Time
+	Tasks  int
+}
+
+// getDataDir is where basket keeps backups, journals and its state:
+// $XDG_DATA_HOME/basket, ~/.local/share/basket, or on Windows
+// %LOCALAPPDATA%\basket
+func getDataDir() string {
+	if dir := getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "basket")
+	}
+	if dir := getenv("LOCALAPPDATA"); dir != "" && goos == "windows" {
+		return filepath.Join(dir, "basket")
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return ".basket"
+	}
+	return filepath.Join(home, ".local", "share", "basket")
+}
+
+func getBackupsDir() string {
+	return filepath.Join(getDataDir(), "backups")
+}
+
+// backupKey names the backup directory of the board at path
+func backupKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if isGlobalPath(path) {
+		return "global"
+	}
+	sum := sha1.Sum([]byte(path))
+	return "local-" + hex.EncodeToString(sum[:4])
+}
+
+// backupBoard copies the current contents of the board at path into its
+// backup directory and prunes backups beyond the retention count. A board
+// that does not exist yet has nothing to back up.
+func backupBoard(path string) error {
+	if backupRetention <= 0 {
+		return nil
+	}
+	data, err := readBoardJSON(path)
+	if err != nil {
+		// A damaged Markdown board has no JSON form; the recovery screen
+		// keeps a copy of it instead
+		var corrupt *corruptBoardError
+		if os.IsNotExist(err) || errors.As(err, &corrupt) {
+			return nil
+		}
+		return err
+	}
+
+	dir := filepath.Join(getBackupsDir(), backupKey(path))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	abs, _ := filepath.Abs(path)
+	if err := os.WriteFile(filepath.Join(dir, "source"), []byte(abs), 0644); err != nil {
+		return err
+	}
+
+	name := time.Now().Format(backupTimeFormat) + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+		return err
+	}
+
+	files, err := backupFiles(dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > backupRetention {
+		os.Remove(filepath.Join(dir, files[0]))
+		files = files[1:]
+	}
+	return nil
+}
+
+// readBoardJSON returns the board at path as it is stored, in JSON. Boards
+// in other formats are converted, so every backup is a JSON board.
+func readBoardJSON(path string) ([]byte, error) {
+	if _, ok := formatFor(path).(jsonFormat); ok {
+		return os.ReadFile(path)
+	}
+	taskList, err := formatFor(path).read(path)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(taskList, "", "  ")
+}
+
+// backupFiles lists the backup file names in dir, oldest first
+func backupFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// listBackups returns the backups of the board at path, newest first.
+// An empty path lists the backups of every board.
+func listBackups(path string) ([]backupInfo, error) {
+	var keys []string
+	if path != "" {
+		keys = []string{backupKey(path)}
+	} else {
+		entries, err := os.ReadDir(getBackupsDir())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				keys = append(keys, e.Name())
+			}
+		}
+	}
+
+	var backups []backupInfo
+	for _, key := range keys {
+		dir := filepath.Join(getBackupsDir(), key)
+		source, _ := os.ReadFile(filepath.Join(dir, "source"))
+		files, err := backupFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range files {
+			stamp := strings.TrimSuffix(name, ".json")
+			info := backupInfo{
+				ID:     key + "/" + stamp,
+				Path:   filepath.Join(dir, name),
+				Source: string(source),
+			}
+			info.Time, _ = time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+			if taskList, err := readBackup(info); err == nil {
+				info.Tasks = len(taskList.Tasks)
+			}
+			backups = append(backups, info)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// readBackup reads a backup, opened with the key of its board if the
+// board is protected
+func readBackup(info backupInfo) (TaskList, error) {
+	var taskList TaskList
+	data, err := os.ReadFile(info.Path)
+	if err != nil {
+		return taskList, err
+	}
+	if data, err = openBoardData(info.Source, data); err != nil {
+		return taskList, err
+	}
+	err = json.Unmarshal(data, &taskList)
+	return taskList, err
+}
+
+// restoreBackup replaces the board the backup was taken from with its
+// contents. The current board is backed up first, so a restore can be
+// undone. It returns the board's tasks from before and after, so the
+// change can be queued for sync and is not pulled over.
+func restoreBackup(info backupInfo) (before, after []Task, err error) {
+	if info.Source == "" {
+		return nil, nil, fmt.Errorf("backup %s does not record its board", info.ID)
+	}
+	taskList, err := readBackup(info)
+	if err != nil {
+		return nil, nil, fmt.Errorf("backup %s is not a valid board: %w", info.ID, err)
+	}
+	// A damaged board has nothing to diff against; all of the backup is
+	// queued then
+	if current, err := formatFor(info.Source).read(info.Source); err == nil {
+		before = current.Tasks
+	}
+	if err := backupBoard(info.Source); err != nil {
+		return nil, nil, err
+	}
+	if err := formatFor(info.Source).write(info.Source, taskList); err != nil {
+		return nil, nil, err
+	}
+	return before, taskList.Tasks, nil
+}
+
+func cmdBackups(cfg Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: basket backups list|restore")
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("backups list", flag.ExitOnError)
+		global, local := boardFlags(fs)
+		all := fs.Bool("all", false, "list backups of every board")
+		fs.Parse(args[1:])
+
+		path := ""
+		if !*all {
+			path = resolveBoardPath(*global, *local)
+		}
+		backups, err := listBackups(path)
+		if err != nil {
+			return err
+		}
+		for _, b := range backups {
+			fmt.Printf("%-36s  %s  %3d tasks  %s\n", b.ID, b.Time.Format("2006-01-02 15:04:05"), b.Tasks, b.Source)
+		}
+		return nil
+
+	case "restore":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: basket backups restore <id>")
+		}
+		backups, err := listBackups("")
+		if err != nil {
+			return err
+		}
+		for _, b := range backups {
+			if b.ID == args[1] {
+				before, after, err := restoreBackup(b)
+				if err != nil {
+					return err
+				}
+				queueBoardSync(cfg, b.Source, before, after)
+				fmt.Printf("restored %s to %s\n", b.ID, b.Source)
+				return nil
+			}
+		}
+		return fmt.Errorf("no backup %q (see basket backups list --all)", args[1])
+
+	default:
+		return fmt.Errorf("unknown backups command %q", args[0])
+	}
+}
+
+func (m *model) openBackups() {
+	m.mode = ViewBackups
+	m.backupCursor = 0
+	m.backupErr = ""
+	backups, err := listBackups(m.currentPath())
+	if err != nil {
+		m.backupErr = err.Error()
+	}
+	m.backups = backups
+	m.loadBackupPreview()
+}
+
+func (m *model) loadBackupPreview() {
+	m.backupPreview = nil
+	if m.backupCursor >= len(m.backups) {
+		return
+	}
+	taskList, err := readBackup(m.backups[m.backupCursor])
+	if err != nil {
+		m.backupErr = err.Error()
+		return
+	}
+	m.backupPreview = taskList.Tasks
+}
+
+func (m model) updateBackups(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit):
+		m.mode = ViewBoard
+		if m.corrupt != nil {
+			m.mode = ViewCorrupt
+		}
+
+	case key.Matches(msg, m.keys.Up):
+		if m.backupCursor > 0 {
+			m.backupCursor--
+			m.loadBackupPreview()
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.backupCursor < len(m.backups)-1 {
+			m.backupCursor++
+			m.loadBackupPreview()
+		}
+
+	case key.Matches(msg, m.keys.Restore):
+		if m.backupCursor >= len(m.backups) {
+			break
+		}
+		info := m.backups[m.backupCursor]
+		before, after, err := restoreBackup(info)
+		if err != nil {
+			m.backupErr = err.Error()
+			break
+		}
+		if isGlobalPath(info.Source) {
+			m.queueSync(diffTasks(before, after))
+		}
+		if err := m.reloadCurrent(); err != nil {
+			m.backupErr = err.Error()
+			break
+		}
+		m.mode = ViewBoard
+	}
+
+	return m, nil
+}
+
+func (m model) viewBackups() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("backups.title")) + "\n")
+
+	if len(m.backups) == 0 {
+		b.WriteString(helpStyle.Render(T("backups.empty")) + "\n")
+	}
+
+	for i, info := range m.backups {
+		line := fmt.Sprintf("%s  %s", info.Time.Format("2006-01-02 15:04:05"), fmt.Sprintf(T("backups.tasks"), info.Tasks))
+		if i == m.backupCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if len(m.backupPreview) > 0 {
+		b.WriteString("\n" + helpStyle.Render(T("backups.preview")) + "\n")
+		for p := maxPriority(); p >= 0; p-- {
+			for _, task := range m.backupPreview {
+				if clampPriority(task.Priority) != p {
+					continue
+				}
+				checkbox := "☐"
+				if task.Completed {
+					checkbox = "☑"
+				}
+				name := lipgloss.NewStyle().Foreground(p.Color()).Render(fmt.Sprintf("%-8s", p.String()))
+				b.WriteString(fmt.Sprintf("  %s %s %s\n", name, checkbox, truncate(task.Title, 50)))
+			}
+		}
+	}
+
+	if m.backupErr != "" {
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(colorError).Render(m.backupErr) + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
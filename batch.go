@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// batchFlags registers what done and move share: the board, a query
+// choosing the tasks and --dry-run
+func batchFlags(fs *flag.FlagSet) (global, local *bool, query *string, dryRun *bool) {
+	global, local = boardFlags(fs)
+	query = fs.String("query", "", "change every task matching a query")
+	dryRun = fs.Bool("dry-run", false, "list the tasks that would change without saving")
+	return global, local, query, dryRun
+}
+
+// batchTargets are the indexes of the tasks named by refs or matched by
+// the query. Habits are left out, they are ticked off per day instead.
+func batchTargets(tasks []Task, refs []string, src string) ([]int, error) {
+	if (len(refs) > 0) == (src != "") {
+		return nil, fmt.Errorf("name tasks by ID or select them with --query, one of the two")
+	}
+	var targets []int
+	for _, ref := range refs {
+		i, ok := findTask(tasks, ref)
+		if !ok {
+			return nil, fmt.Errorf("no single task matches %q", ref)
+		}
+		targets = append(targets, i)
+	}
+	if src == "" {
+		return targets, nil
+	}
+	query, err := parseQuery(src)
+	if err != nil {
+		return nil, pointAtQueryError(src, err)
+	}
+	now := time.Now()
+	for i, task := range tasks {
+		if !task.Habit && query.Match(task, tasks, now) {
+			targets = append(targets, i)
+		}
+	}
+	return targets, nil
+}
+
+// runBatch loads the board, applies change to every target that it
+// changes and saves once
+func runBatch(cfg Config, global, local, dryRun bool, refs []string, query string, change func(*Task) bool) error {
+	path := resolveBoardPath(global, local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	targets, err := batchTargets(taskList.Tasks, refs, query)
+	if err != nil {
+		return err
+	}
+
+	before := cloneTasks(taskList.Tasks)
+	changed := 0
+	for _, i := range targets {
+		task := &taskList.Tasks[i]
+		if !change(task) {
+			continue
+		}
+		changed++
+		fmt.Printf("%-6s  %-8s %s\n", shortID(task.ID), task.Priority, task.Title)
+	}
+	switch {
+	case dryRun:
+		fmt.Printf("%d task(s) would change\n", changed)
+		return nil
+	case changed == 0:
+		fmt.Println("nothing to change")
+		return nil
+	}
+
+	touchTasks(before, taskList.Tasks, time.Now())
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("%d task(s) changed\n", changed)
+	return nil
+}
+
+// cmdDone completes tasks, or with --undo reopens them:
+//
+//	basket done --query 'tag:release and not completed'
+func cmdDone(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("done", flag.ExitOnError)
+	global, local, query, dryRun := batchFlags(fs)
+	undo := fs.Bool("undo", false, "reopen the tasks instead")
+	refs := parseInterspersed(fs, args)
+
+	return runBatch(cfg, *global, *local, *dryRun, refs, *query, func(task *Task) bool {
+		if task.Completed != *undo {
+			return false
+		}
+		setCompleted(task, !*undo)
+		return true
+	})
+}
+
+// cmdMove moves tasks to another column:
+//
+//	basket move --to high --query 'overdue'
+func cmdMove(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("move", flag.ExitOnError)
+	global, local, query, dryRun := batchFlags(fs)
+	to := fs.String("to", "", "the column to move to")
+	refs := parseInterspersed(fs, args)
+	if *to == "" {
+		return fmt.Errorf("usage: basket move --to <column> <id>... or --query q")
+	}
+
+	// the board's columns are only known once it is picked
+	resolveBoardPath(*global, *local)
+	target, ok := parsePriorityName(*to)
+	if !ok {
+		return fmt.Errorf("no column %q", *to)
+	}
+	return runBatch(cfg, *global, *local, *dryRun, refs, *query, func(task *Task) bool {
+		if task.Priority == target {
+			return false
+		}
+		task.Priority = target
+		return true
+	})
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boardState is what basket remembers about local boards between runs
+type boardState struct {
+	// LastUsed is the board last picked, by the directory it was picked in
+	LastUsed map[string]string `json:"last_used"`
+
+	// Known is every local board ever opened, by path
+	Known map[string]*knownBoard `json:"known"`
+}
+
+// knownBoard is the registry entry of a local board
+type knownBoard struct {
+	Opens      int       `json:"opens"`
+	LastOpened time.Time `json:"last_opened"`
+}
+
+func getBoardStatePath() string {
+	return filepath.Join(getDataDir(), "boards.json")
+}
+
+func loadBoardState() boardState {
+	var state boardState
+	if data, err := os.ReadFile(getBoardStatePath()); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	if state.LastUsed == nil {
+		state.LastUsed = map[string]string{}
+	}
+	if state.Known == nil {
+		state.Known = map[string]*knownBoard{}
+	}
+	return state
+}
+
+func saveBoardState(state boardState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(getDataDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(getBoardStatePath(), data, 0644)
+}
+
+// lastUsedBoard is the board last picked in dir, if it still exists
+func lastUsedBoard(dir string) (string, bool) {
+	path, ok := loadBoardState().LastUsed[dir]
+	if !ok {
+		return "", false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// rememberBoard records path as the board picked in dir
+func rememberBoard(dir, path string) {
+	state := loadBoardState()
+	state.LastUsed[dir] = path
+	if err := saveBoardState(state); err != nil {
+		logger.Error("board state", "err", err)
+	}
+}
+
+// recordOpen adds the local board at path to the registry, or counts
+// another open of it
+func recordOpen(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	state := loadBoardState()
+	entry, ok := state.Known[path]
+	if !ok {
+		entry = &knownBoard{}
+		state.Known[path] = entry
+	}
+	entry.Opens++
+	entry.LastOpened = time.Now()
+	if err := saveBoardState(state); err != nil {
+		logger.Error("board state", "err", err)
+	}
+}
+
+// knownBoards lists the registry, most recently opened first
+func knownBoards(state boardState) []string {
+	paths := make([]string, 0, len(state.Known))
+	for path := range state.Known {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return state.Known[paths[i]].LastOpened.After(state.Known[paths[j]].LastOpened)
+	})
+	return paths
+}
+
+// skipDirs are not searched for boards: vendored dependencies hold no
+// boards of their own, and can be huge
+var skipDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// discoverBoards lists every local board under root, such as one per
+// package in a monorepo, in path order
+func discoverBoards(root string) []string {
+	var boards []string
+	seen := map[string]bool{}
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() && path != root && name != ".basket" &&
+			(strings.HasPrefix(name, ".") || skipDirs[name]) {
+			return fs.SkipDir
+		}
+		if boardBase(name) != ".basket" {
+			return nil
+		}
+		board := findBoard(filepath.Join(filepath.Dir(path), ".basket"))
+		if !seen[board] {
+			seen[board] = true
+			boards = append(boards, board)
+		}
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return boards
+}
+
+// needsBoardPick reports whether basket should ask which board to open:
+// the repository has several and none was picked here before
+func needsBoardPick() bool {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	if _, ok := lastUsedBoard(cwd); ok {
+		return false
+	}
+	root, ok := repoRoot(cwd)
+	return ok && len(discoverBoards(root)) > 1
+}
+
+// boardChoice is one entry in the board picker
+type boardChoice struct {
+	Path  string
+	Open  int  // open tasks
+	Opens int  // times basket opened it
+	Repo  bool // in the current repository
+}
+
+// pickerBoards lists the boards of the repository around the current
+// directory, then the other boards in the registry that still exist
+func pickerBoards() []boardChoice {
+	state := loadBoardState()
+	var out []boardChoice
+	seen := map[string]bool{}
+	add := func(path string, repo bool) {
+		choice := boardChoice{Path: path, Repo: repo}
+		if entry, ok := state.Known[path]; ok {
+			choice.Opens = entry.Opens
+		}
+		if tasks, err := loadTasks(path); err == nil {
+			for _, task := range tasks {
+				if !task.Completed && !offBoard(task) {
+					choice.Open++
+				}
+			}
+		}
+		seen[path] = true
+		out = append(out, choice)
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if root, ok := repoRoot(cwd); ok {
+			for _, path := range discoverBoards(root) {
+				add(path, true)
+			}
+		}
+	}
+	for _, path := range knownBoards(state) {
+		if _, err := os.Stat(path); err == nil && !seen[path] {
+			add(path, false)
+		}
+	}
+	return out
+}
+
+// openBoards shows the board picker, with the cursor on the local board
+func (m *model) openBoards() {
+	m.mode = ViewBoards
+	m.boards = pickerBoards()
+	m.boardCursor = 0
+	for i, b := range m.boards {
+		if b.Path == m.localPath {
+			m.boardCursor = i
+		}
+	}
+}
+
+// switchLocal makes the board at path the local board and shows it
+func (m *model) switchLocal(path string) {
+	board, err := loadBoard(path)
+	if isLocked(err) {
+		m.localPath, m.hasLocal, m.showingLocal = path, true, true
+		m.promptBoard(path)
+		return
+	}
+	var corrupt *corruptBoardError
+	if err != nil && !errors.As(err, &corrupt) {
+		m.fail(fmt.Errorf(T("status.load_failed"), err))
+		return
+	}
+	recordOpen(path)
+	m.localPath = path
+	m.hasLocal = true
+	m.showingLocal = true
+	m.mode = ViewBoard
+	if corrupt != nil {
+		m.openCorrupt(corrupt)
+		return
+	}
+	useColumns(board.Columns)
+	if err := autoArchive(path, &board, m.config.ArchiveAfterDays); err != nil {
+		m.fail(fmt.Errorf(T("status.save_failed"), err))
+	}
+	autoEscalate(path, &board, m.config.EscalateAfterDays)
+	autoSchedule(path, &board, m.config.Schedules, "local")
+	m.localBoard = board
+	m.tasks = cloneTasks(board.Tasks)
+	m.projectFilter = false
+	m.project = ""
+	m.context = ""
+	m.applyBoardSettings()
+	m.selectedTask = 0
+	m.scrollOffset = 0
+	m.colScrollOffset = 0
+	m.updateHorizontalScroll()
+}
+
+func (m model) updateBoards(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Boards):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Up):
+		if m.boardCursor > 0 {
+			m.boardCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.boardCursor < len(m.boards)-1 {
+			m.boardCursor++
+		}
+
+	case key.Matches(msg, m.keys.Confirm):
+		if m.boardCursor < len(m.boards) {
+			path := m.boards[m.boardCursor].Path
+			if cwd, err := os.Getwd(); err == nil {
+				rememberBoard(cwd, path)
+			}
+			m.switchLocal(path)
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) viewBoards() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("boards.title")) + "\n")
+	if len(m.boards) == 0 {
+		b.WriteString(helpStyle.Render(T("boards.empty")) + "\n")
+	}
+
+	root := ""
+	if cwd, err := os.Getwd(); err == nil {
+		root, _ = repoRoot(cwd)
+	}
+	for i, choice := range m.boards {
+		if i == 0 && choice.Repo {
+			b.WriteString(helpStyle.Render(T("boards.repo")) + "\n")
+		}
+		if !choice.Repo && (i == 0 || m.boards[i-1].Repo) {
+			b.WriteString(helpStyle.Render(T("boards.elsewhere")) + "\n")
+		}
+		name := choice.Path
+		if rel, err := filepath.Rel(root, choice.Path); err == nil && choice.Repo {
+			name = rel
+		}
+		line := fmt.Sprintf("%-48s %s", truncate(name, 48), fmt.Sprintf(T("boards.counts"), choice.Open, choice.Opens))
+		if i == m.boardCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
+
+func cmdBoards(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("boards", flag.ExitOnError)
+	prune := fs.Bool("prune", false, "forget boards that no longer exist")
+	fs.Parse(args)
+
+	state := loadBoardState()
+	pruned := 0
+	for _, path := range knownBoards(state) {
+		entry := state.Known[path]
+		missing := ""
+		if _, err := os.Stat(path); err != nil {
+			if *prune {
+				delete(state.Known, path)
+				pruned++
+				continue
+			}
+			missing = "  (missing)"
+		}
+		fmt.Printf("%5d  %s  %s%s\n", entry.Opens, entry.LastOpened.Local().Format("2006-01-02"), path, missing)
+	}
+	if pruned > 0 {
+		if err := saveBoardState(state); err != nil {
+			return err
+		}
+		fmt.Printf("forgot %d missing board(s)\n", pruned)
+	}
+	return nil
+}
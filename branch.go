@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// branchNameLimit keeps derived branch names readable
+const branchNameLimit = 48
+
+// gitBranch is the branch checked out in the repository around the
+// current directory, or "" outside a repository or on a detached HEAD
+func gitBranch() string {
+	out, err := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return ""
+	}
+	return branch
+}
+
+// branchName derives a branch name from a task title: "Fix login bug!"
+// becomes fix-login-bug
+func branchName(task Task) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(task.Title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if len(name) > branchNameLimit {
+		name = name[:branchNameLimit]
+		if i := strings.LastIndex(name, "-"); i > branchNameLimit/2 {
+			name = name[:i]
+		}
+		name = strings.TrimSuffix(name, "-")
+	}
+	if name == "" {
+		name = "task-" + shortID(task.ID)
+	}
+	return name
+}
+
+// branchTask is the task linked to branch, preferring open ones
+func branchTask(tasks []Task, branch string) (Task, bool) {
+	var found Task
+	ok := false
+	for _, task := range tasks {
+		if branch == "" || task.Branch != branch {
+			continue
+		}
+		if !ok || (found.Completed && !task.Completed) {
+			found, ok = task, true
+		}
+	}
+	return found, ok
+}
+
+func gitRun(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func cmdBranch(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("branch", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	name := fs.String("name", "", "branch name (default: derived from the task title)")
+	link := fs.Bool("link", false, "link the current branch instead of creating one")
+	refs := parseInterspersed(fs, args)
+	if len(refs) != 1 {
+		return fmt.Errorf("usage: basket branch <id> [--name branch] [--link]")
+	}
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	i, ok := findTask(taskList.Tasks, refs[0])
+	if !ok {
+		return fmt.Errorf("no single task matches %q", refs[0])
+	}
+	task := &taskList.Tasks[i]
+
+	branch := *name
+	switch {
+	case *link:
+		if branch == "" {
+			branch = gitBranch()
+		}
+		if branch == "" {
+			return fmt.Errorf("not on a git branch")
+		}
+	case branch == "" && task.Branch != "":
+		branch = task.Branch
+	case branch == "":
+		branch = branchName(*task)
+	}
+
+	if !*link {
+		exists := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+		if exists {
+			err = gitRun("checkout", branch)
+		} else {
+			err = gitRun("checkout", "-b", branch)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if task.Branch == branch {
+		return nil
+	}
+	before := cloneTasks(taskList.Tasks)
+	task.Branch = branch
+	touchTasks(before, taskList.Tasks, time.Now())
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("linked %s to %s\n", shortID(task.ID), branch)
+	return nil
+}
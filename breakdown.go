@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const breakdownPrompt = `You split tasks into small, concrete steps. Reply with one step per line, at most 8 steps, no numbering and nothing else.`
+
+// breakdownMsg carries the steps the model proposed for a task
+type breakdownMsg struct {
+	taskID string
+	steps  []string
+	err    error
+}
+
+// askBreakdown asks the model to split the selected task into steps
+func (m *model) askBreakdown() tea.Cmd {
+	if m.llm == nil {
+		return nil
+	}
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return nil
+	}
+	task := tasksInCol[m.selectedTask]
+	m.notify(T("status.splitting"))
+
+	client := m.llm
+	return func() tea.Msg {
+		prompt := task.Title
+		if task.Description != "" {
+			prompt += "\n\n" + task.Description
+		}
+		reply, err := client.chat(breakdownPrompt, prompt)
+		if err != nil {
+			return breakdownMsg{taskID: task.ID, err: err}
+		}
+		return breakdownMsg{taskID: task.ID, steps: replyLines(reply)}
+	}
+}
+
+// handleBreakdown opens the proposed steps for review, one per line, so
+// they can be edited or dropped before they are added
+func (m model) handleBreakdown(msg breakdownMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.fail(fmt.Errorf(T("status.split_failed"), msg.err))
+		return m, nil
+	}
+	if m.mode != ViewBoard {
+		return m, nil
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == msg.taskID {
+			m.mode = ViewBreakdown
+			m.editingTask = &m.tasks[i]
+			m.textarea.Reset()
+			m.textarea.Placeholder = ""
+			m.textarea.SetHeight(min(max(len(msg.steps), 3), 10))
+			m.textarea.SetValue(strings.Join(msg.steps, "\n"))
+			m.status = ""
+			return m, m.textarea.Focus()
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateBreakdown(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = ViewBoard
+		m.editingTask = nil
+		return m, nil
+
+	case key.Matches(msg, m.keys.Save):
+		// Accepted steps become a checklist at the end of the description
+		var items []string
+		for _, step := range replyLines(m.textarea.Value()) {
+			items = append(items, "- [ ] "+step)
+		}
+		if m.editingTask != nil && len(items) > 0 {
+			desc := strings.TrimSpace(m.editingTask.Description)
+			if desc != "" {
+				desc += "\n\n"
+			}
+			m.editingTask.Description = desc + strings.Join(items, "\n")
+			m.commit(fmt.Sprintf(T("status.split"), len(items)))
+		}
+		m.mode = ViewBoard
+		m.editingTask = nil
+		return m, nil
+	}
+
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewBreakdown() string {
+	var b strings.Builder
+
+	if m.editingTask == nil {
+		return m.renderFooter()
+	}
+	b.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.editingTask.Priority.Color()).
+		Render(fmt.Sprintf(T("breakdown.title"), truncate(m.editingTask.Title, 60))) + "\n")
+	b.WriteString(helpStyle.Render(T("breakdown.hint")) + "\n\n")
+	b.WriteString(m.textarea.View() + "\n\n")
+	b.WriteString(m.renderFooter())
+	return b.String()
+}
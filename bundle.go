@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// bundleVersion is written into every bundle; readers reject newer ones
+const bundleVersion = 1
+
+// boardBundle is a whole board in one portable file: its tasks and
+// archive plus the settings needed to see it the same way elsewhere.
+// Basket has no attachments, so a bundle carries none.
+type boardBundle struct {
+	Version    int             `json:"basket_bundle"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Source     string          `json:"source"` // board path on the exporting machine
+	Board      TaskList        `json:"board"`
+	Config     json.RawMessage `json:"config,omitempty"`
+	Script     string          `json:"script,omitempty"`
+}
+
+// writeBundle bundles the board at path with the user's config and
+// script. The sync token and password, the LLM API key and the GitHub
+// and GitLab tokens are left out; they are credentials, not settings.
+func writeBundle(w io.Writer, path string, taskList TaskList) error {
+	bundle := boardBundle{
+		Version:    bundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Source:     path,
+		Board:      taskList,
+	}
+
+	if data, err := os.ReadFile(getConfigPath()); err == nil {
+		var cfg map[string]any
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("%s: %w", getConfigPath(), err)
+		}
+		if sync, ok := cfg["sync"].(map[string]any); ok {
+			delete(sync, "token")
+			delete(sync, "password")
+		}
+		if llm, ok := cfg["llm"].(map[string]any); ok {
+			delete(llm, "api_key")
+		}
+		if github, ok := cfg["github"].(map[string]any); ok {
+			delete(github, "token")
+		}
+		if gitlab, ok := cfg["gitlab"].(map[string]any); ok {
+			delete(gitlab, "token")
+		}
+		if bundle.Config, err = json.Marshal(cfg); err != nil {
+			return err
+		}
+	}
+	if data, err := os.ReadFile(getScriptPath()); err == nil {
+		bundle.Script = string(data)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
+
+// readBundle reads path as a bundle. It reports false for files that are
+// plain boards rather than bundles.
+func readBundle(path string) (boardBundle, bool, error) {
+	if isMarkdownBoard(path) {
+		return boardBundle{}, false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return boardBundle{}, false, err
+	}
+	var bundle boardBundle
+	if err := json.Unmarshal(data, &bundle); err != nil || bundle.Version == 0 {
+		return boardBundle{}, false, nil
+	}
+	if bundle.Version > bundleVersion {
+		return boardBundle{}, true, fmt.Errorf("%s was made by a newer basket (bundle version %d)", path, bundle.Version)
+	}
+	if bundle.Board.Tasks == nil {
+		bundle.Board.Tasks = []Task{}
+	}
+	return bundle, true, nil
+}
+
+// installBundleSettings puts the bundle's config and script in place,
+// never replacing ones that already exist on this machine
+func installBundleSettings(bundle boardBundle) []string {
+	var notes []string
+	install := func(path string, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		if _, err := os.Stat(path); err == nil {
+			notes = append(notes, fmt.Sprintf("kept existing %s", path))
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			notes = append(notes, err.Error())
+			return
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			notes = append(notes, err.Error())
+			return
+		}
+		notes = append(notes, fmt.Sprintf("installed %s", path))
+	}
+
+	if len(bundle.Config) > 0 {
+		var cfg any
+		json.Unmarshal(bundle.Config, &cfg)
+		data, _ := json.MarshalIndent(cfg, "", "  ")
+		install(getConfigPath(), data)
+	}
+	install(getScriptPath(), []byte(bundle.Script))
+	return notes
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// caldavBackend syncs the board with a CalDAV task list (a calendar
+// collection of VTODOs), as served by Nextcloud, Fastmail or Radicale.
+// Each task is one resource named after its ID.
+//
+// VTODO has no room for comments, assignees and the like, so the whole
+// task also rides along as JSON in X-BASKET-TASK. On pull the standard
+// fields win, since those are what other apps edit.
+type caldavBackend struct {
+	url      string // collection URL, ending in a slash
+	user     string
+	password string
+	token    string
+	client   *http.Client
+
+	// pulled is the remote state seen by the last Pull, by task ID, so
+	// Push only writes what changed
+	pulled map[string]caldavItem
+}
+
+// caldavItem is one VTODO resource on the server
+type caldavItem struct {
+	href string
+	etag string
+	task Task
+}
+
+func newCalDAVBackend(cfg *SyncConfig) *caldavBackend {
+	u := cfg.URL
+	if !strings.HasSuffix(u, "/") {
+		u += "/"
+	}
+	return &caldavBackend{
+		url:      u,
+		user:     cfg.Username,
+		password: cfg.Password,
+		token:    cfg.Token,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *caldavBackend) do(method, target string, header map[string]string, body string) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	switch {
+	case c.user != "":
+		req.SetBasicAuth(c.user, c.password)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s", method, target, resp.Status)
+	}
+	return resp, nil
+}
+
+const caldavQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><d:getetag/><c:calendar-data/></d:prop>
+  <c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VTODO"/></c:comp-filter></c:filter>
+</c:calendar-query>`
+
+// multistatus is the part of a WebDAV REPORT response basket reads
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ETag string `xml:"getetag"`
+				Data string `xml:"calendar-data"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+func (c *caldavBackend) Pull() (TaskList, error) {
+	resp, err := c.do("REPORT", c.url, map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml; charset=utf-8",
+	}, caldavQuery)
+	if err != nil {
+		return TaskList{}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return TaskList{}, err
+	}
+	var ms multistatus
+	if err := xml.Unmarshal(data, &ms); err != nil {
+		return TaskList{}, fmt.Errorf("%s: %w", c.url, err)
+	}
+
+	base, _ := url.Parse(c.url)
+	c.pulled = map[string]caldavItem{}
+	taskList := TaskList{Tasks: []Task{}}
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstat {
+			if ps.Prop.Data == "" {
+				continue
+			}
+			task, err := vtodoTask(ps.Prop.Data)
+			if err != nil {
+				logger.Warn("caldav skip", "href", r.Href, "err", err)
+				continue
+			}
+			href := r.Href
+			if ref, err := url.Parse(r.Href); err == nil {
+				href = base.ResolveReference(ref).String()
+			}
+			c.pulled[task.ID] = caldavItem{href: href, etag: ps.Prop.ETag, task: task}
+			taskList.Tasks = append(taskList.Tasks, task)
+		}
+	}
+	return taskList, nil
+}
+
+// Push writes the tasks that differ from the last pull and deletes the
+// ones that are gone. ETags make the server refuse writes over changes
+// made since, and the next sync pulls those first.
+func (c *caldavBackend) Push(taskList TaskList) error {
+	keep := map[string]bool{}
+	for _, task := range taskList.Tasks {
+		keep[task.ID] = true
+		old, ok := c.pulled[task.ID]
+		if ok && sameTask(old.task, task) {
+			continue
+		}
+		header := map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+		target := c.url + url.PathEscape(task.ID) + ".ics"
+		if ok {
+			target = old.href
+			if old.etag != "" {
+				header["If-Match"] = old.etag
+			}
+		} else {
+			header["If-None-Match"] = "*"
+		}
+		resp, err := c.do(http.MethodPut, target, header, taskVTODO(task))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	for id, old := range c.pulled {
+		if keep[id] {
+			continue
+		}
+		header := map[string]string{}
+		if old.etag != "" {
+			header["If-Match"] = old.etag
+		}
+		resp, err := c.do(http.MethodDelete, old.href, header, "")
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// icalPriority maps a level onto iCalendar's 1 (highest) to 9 (lowest)
+func icalPriority(p Priority) int {
+	if maxPriority() == 0 {
+		return 5
+	}
+	return 9 - int(p)*8/int(maxPriority())
+}
+
+// fromICalPriority is the level nearest to an iCalendar priority; 0
+// (undefined) is the default level
+func fromICalPriority(n int) Priority {
+	if n < 1 || n > 9 {
+		return defaultPriority()
+	}
+	return Priority((2*(9-n)*int(maxPriority()) + 8) / 16)
+}
+
+// taskVTODO renders task as a VCALENDAR holding one VTODO
+func taskVTODO(task Task) string {
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN", "VCALENDAR")
+	writeICalLine(&b, "VERSION", "2.0")
+	writeICalLine(&b, "PRODID", "-//basket//EN")
+	writeVTODO(&b, task)
+	writeICalLine(&b, "END", "VCALENDAR")
+	return b.String()
+}
+
+// writeVTODO appends task as a VTODO component
+func writeVTODO(b *strings.Builder, task Task) {
+	writeICalLine(b, "BEGIN", "VTODO")
+	writeICalLine(b, "UID", icalEscape(task.ID))
+	writeICalLine(b, "DTSTAMP", icalUTC(time.Now()))
+	writeICalLine(b, "CREATED", icalUTC(task.CreatedAt))
+	writeICalLine(b, "LAST-MODIFIED", icalUTC(lastModified(task)))
+	writeICalLine(b, "SUMMARY", icalEscape(task.Title))
+	if task.Description != "" {
+		writeICalLine(b, "DESCRIPTION", icalEscape(task.Description))
+	}
+	writeICalLine(b, "PRIORITY", strconv.Itoa(icalPriority(clampPriority(task.Priority))))
+	if task.Due != nil {
+		if due := task.Due.Local(); due.Equal(startOfDay(due)) {
+			writeICalLine(b, "DUE;VALUE=DATE", due.Format("20060102"))
+		} else {
+			writeICalLine(b, "DUE", icalUTC(due))
+		}
+	}
+	if task.Project != "" {
+		writeICalLine(b, "CATEGORIES", icalEscape(task.Project))
+	}
+	if task.Completed {
+		writeICalLine(b, "STATUS", "COMPLETED")
+		writeICalLine(b, "PERCENT-COMPLETE", "100")
+		if task.CompletedAt != nil {
+			writeICalLine(b, "COMPLETED", icalUTC(*task.CompletedAt))
+		}
+	} else {
+		writeICalLine(b, "STATUS", "NEEDS-ACTION")
+	}
+	if data, err := json.Marshal(task); err == nil {
+		writeICalLine(b, "X-BASKET-TASK", icalEscape(string(data)))
+	}
+	writeICalLine(b, "END", "VTODO")
+}
+
+// vtodoTask reads the first VTODO of a calendar object. Tasks created
+// elsewhere get their UID as ID.
+func vtodoTask(data string) (Task, error) {
+	roots, err := parseICal(data)
+	if err != nil {
+		return Task{}, err
+	}
+	var todo *icalComponent
+	for _, root := range roots {
+		root.walk(func(c *icalComponent) {
+			if todo == nil && c.Name == "VTODO" {
+				todo = c
+			}
+		})
+	}
+	if todo == nil {
+		return Task{}, fmt.Errorf("no VTODO")
+	}
+
+	var task Task
+	if raw := todo.text("X-BASKET-TASK"); raw != "" {
+		json.Unmarshal([]byte(raw), &task)
+	}
+	task.ID = todo.text("UID")
+	if task.ID == "" {
+		return Task{}, fmt.Errorf("VTODO without UID")
+	}
+	task.Title = todo.text("SUMMARY")
+	task.Description = todo.text("DESCRIPTION")
+	task.Project = strings.Split(todo.text("CATEGORIES"), ",")[0]
+
+	n, _ := strconv.Atoi(todo.text("PRIORITY"))
+	if p := fromICalPriority(n); icalPriority(clampPriority(task.Priority)) != n {
+		task.Priority = p
+	}
+	task.Due = nil
+	if p, ok := todo.get("DUE"); ok {
+		if t, _, err := icalTime(p); err == nil {
+			task.Due = &t
+		}
+	}
+	if p, ok := todo.get("CREATED"); ok && task.CreatedAt.IsZero() {
+		task.CreatedAt, _, _ = icalTime(p)
+	}
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = time.Now()
+	}
+	if p, ok := todo.get("LAST-MODIFIED"); ok {
+		if t, _, err := icalTime(p); err == nil && t.After(lastModified(task)) {
+			task.UpdatedAt = &t
+		}
+	}
+
+	done := strings.EqualFold(todo.text("STATUS"), "COMPLETED")
+	if done != task.Completed {
+		task.Completed = done
+		task.CompletedAt = nil
+	}
+	if p, ok := todo.get("COMPLETED"); ok && done && task.CompletedAt == nil {
+		if t, _, err := icalTime(p); err == nil {
+			task.CompletedAt = &t
+		}
+	}
+	if done && task.CompletedAt == nil {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	return task, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// runCommand dispatches a `basket <command>` invocation
+func runCommand(cfg Config, name string, args []string) error {
+	switch name {
+	case "list":
+		return cmdList(cfg, args)
+	case "add":
+		return cmdAdd(cfg, args)
+	case "done":
+		return cmdDone(cfg, args)
+	case "move":
+		return cmdMove(cfg, args)
+	case "rpc":
+		return cmdRPC(cfg, args)
+	case "serve":
+		return cmdServe(cfg, args)
+	case "export":
+		return cmdExport(cfg, args)
+	case "purge":
+		return cmdPurge(cfg, args)
+	case "backups":
+		return cmdBackups(cfg, args)
+	case "protect":
+		return cmdProtect(cfg, args)
+	case "sync":
+		return cmdSync(cfg, args)
+	case "validate":
+		return cmdValidate(cfg, args)
+	case "convert":
+		return cmdConvert(cfg, args)
+	case "import":
+		return cmdImport(cfg, args)
+	case "share":
+		return cmdShare(cfg, args)
+	case "triage":
+		return cmdTriage(cfg, args)
+	case "rename":
+		return cmdRename(cfg, args)
+	case "report":
+		return cmdReport(cfg, args)
+	case "branch":
+		return cmdBranch(cfg, args)
+	case "boards":
+		return cmdBoards(cfg, args)
+	case "init":
+		return cmdInit(cfg, args)
+	case "block":
+		return cmdBlock(cfg, args)
+	case "estimate":
+		return cmdEstimate(cfg, args)
+	case "workspace":
+		return cmdWorkspace(cfg, args)
+	case "open":
+		return cmdOpen(cfg, args)
+	case "github":
+		return cmdGitHub(cfg, args)
+	case "gitlab":
+		return cmdGitLab(cfg, args)
+	case "scan":
+		return cmdScan(cfg, args)
+	case "hook":
+		return cmdHook(cfg, args)
+	case "log":
+		return cmdLog(cfg, args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// boardFlags registers the --global/--local selectors shared by commands
+// that operate on a board
+func boardFlags(fs *flag.FlagSet) (global, local *bool) {
+	global = fs.Bool("global", false, "use the global board")
+	local = fs.Bool("local", false, "use the local board of the current directory or repository")
+	return global, local
+}
+
+// parseInterspersed parses flags that may come before or after the
+// positional arguments, as in `basket import file.json --merge`, and
+// returns the positional ones
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// resolveBoardPath picks the board a command operates on. Without an
+// explicit choice it mirrors the TUI: the local board when it has tasks,
+// otherwise the global one. The levels switch to that board's columns.
+func resolveBoardPath(global, local bool) string {
+	path := pickBoardPath(global, local)
+	if board, err := loadBoard(path); err == nil {
+		useColumns(board.Columns)
+	}
+	return path
+}
+
+func pickBoardPath(global, local bool) string {
+	localPath, hasLocal := getLocalTasksPath()
+	if local {
+		return localPath
+	}
+	if global || !hasLocal {
+		return getGlobalTasksPath()
+	}
+	if tasks, err := loadTasks(localPath); err == nil && len(tasks) == 0 {
+		return getGlobalTasksPath()
+	}
+	return localPath
+}
+
+func cmdList(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	all := fs.Bool("all", false, "include completed tasks")
+	archived := fs.Bool("archived", false, "list archived tasks instead")
+	mine := fs.Bool("mine", false, "only tasks assigned to you")
+	project := fs.String("project", "", "only tasks of this project")
+	context := fs.String("context", "", "only tasks with this @context")
+	someday := fs.Bool("someday", false, "list someday/maybe ideas instead")
+	top := fs.Int("top", 0, "only the n most urgent open tasks")
+	queryStr := fs.String("query", "", "only tasks matching a query, completed ones included")
+	fs.Parse(args)
+
+	taskList, err := loadBoard(resolveBoardPath(*global, *local))
+	if err != nil {
+		return err
+	}
+	// after loading, so priorities are the board's column names
+	query, err := parseQuery(*queryStr)
+	if err != nil {
+		return pointAtQueryError(*queryStr, err)
+	}
+	tasks := taskList.Tasks
+	if *archived {
+		tasks = taskList.Archive
+		*all = true
+	}
+	if query != nil {
+		*all = true
+	}
+	now := time.Now()
+
+	// Highest priority first, then as ordered in the column
+	sortByRank(tasks)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Priority != tasks[j].Priority {
+			return tasks[i].Priority > tasks[j].Priority
+		}
+		return cardGroup(tasks[i], false) < cardGroup(tasks[j], false)
+	})
+	if *top > 0 {
+		sortByUrgency(tasks, now)
+	}
+
+	shown := 0
+	for _, task := range tasks {
+		if *top > 0 && shown == *top {
+			break
+		}
+		if task.Completed && !*all {
+			continue
+		}
+		if task.Someday != *someday || task.Habit {
+			continue
+		}
+		if *mine && !sameAssignee(task.Assignee, currentUser(cfg)) {
+			continue
+		}
+		if *project != "" && !strings.EqualFold(task.Project, *project) {
+			continue
+		}
+		if *context != "" && !hasContext(task, *context) {
+			continue
+		}
+		if !query.Match(task, taskList.Tasks, now) {
+			continue
+		}
+		checkbox := "☐"
+		if task.Completed {
+			checkbox = "☑"
+		}
+		fmt.Fprintf(os.Stdout, "%-6s  %-8s %s %s\n", shortID(task.ID), task.Priority, checkbox, task.Title)
+		shown++
+	}
+	return nil
+}
+
+// cmdConvert switches a board between a JSON file, a YAML file and a
+// directory of Markdown files. The old copy is backed up and removed, so the board
+// keeps one source of truth.
+func cmdConvert(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	to := fs.String("to", "", "target format: json, yaml or markdown")
+	fs.Parse(args)
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+
+	base := boardBase(path)
+	var dest string
+	switch *to {
+	case "markdown", "md":
+		dest = base
+	case "json":
+		dest = base + ".json"
+	case "yaml", "yml":
+		dest = base + ".yaml"
+	default:
+		return fmt.Errorf("usage: basket convert --to json|yaml|markdown")
+	}
+	if dest == path {
+		return fmt.Errorf("%s is already in that format", path)
+	}
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	if *to == "markdown" || *to == "md" {
+		if err := os.Mkdir(dest, 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := saveBoard(dest, taskList); err != nil {
+		return err
+	}
+	if err := backupBoard(path); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	fmt.Printf("converted %s to %s\n", path, dest)
+	return nil
+}
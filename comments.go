@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Comment is a timestamped progress note on a task, kept apart from its
+// description
+type Comment struct {
+	At   time.Time `json:"at"`
+	Text string    `json:"text"`
+}
+
+// commentTimeFormat is how comment timestamps are shown
+const commentTimeFormat = "2006-01-02 15:04"
+
+// openComments shows the selected task with its comments and a box for
+// adding one
+func (m *model) openComments() tea.Cmd {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return nil
+	}
+	return m.openTask(tasksInCol[m.selectedTask].ID)
+}
+
+// openTask shows the task with the given ID in full, with its comments
+func (m *model) openTask(id string) tea.Cmd {
+	for i := range m.tasks {
+		if m.tasks[i].ID == id {
+			m.mode = ViewComments
+			m.editingTask = &m.tasks[i]
+			m.textarea.Reset()
+			m.textarea.Placeholder = T("comments.placeholder")
+			m.textarea.SetHeight(3)
+			return m.textarea.Focus()
+		}
+	}
+	return nil
+}
+
+// taskDetails lists what is known about task beyond its title and
+// description, one fact per entry
+func taskDetails(task Task, tasks []Task, now time.Time) []string {
+	details := []string{"#" + shortID(task.ID), strings.ToUpper(clampPriority(task.Priority).String())}
+	if task.Project != "" {
+		details = append(details, fmt.Sprintf(T("board.project"), task.Project))
+	}
+	if task.Assignee != "" {
+		details = append(details, fmt.Sprintf(T("detail.assignee"), task.Assignee))
+	}
+	if task.Due != nil {
+		details = append(details, fmt.Sprintf(T("detail.due"), formatDue(*task.Due, now)))
+	}
+	if task.PlannedOn != nil {
+		details = append(details, fmt.Sprintf(T("detail.planned"), formatDue(*task.PlannedOn, now)))
+	}
+	if task.Branch != "" {
+		details = append(details, fmt.Sprintf(T("board.branch"), task.Branch))
+	}
+	if task.Estimate > 0 {
+		details = append(details, fmt.Sprintf(T("detail.estimate"), formatDuration(time.Duration(task.Estimate)*time.Minute)))
+	}
+	if d := trackedTotal(task, now); d > 0 {
+		details = append(details, fmt.Sprintf(T("detail.tracked"), formatDuration(d)))
+	}
+	details = append(details, fmt.Sprintf(T("detail.created"), formatDue(startOfDay(task.CreatedAt.Local()), now)))
+	for _, blocker := range openBlockers(tasks, task) {
+		details = append(details, fmt.Sprintf(T("detail.waits_on"), blocker.Title, shortID(blocker.ID)))
+	}
+	return details
+}
+
+func (m model) updateComments(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = ViewBoard
+		m.editingTask = nil
+		return m, nil
+
+	case key.Matches(msg, m.keys.Save):
+		text := strings.TrimSpace(m.textarea.Value())
+		if m.editingTask != nil && text != "" {
+			m.editingTask.Comments = append(m.editingTask.Comments, Comment{At: time.Now(), Text: text})
+			m.commit(T("status.commented"))
+		}
+		// Stay on the thread, so several notes can be added in a row
+		m.textarea.Reset()
+		return m, nil
+	}
+
+	m.textarea, cmd = m.textarea.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewComments() string {
+	var b strings.Builder
+
+	if m.editingTask == nil {
+		return m.renderFooter()
+	}
+	task := *m.editingTask
+
+	b.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(task.Priority.Color()).
+		Render(fmt.Sprintf(T("comments.title"), truncate(task.Title, 60))) + "\n")
+	if task.Description != "" {
+		b.WriteString(helpStyle.Render(m.description(task)) + "\n")
+	}
+	b.WriteString(helpStyle.Render(strings.Join(taskDetails(task, m.tasks, time.Now()), "  ·  ")) + "\n")
+	for _, url := range taskLinks(task, m.config) {
+		b.WriteString(helpStyle.Render("↗ "+url) + "\n")
+	}
+	b.WriteString("\n")
+
+	if len(task.Comments) == 0 {
+		b.WriteString(helpStyle.Render(T("comments.empty")) + "\n")
+	}
+	for _, c := range task.Comments {
+		stamp := lipgloss.NewStyle().Foreground(colorAccent).Render(c.At.Local().Format(commentTimeFormat))
+		b.WriteString(fmt.Sprintf("%s  %s\n", stamp, c.Text))
+	}
+
+	b.WriteString("\n" + m.textarea.View() + "\n\n")
+	b.WriteString(m.renderFooter())
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// densityCompact is the density setting that draws one line per card
+const densityCompact = "compact"
+
+// tasksPerPage is how many cards a column shows before scrolling
+func (m model) tasksPerPage() int {
+	if m.compact {
+		return 24
+	}
+	return 8
+}
+
+// renderCompactTask draws a card as a single line without a border: the
+// checkbox, the title, the badges and a dot per tag, cut to width
+func (m model) renderCompactTask(task Task, isSelected bool, width int) string {
+	marker, checkbox := "  ", "☐ "
+	if isSelected {
+		marker = "▸ "
+	}
+	if task.Completed {
+		checkbox = "☑ "
+	}
+
+	titleStyle := lipgloss.NewStyle()
+	switch {
+	case isSelected:
+		titleStyle = titleStyle.Bold(true).Foreground(colorAccent)
+	case task.Completed:
+		titleStyle = titleStyle.Foreground(colorDone).Strikethrough(true)
+	}
+	title := task.Title
+	if urls := taskLinks(task, m.config); len(urls) > 0 && m.config.linksEnabled() {
+		title = hyperlink(title, urls[0])
+	}
+
+	line := marker + checkbox + titleStyle.Render(title)
+	if extras := m.cardBadges(task); len(extras) > 0 {
+		line += " " + helpStyle.Render(strings.Join(extras, " "))
+	}
+	for _, tag := range taskContexts(task) {
+		line += lipgloss.NewStyle().Foreground(tagColor(tag, m.config.TagColors)).Render("●")
+	}
+	return ansi.Truncate(line, width, "…")
+}
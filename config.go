@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user preferences loaded from the config file
+type Config struct {
+	Locale        string `json:"locale"`
+	ShowIDs       bool   `json:"show_ids"`
+	SinkCompleted bool   `json:"sink_completed"`
+	HideCompleted bool   `json:"hide_completed"`
+	RelativeTimes bool   `json:"relative_times"`
+	Density       string `json:"density"`
+	SortByUrgency bool   `json:"sort_by_urgency"`
+
+	// Theme is "light" or "dark"; unset asks the terminal
+	Theme string `json:"theme"`
+
+	// Colors is how many colors the terminal has: "truecolor", "256",
+	// "16" or "none"; unset goes by the environment
+	Colors string `json:"colors"`
+
+	// Hyperlinks makes linked card titles clickable in terminals that
+	// support OSC 8; unset keeps them on
+	Hyperlinks *bool `json:"hyperlinks"`
+
+	// Preview shows the selected task beside the board on terminals wide
+	// enough for it; unset keeps it on
+	Preview *bool `json:"preview"`
+
+	// CardDescription shows the start of the selected card's description
+	// on the card when there is no preview pane; unset keeps it on
+	CardDescription *bool `json:"card_description"`
+
+	// TitleLines wraps long card titles over up to that many lines
+	// rather than cutting them short; unset or 1 keeps one line
+	TitleLines int `json:"title_lines"`
+
+	// IssueLinks resolves issue references to links, keyed by "#" for
+	// #123, a tracker key such as "JIRA" for JIRA-456, or "GH" for
+	// GH-owner/repo#12; {ref}, {n} and {repo} are filled in
+	IssueLinks map[string]string `json:"issue_links"`
+
+	// User is who "only mine" shows tasks for; defaults to the git
+	// user.name
+	User string `json:"user"`
+
+	// ArchiveAfterDays moves tasks completed longer ago than this into
+	// the board's archive on startup; 0 disables it
+	ArchiveAfterDays int `json:"archive_after_days"`
+
+	// EscalateAfterDays bumps open tasks untouched for longer than this
+	// up one priority on startup; 0 disables it
+	EscalateAfterDays int `json:"escalate_after_days"`
+
+	// Schedules create routine tasks on startup when they come round
+	Schedules []ScheduleRule `json:"schedules"`
+
+	// BackupRetention is how many rotating backups to keep per board;
+	// unset keeps the default and 0 disables backups
+	BackupRetention *int `json:"backup_retention"`
+
+	// LockAfterMinutes locks protected boards after this long without a
+	// key press; unset locks after 10 minutes and 0 never
+	LockAfterMinutes *int `json:"lock_after_minutes"`
+
+	// Priorities replaces the five built-in levels, lowest first
+	Priorities []PriorityLevel `json:"priorities"`
+
+	// Columns overrides the name or color of single levels, keyed by
+	// level, e.g. {"lowest": {"name": "Someday"}}
+	Columns map[string]PriorityLevel `json:"columns"`
+
+	// TagColors colors tag chips, e.g. {"infra": "#60A5FA"}; other tags
+	// get a color from their name
+	TagColors map[string]string `json:"tag_colors"`
+
+	// CardAccent colors each card's left border by its first tag ("tag")
+	// or its project ("project"); ProjectColors picks the projects' colors
+	// as TagColors does the tags'
+	CardAccent    string            `json:"card_accent"`
+	ProjectColors map[string]string `json:"project_colors"`
+
+	// Keys remaps actions to other keys, e.g. {"move": ["M"]}
+	Keys map[string][]string `json:"keys"`
+
+	// Workspaces are named starting points, opened with basket workspace
+	Workspaces map[string]Workspace `json:"workspaces"`
+
+	Sync  *SyncConfig `json:"sync"`
+	Hooks HooksConfig `json:"hooks"`
+
+	// LLM enables the optional AI helpers; nothing is sent without it
+	LLM *LLMConfig `json:"llm"`
+
+	GitHub *GitHubConfig `json:"github"`
+	GitLab *GitLabConfig `json:"gitlab"`
+}
+
+func getConfigDir() string {
+	dir, err := userConfigDir()
+	if err != nil {
+		return ".basket"
+	}
+	return filepath.Join(dir, "basket")
+}
+
+func getConfigPath() string {
+	return filepath.Join(getConfigDir(), "config.json")
+}
+
+func loadConfig() (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(getConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// setConfigValue writes one top-level key of the config file, leaving the
+// rest of the file as the user wrote it; a nil value removes the key
+func setConfigValue(name string, value any) error {
+	raw := map[string]json.RawMessage{}
+	data, err := os.ReadFile(getConfigPath())
+	if err == nil {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if value == nil {
+		delete(raw, name)
+	} else {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		raw[name] = encoded
+	}
+	data, err = json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(getConfigDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(getConfigPath(), append(data, '\n'), 0644)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// contextPattern matches GTD contexts such as @home or @deep-work. The @
+// must start a word, so e-mail addresses are not contexts.
+var contextPattern = regexp.MustCompile(`(?:^|\s)@([\p{L}\p{N}_-]+)`)
+
+// taskContexts returns the contexts annotated in a task's title and
+// description, lowercased and without the @
+func taskContexts(task Task) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, text := range []string{task.Title, task.Description} {
+		for _, match := range contextPattern.FindAllStringSubmatch(text, -1) {
+			ctx := strings.ToLower(match[1])
+			if !seen[ctx] {
+				seen[ctx] = true
+				out = append(out, ctx)
+			}
+		}
+	}
+	return out
+}
+
+// hasContext reports whether task is annotated with ctx
+func hasContext(task Task, ctx string) bool {
+	ctx = strings.ToLower(strings.TrimPrefix(ctx, "@"))
+	for _, c := range taskContexts(task) {
+		if c == ctx {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeContexts counts the tasks in each context, by name
+func summarizeContexts(tasks []Task) []groupSummary {
+	byName := map[string]*groupSummary{}
+	for _, task := range tasks {
+		for _, ctx := range taskContexts(task) {
+			s, ok := byName[ctx]
+			if !ok {
+				s = &groupSummary{Name: ctx}
+				byName[ctx] = s
+			}
+			s.Total++
+			if !task.Completed {
+				s.Open++
+			}
+		}
+	}
+
+	out := make([]groupSummary, 0, len(byName))
+	for _, s := range byName {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// inContext reports whether task belongs on the board while a context is
+// active
+func (m model) inContext(task Task) bool {
+	return m.context == "" || hasContext(task, m.context)
+}
+
+// openContexts shows the context switcher, with the cursor on the active
+// context
+func (m *model) openContexts() {
+	m.mode = ViewContexts
+	m.contexts = summarizeContexts(m.tasks)
+	m.contextCursor = 0
+	for i, c := range m.contexts {
+		if c.Name == m.context {
+			m.contextCursor = i + 1
+		}
+	}
+}
+
+func (m model) updateContexts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Up):
+		if m.contextCursor > 0 {
+			m.contextCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.contextCursor < len(m.contexts) {
+			m.contextCursor++
+		}
+
+	case key.Matches(msg, m.keys.Confirm):
+		// The first entry clears the context
+		m.context = ""
+		if m.contextCursor > 0 {
+			m.context = m.contexts[m.contextCursor-1].Name
+		}
+		m.selectedTask = 0
+		m.scrollOffset = 0
+		m.mode = ViewBoard
+	}
+
+	return m, nil
+}
+
+func (m model) viewContexts() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("contexts.title")) + "\n")
+	if len(m.contexts) == 0 {
+		b.WriteString(helpStyle.Render(T("contexts.empty")) + "\n")
+	}
+
+	lines := []string{T("contexts.any")}
+	for _, c := range m.contexts {
+		lines = append(lines, fmt.Sprintf("%-24s %s", truncate("@"+c.Name, 24), fmt.Sprintf(T("projects.counts"), c.Open, c.Total)))
+	}
+	for i, line := range lines {
+		if i == m.contextCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
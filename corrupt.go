@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// corruptBoardError is returned by loadBoard when a board file exists but
+// cannot be parsed. Such a board must never be saved over, or the first
+// edit would wipe whatever is left in it.
+type corruptBoardError struct {
+	Path string
+	Line int // 1-based line of the syntax error, 0 when unknown
+	Err  error
+}
+
+func (e *corruptBoardError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s is damaged (line %d): %v", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s is damaged: %v", e.Path, e.Err)
+}
+
+// lineMsg describes a parse error without the file path
+func (e *corruptBoardError) lineMsg() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("invalid JSON at line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("invalid JSON: %v", e.Err)
+}
+
+func (e *corruptBoardError) Unwrap() error {
+	return e.Err
+}
+
+// newCorruptBoardError locates the parse error in data, so the user knows
+// where to look when fixing the file by hand
+func newCorruptBoardError(path string, data []byte, err error) *corruptBoardError {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	}
+
+	line := 0
+	if offset > 0 && offset <= int64(len(data)) {
+		line = bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+	return &corruptBoardError{Path: path, Line: line, Err: err}
+}
+
+func getCorruptDir() string {
+	return filepath.Join(getDataDir(), "corrupt")
+}
+
+// quarantineBoard copies the damaged board at path aside and returns where
+// the copy went. The original is left in place for the user to repair.
+func quarantineBoard(path string) (string, error) {
+	dest := filepath.Join(getCorruptDir(), backupKey(path)+"-"+time.Now().Format(backupTimeFormat))
+	if isMarkdownBoard(path) {
+		if err := os.CopyFS(dest, os.DirFS(path)); err != nil {
+			return "", err
+		}
+		logger.Warn("quarantine", "path", path, "copy", dest)
+		return dest, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(getCorruptDir(), 0755); err != nil {
+		return "", err
+	}
+	dest += ".json"
+	if err := os.WriteFile(dest, data, 0644); err != nil {
+		return "", err
+	}
+	logger.Warn("quarantine", "path", path, "copy", dest)
+	return dest, nil
+}
+
+// openCorrupt switches to the recovery screen for a board that failed to
+// parse, after putting a copy of it somewhere safe
+func (m *model) openCorrupt(err *corruptBoardError) {
+	m.corrupt = err
+	m.corruptCopy = ""
+	m.corruptErr = ""
+	if dest, qerr := quarantineBoard(err.Path); qerr != nil {
+		m.corruptErr = qerr.Error()
+	} else {
+		m.corruptCopy = dest
+	}
+	m.showingLocal = err.Path == m.localPath
+	m.tasks = []Task{}
+	m.mode = ViewCorrupt
+}
+
+// isCorrupt reports whether the board at path failed to load and must not
+// be written
+func (m model) isCorrupt(path string) bool {
+	return m.corrupt != nil && m.corrupt.Path == path
+}
+
+// recovered leaves the recovery screen with board loaded from the fixed
+// or restored file
+func (m *model) recovered(board TaskList) {
+	if m.showingLocal {
+		m.localBoard = board
+	} else {
+		m.globalBoard = board
+	}
+	m.tasks = cloneTasks(board.Tasks)
+	m.useBoardColumns()
+	m.corrupt = nil
+	m.selectedTask = 0
+	m.scrollOffset = 0
+	m.mode = ViewBoard
+}
+
+func (m model) updateCorrupt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Retry):
+		// The user may have fixed the file in an editor meanwhile
+		board, err := loadBoard(m.corrupt.Path)
+		var corrupt *corruptBoardError
+		if errors.As(err, &corrupt) {
+			m.corrupt = corrupt
+			m.corruptErr = ""
+			break
+		}
+		if err != nil {
+			m.corruptErr = err.Error()
+			break
+		}
+		m.recovered(board)
+		m.notify(T("status.recovered"))
+
+	case key.Matches(msg, m.keys.Backups):
+		m.openBackups()
+
+	case key.Matches(msg, m.keys.Discard):
+		if m.corruptCopy == "" {
+			// Without a copy the damaged file would be lost for good
+			break
+		}
+		if err := os.RemoveAll(m.corrupt.Path); err != nil {
+			m.corruptErr = err.Error()
+			break
+		}
+		m.recovered(TaskList{Tasks: []Task{}})
+		m.notify(fmt.Sprintf(T("status.discarded"), m.corruptCopy))
+	}
+
+	return m, nil
+}
+
+func (m model) viewCorrupt() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("corrupt.title")) + "\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(colorError).Render(m.corrupt.Error()) + "\n\n")
+	b.WriteString(T("corrupt.body") + "\n")
+	if m.corruptCopy != "" {
+		b.WriteString(helpStyle.Render(fmt.Sprintf(T("corrupt.copy"), m.corruptCopy)) + "\n")
+	}
+	if m.corruptErr != "" {
+		b.WriteString("\n" + lipgloss.NewStyle().Foreground(colorError).Render(m.corruptErr) + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
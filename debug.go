@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// logger receives debug logs. It discards everything unless --debug is
+// given.
+var logger = slog.New(slog.DiscardHandler)
+
+func getDebugLogPath() string {
+	return filepath.Join(getDataDir(), "debug.log")
+}
+
+// enableDebugLog appends JSON logs to the debug log file until the
+// returned function closes it
+func enableDebugLog() (func(), error) {
+	path := getDebugLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	logger = slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	logger.Info("start", "args", os.Args, "pid", os.Getpid())
+	return func() {
+		logger.Info("exit")
+		logger = slog.New(slog.DiscardHandler)
+		f.Close()
+	}, nil
+}
+
+// takeDebugFlag takes --debug out of args wherever it is, so it works
+// before or after a command and its flags. Arguments after a "--" are
+// left alone.
+func takeDebugFlag(args []string) ([]string, bool) {
+	var rest []string
+	debug := false
+	for i, arg := range args {
+		if arg == "--" {
+			return append(rest, args[i:]...), debug
+		}
+		if arg == "--debug" {
+			debug = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, debug
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskByID finds a task by its full ID
+func taskByID(tasks []Task, id string) (Task, bool) {
+	for _, task := range tasks {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return Task{}, false
+}
+
+// openBlockers are the tasks holding task up: those it is blocked by that
+// are on the board and not done yet
+func openBlockers(tasks []Task, task Task) []Task {
+	var out []Task
+	for _, id := range task.BlockedBy {
+		if blocker, ok := taskByID(tasks, id); ok && !blocker.Completed {
+			out = append(out, blocker)
+		}
+	}
+	return out
+}
+
+// isBlocked reports whether an open task waits on another open one
+func isBlocked(tasks []Task, task Task) bool {
+	return !task.Completed && len(openBlockers(tasks, task)) > 0
+}
+
+// dependsOn reports whether task waits on target, directly or through
+// other tasks
+func dependsOn(tasks []Task, task Task, target string, seen map[string]bool) bool {
+	for _, id := range task.BlockedBy {
+		if id == target {
+			return true
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if next, ok := taskByID(tasks, id); ok && dependsOn(tasks, next, target, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedGraph renders what holds up the open blocked tasks as trees,
+// most important task first. A task reached a second time is not expanded
+// again, so shared blockers and cycles stay readable.
+func blockedGraph(tasks []Task) []string {
+	var roots []Task
+	for _, task := range tasks {
+		if isBlocked(tasks, task) && !offBoard(task) {
+			roots = append(roots, task)
+		}
+	}
+	slices.SortStableFunc(roots, func(a, b Task) int { return int(b.Priority) - int(a.Priority) })
+
+	var lines []string
+	shown := map[string]bool{}
+	var walk func(task Task, prefix, branch string)
+	walk = func(task Task, prefix, branch string) {
+		state := "☐"
+		if task.Completed {
+			state = "☑"
+		}
+		label := lipgloss.NewStyle().Foreground(clampPriority(task.Priority).Color()).Render(strings.ToUpper(clampPriority(task.Priority).String()))
+		line := fmt.Sprintf("%s%s%s %s  %s %s", prefix, branch, state, task.Title, label, helpStyle.Render("#"+shortID(task.ID)))
+		if shown[task.ID] && len(task.BlockedBy) > 0 {
+			lines = append(lines, line+helpStyle.Render(" "+T("graph.seen")))
+			return
+		}
+		if task.Completed {
+			line = helpStyle.Render(fmt.Sprintf("%s%s%s %s  #%s", prefix, branch, state, task.Title, shortID(task.ID)))
+		}
+		lines = append(lines, line)
+		shown[task.ID] = true
+
+		switch branch {
+		case "├── ":
+			prefix += "│   "
+		case "└── ":
+			prefix += "    "
+		}
+		var children []Task
+		for _, id := range task.BlockedBy {
+			if child, ok := taskByID(tasks, id); ok {
+				children = append(children, child)
+			}
+		}
+		for i, child := range children {
+			if i == len(children)-1 {
+				walk(child, prefix, "└── ")
+			} else {
+				walk(child, prefix, "├── ")
+			}
+		}
+	}
+	for _, root := range roots {
+		if shown[root.ID] {
+			continue
+		}
+		walk(root, "", "")
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func (m *model) openGraph() {
+	m.mode = ViewGraph
+	m.graphOffset = 0
+}
+
+func (m model) updateGraph(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Graph):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Up):
+		if m.graphOffset > 0 {
+			m.graphOffset--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.graphOffset < len(blockedGraph(m.tasks))-1 {
+			m.graphOffset++
+		}
+	}
+	return m, nil
+}
+
+func (m model) viewGraph() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(T("graph.title")) + "\n")
+
+	lines := blockedGraph(m.tasks)
+	if len(lines) == 0 {
+		b.WriteString(helpStyle.Render(T("graph.empty")) + "\n")
+	}
+	height := len(lines)
+	if m.height > 0 {
+		height = max(m.height-6, 4)
+	}
+	for _, line := range lines[min(m.graphOffset, len(lines)):min(m.graphOffset+height, len(lines))] {
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
+
+// cmdBlock records that a task waits on others, or with --remove that it
+// no longer does
+func cmdBlock(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("block", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	remove := fs.Bool("remove", false, "remove the blockers instead (all of them when none are named)")
+	refs := parseInterspersed(fs, args)
+	if len(refs) < 1 || (len(refs) < 2 && !*remove) {
+		return fmt.Errorf("usage: basket block <id> <blocker-id>... [--remove]")
+	}
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	i, ok := findTask(taskList.Tasks, refs[0])
+	if !ok {
+		return fmt.Errorf("no single task matches %q", refs[0])
+	}
+	before := cloneTasks(taskList.Tasks)
+	task := &taskList.Tasks[i]
+	blockedBy := slices.Clone(task.BlockedBy)
+
+	if *remove && len(refs) == 1 {
+		blockedBy = nil
+	}
+	for _, ref := range refs[1:] {
+		j, ok := findTask(taskList.Tasks, ref)
+		if !ok {
+			return fmt.Errorf("no single task matches %q", ref)
+		}
+		blocker := taskList.Tasks[j]
+		switch {
+		case *remove:
+			blockedBy = slices.DeleteFunc(blockedBy, func(id string) bool { return id == blocker.ID })
+		case blocker.ID == task.ID:
+			return fmt.Errorf("a task cannot block itself")
+		case dependsOn(taskList.Tasks, blocker, task.ID, map[string]bool{}):
+			return fmt.Errorf("%q already waits on %q", blocker.Title, task.Title)
+		case !slices.Contains(blockedBy, blocker.ID):
+			blockedBy = append(blockedBy, blocker.ID)
+		}
+	}
+	task.BlockedBy = blockedBy
+
+	touchTasks(before, taskList.Tasks, time.Now())
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	if len(task.BlockedBy) == 0 {
+		fmt.Printf("%s is not blocked\n", shortID(task.ID))
+		return nil
+	}
+	for _, blocker := range openBlockers(taskList.Tasks, *task) {
+		fmt.Printf("%s waits on %s  %s\n", shortID(task.ID), shortID(blocker.ID), blocker.Title)
+	}
+	return nil
+}
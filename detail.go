@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// detailWidth is the width of the detail popup's text
+const detailWidth = 60
+
+// detailComments is how many of the latest comments the popup shows
+const detailComments = 3
+
+var dimStyle = lipgloss.NewStyle().Foreground(colorFaint)
+
+// openDetail shows the selected task in a popup over the board
+func (m *model) openDetail() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
+		m.detail = tasksInCol[m.selectedTask].ID
+	}
+}
+
+// updateDetail handles keys while the popup is open; it only closes, so a
+// stray key cannot edit the task underneath
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Details):
+		m.detail = ""
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// detailTask is the task in the popup, nil when the popup is closed or
+// the task is gone
+func (m model) detailTask() *Task {
+	if m.detail == "" {
+		return nil
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == m.detail {
+			return &m.tasks[i]
+		}
+	}
+	return nil
+}
+
+// renderDetail is the popup's box, empty when it is closed
+func (m model) renderDetail() string {
+	task := m.detailTask()
+	if task == nil {
+		return ""
+	}
+	body := m.detailBody(*task, detailWidth)
+	body += "\n" + helpStyle.Render(m.help.ShortHelpView(m.helpKeys().ShortHelp()))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(clampPriority(task.Priority).Color()).
+		Padding(0, 1).
+		Render(body)
+}
+
+// detailBody is everything about task, wrapped to width; the popup and
+// the preview pane show it
+func (m model) detailBody(task Task, width int) string {
+	now := time.Now()
+	text := lipgloss.NewStyle().Width(width)
+
+	var b strings.Builder
+	title := task.Title
+	if task.Completed {
+		title = "☑ " + title
+	}
+	b.WriteString(text.Bold(true).Foreground(clampPriority(task.Priority).Color()).Render(title) + "\n")
+	b.WriteString(helpStyle.Width(width).Render(strings.Join(taskDetails(task, m.tasks, now), "  ·  ")) + "\n")
+	if chips := renderTagChips(task, m.config.TagColors); chips != "" {
+		b.WriteString(chips + "\n")
+	}
+	if task.Description != "" {
+		b.WriteString("\n" + text.Render(m.description(task)) + "\n")
+	}
+	for _, url := range taskLinks(task, m.config) {
+		b.WriteString(helpStyle.Render("↗ "+truncate(url, width-2)) + "\n")
+	}
+
+	if n := len(task.Comments); n > 0 {
+		b.WriteString("\n" + helpStyle.Render(fmt.Sprintf(T("detail.comments"), n)) + "\n")
+		for _, c := range task.Comments[max(n-detailComments, 0):] {
+			stamp := lipgloss.NewStyle().Foreground(colorAccent).Render(c.At.Local().Format(commentTimeFormat))
+			b.WriteString(text.Render(stamp+"  "+c.Text) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// overlay draws fg centered on top of bg, with bg dimmed to gray so the
+// popup stands out
+func overlay(bg, fg string, width, height int) string {
+	lines := strings.Split(bg, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Strip(line)
+		width = max(width, ansi.StringWidth(lines[i]))
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	box := strings.Split(fg, "\n")
+	boxWidth := lipgloss.Width(fg)
+	top := max((len(lines)-len(box))/2, 0)
+	left := max((width-boxWidth)/2, 0)
+
+	for i, line := range lines {
+		if i < top || i >= top+len(box) {
+			lines[i] = dimStyle.Render(line)
+			continue
+		}
+		line += strings.Repeat(" ", max(left+boxWidth-ansi.StringWidth(line), 0))
+		row := box[i-top]
+		row += strings.Repeat(" ", boxWidth-ansi.StringWidth(row))
+		lines[i] = dimStyle.Render(ansi.Cut(line, 0, left)) + row + dimStyle.Render(ansi.Cut(line, left+boxWidth, width))
+	}
+	return strings.Join(lines, "\n")
+}
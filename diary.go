@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The diary is a log of the day's work, across boards: every task
+// completed, and notes added by hand. Unlike the journal, which keeps
+// edits safe until the board is written, it is kept for reading back.
+
+// diaryEntry is one line of the diary
+type diaryEntry struct {
+	At     time.Time `json:"at"`
+	Text   string    `json:"text"`
+	TaskID string    `json:"task,omitempty"` // set for a completed task
+	Board  string    `json:"board,omitempty"`
+}
+
+func getDiaryPath() string {
+	return filepath.Join(getDataDir(), "diary.jsonl")
+}
+
+// appendDiary adds entries to the diary
+func appendDiary(entries ...diaryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	path := getDiaryPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(buf)
+	return err
+}
+
+// readDiary returns the diary's entries from the start of day from up to
+// the start of day to, oldest first. Lines that do not parse are skipped.
+func readDiary(from, to time.Time) []diaryEntry {
+	f, err := os.Open(getDiaryPath())
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var entries []diaryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e diaryEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		if at := e.At.Local(); !at.Before(from) && at.Before(to) {
+			entries = append(entries, e)
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b diaryEntry) int { return a.At.Compare(b.At) })
+	return entries
+}
+
+// logCompletions writes the tasks completed between two versions of a
+// board to the diary. Reopening a task completed today takes its entry
+// back out, so a slip of the finger leaves no trace. Protected boards
+// are left out, as the diary is not sealed.
+func logCompletions(board string, before, after []Task) {
+	if isProtected(board) {
+		return
+	}
+	was := make(map[string]bool, len(before))
+	for _, task := range before {
+		was[task.ID] = task.Completed
+	}
+	var entries []diaryEntry
+	reopened := map[string]bool{}
+	for _, task := range after {
+		switch {
+		case task.Completed && !was[task.ID]:
+			at := time.Now()
+			if task.CompletedAt != nil {
+				at = *task.CompletedAt
+			}
+			entries = append(entries, diaryEntry{At: at, Text: task.Title, TaskID: task.ID, Board: board})
+		case !task.Completed && was[task.ID]:
+			reopened[task.ID] = true
+		}
+	}
+	if err := appendDiary(entries...); err != nil {
+		logger.Error("diary", "err", err)
+	}
+	if len(reopened) > 0 {
+		if err := forgetCompletions(reopened, startOfDay(time.Now())); err != nil {
+			logger.Error("diary", "err", err)
+		}
+	}
+}
+
+// forgetCompletions drops the entries for completing the given tasks
+// since the start of day from, rewriting the diary
+func forgetCompletions(ids map[string]bool, from time.Time) error {
+	data, err := os.ReadFile(getDiaryPath())
+	if err != nil {
+		return nil
+	}
+	var kept []byte
+	dropped := false
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		var e diaryEntry
+		if json.Unmarshal([]byte(line), &e) == nil && ids[e.TaskID] && !e.At.Local().Before(from) {
+			dropped = true
+			continue
+		}
+		kept = append(kept, line...)
+	}
+	if !dropped {
+		return nil
+	}
+	return os.WriteFile(getDiaryPath(), kept, 0644)
+}
+
+// diaryMarkdown renders entries as Markdown, a heading per day
+func diaryMarkdown(entries []diaryEntry) string {
+	var b strings.Builder
+	var day time.Time
+	for _, e := range entries {
+		at := e.At.Local()
+		if d := startOfDay(at); !d.Equal(day) {
+			if !day.IsZero() {
+				b.WriteString("\n")
+			}
+			day = d
+			fmt.Fprintf(&b, "## %s\n\n", d.Format("Monday, January 2, 2006"))
+		}
+		if e.TaskID != "" {
+			fmt.Fprintf(&b, "- %s Completed: %s\n", at.Format("15:04"), e.Text)
+		} else {
+			fmt.Fprintf(&b, "- %s %s\n", at.Format("15:04"), e.Text)
+		}
+	}
+	return b.String()
+}
+
+// openDiary shows today's diary
+func (m *model) openDiary() {
+	m.mode = ViewDiary
+	m.diaryDay = startOfDay(time.Now())
+	m.diaryOffset = 0
+}
+
+// diaryEntries are the entries of the day the diary view shows
+func (m model) diaryEntries() []diaryEntry {
+	return readDiary(m.diaryDay, m.diaryDay.AddDate(0, 0, 1))
+}
+
+// openLogNote prompts for a note to add to the diary, returning to the
+// screen it was opened from
+func (m *model) openLogNote() tea.Cmd {
+	m.logReturn = m.mode
+	m.mode = ViewLogNote
+	m.inputErr = ""
+	m.input.Reset()
+	m.input.Placeholder = T("diary.placeholder")
+	return m.input.Focus()
+}
+
+func (m model) updateLogNote(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = m.logReturn
+		return m, nil
+
+	case key.Matches(msg, m.keys.Confirm):
+		m.mode = m.logReturn
+		text := strings.TrimSpace(m.input.Value())
+		if text == "" {
+			return m, nil
+		}
+		if err := appendDiary(diaryEntry{At: time.Now(), Text: text}); err != nil {
+			m.fail(fmt.Errorf(T("status.log_failed"), err))
+			return m, nil
+		}
+		if m.mode == ViewDiary {
+			m.diaryDay = startOfDay(time.Now())
+		}
+		m.notify(T("status.logged"))
+		return m, nil
+	}
+
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewLogNote() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render(T("diary.note_title"))
+	return fmt.Sprintf("%s\n\n%s\n\n%s", title, m.input.View(), m.renderFooter())
+}
+
+func (m model) updateDiary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	today := startOfDay(time.Now())
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Diary):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Left):
+		m.diaryDay = m.diaryDay.AddDate(0, 0, -1)
+		m.diaryOffset = 0
+
+	case key.Matches(msg, m.keys.Right):
+		if m.diaryDay.Before(today) {
+			m.diaryDay = m.diaryDay.AddDate(0, 0, 1)
+			m.diaryOffset = 0
+		}
+
+	case key.Matches(msg, m.keys.Up):
+		if m.diaryOffset > 0 {
+			m.diaryOffset--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.diaryOffset < len(m.diaryEntries())-1 {
+			m.diaryOffset++
+		}
+
+	case key.Matches(msg, m.keys.Log):
+		return m, m.openLogNote()
+
+	case key.Matches(msg, m.keys.Share):
+		if err := clipboard.WriteAll(diaryMarkdown(m.diaryEntries())); err != nil {
+			m.fail(fmt.Errorf(T("status.share_failed"), err))
+			break
+		}
+		m.notify(T("status.diary_copied"))
+	}
+
+	return m, nil
+}
+
+func (m model) viewDiary() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf(T("diary.title"), m.diaryDay.Format(T("diary.date")))) + "\n")
+
+	entries := m.diaryEntries()
+	if len(entries) == 0 {
+		b.WriteString(helpStyle.Render(T("diary.empty")) + "\n")
+	}
+	height := 20
+	if m.height > 0 {
+		height = max(m.height-8, 4)
+	}
+	width := 72
+	if m.width > 0 {
+		width = max(m.width-12, 20)
+	}
+	done := lipgloss.NewStyle().Foreground(colorSuccess)
+	for i, e := range entries[min(m.diaryOffset, len(entries)):] {
+		if i == height {
+			b.WriteString(helpStyle.Render(fmt.Sprintf(T("matrix.more"), len(entries)-m.diaryOffset-height)) + "\n")
+			break
+		}
+		mark := "  "
+		if e.TaskID != "" {
+			mark = done.Render("✓ ")
+		}
+		b.WriteString(helpStyle.Render(e.At.Local().Format("15:04")) + "  " + mark + truncate(e.Text, width) + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
+
+// cmdLog adds a note to the diary, or prints it as Markdown:
+//
+//	basket log Paired with Ana on the release
+//	basket log --since -7d > week.md
+func cmdLog(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	day := fs.String("day", "today", "the day to print: YYYY-MM-DD, today, yesterday or -3d")
+	since := fs.String("since", "", "print every day from this one up to today instead")
+	words := parseInterspersed(fs, args)
+
+	now := time.Now()
+	if len(words) > 0 {
+		text := strings.TrimSpace(strings.Join(words, " "))
+		if err := appendDiary(diaryEntry{At: now, Text: text}); err != nil {
+			return err
+		}
+		fmt.Printf("logged at %s\n", now.Format("15:04"))
+		return nil
+	}
+
+	from, err := queryDay(*day, now)
+	if err != nil {
+		return err
+	}
+	to := from.AddDate(0, 0, 1)
+	if *since != "" {
+		if from, err = queryDay(*since, now); err != nil {
+			return err
+		}
+		to = startOfDay(now).AddDate(0, 0, 1)
+	}
+	entries := readDiary(from, to)
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "nothing logged")
+		return nil
+	}
+	_, err = fmt.Print(diaryMarkdown(entries))
+	return err
+}
@@ -0,0 +1,40 @@
+package main
+
+import "time"
+
+// startOfDay is midnight of the day holding t
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// isOverdue reports whether an open task is past its due date. A date
+// without a time is due until the end of that day.
+func isOverdue(task Task, now time.Time) bool {
+	if task.Due == nil || task.Completed {
+		return false
+	}
+	due := task.Due.Local()
+	if due.Equal(startOfDay(due)) {
+		due = due.AddDate(0, 0, 1)
+	}
+	return now.After(due)
+}
+
+// isDueToday reports whether task is due on the day holding now
+func isDueToday(task Task, now time.Time) bool {
+	return task.Due != nil && startOfDay(task.Due.Local()).Equal(startOfDay(now))
+}
+
+// formatDue renders a due date compactly: "Jan 2", with the time when it
+// is not midnight, and the year when it is not this one
+func formatDue(due, now time.Time) string {
+	due = due.Local()
+	layout := "Jan 2"
+	if due.Year() != now.Year() {
+		layout = "Jan 2 2006"
+	}
+	if !due.Equal(startOfDay(due)) {
+		layout += " 15:04"
+	}
+	return due.Format(layout)
+}
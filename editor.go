@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// locationPattern is a reference to a place in a file, file:line with
+// an optional :column, as basket scan and most compilers write them
+var locationPattern = regexp.MustCompile(`^(.+?):(\d+)(?::\d+)?$`)
+
+// codeLocation is a line of a file a task points at
+type codeLocation struct {
+	Path string // absolute
+	Line int
+}
+
+// taskLocation finds the first file:line in a task's description or
+// title naming a file that exists. Relative paths are tried against the
+// repository holding the board, then the board's directory, then the
+// current repository and directory.
+func taskLocation(task Task, boardPath string) (codeLocation, bool) {
+	var bases []string
+	for _, dir := range []string{filepath.Dir(boardPath), "."} {
+		if abs, err := filepath.Abs(dir); err == nil {
+			if root, ok := repoRoot(abs); ok {
+				bases = append(bases, root)
+			}
+			bases = append(bases, abs)
+		}
+	}
+	for _, text := range []string{task.Description, task.Title} {
+		for _, field := range strings.Fields(text) {
+			field = strings.Trim(field, "`'\"()[]<>,;")
+			m := locationPattern.FindStringSubmatch(field)
+			if m == nil || strings.Contains(m[1], "://") {
+				continue
+			}
+			line, _ := strconv.Atoi(m[2])
+			path := filepath.FromSlash(m[1])
+			if strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+				if home, err := os.UserHomeDir(); err == nil {
+					path = filepath.Join(home, path[2:])
+				}
+			}
+			candidates := []string{path}
+			if !filepath.IsAbs(path) {
+				candidates = nil
+				for _, base := range bases {
+					candidates = append(candidates, filepath.Join(base, path))
+				}
+			}
+			for _, c := range candidates {
+				if info, err := os.Stat(c); err == nil && info.Mode().IsRegular() {
+					return codeLocation{Path: c, Line: max(line, 1)}, true
+				}
+			}
+		}
+	}
+	return codeLocation{}, false
+}
+
+// editorCommand is the command opening loc in $VISUAL or $EDITOR (vi if
+// neither is set). Editors differ in how they take a line: most read
+// +line before the file, some file:line.
+func editorCommand(loc codeLocation) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	switch name {
+	case "code", "code-insiders", "codium", "cursor":
+		args = append(args, "--goto", fmt.Sprintf("%s:%d", loc.Path, loc.Line))
+	case "subl", "hx", "helix", "micro", "zed":
+		args = append(args, fmt.Sprintf("%s:%d", loc.Path, loc.Line))
+	default:
+		args = append(args, "+"+strconv.Itoa(loc.Line), loc.Path)
+	}
+	return exec.Command(args[0], args[1:]...)
+}
+
+// editorDoneMsg reports the editor closing
+type editorDoneMsg struct{ err error }
+
+// openSelectedLocation hands the terminal to the editor at the file and
+// line the selected task points at, taking it back when the editor quits
+func (m *model) openSelectedLocation() tea.Cmd {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return nil
+	}
+	loc, ok := taskLocation(tasksInCol[m.selectedTask], m.currentPath())
+	if !ok {
+		m.fail(errors.New(T("status.no_location")))
+		return nil
+	}
+	return tea.ExecProcess(editorCommand(loc), func(err error) tea.Msg {
+		return editorDoneMsg{err}
+	})
+}
@@ -0,0 +1,44 @@
+package main
+
+import "time"
+
+// escalateStale bumps open tasks untouched for more than days up one
+// level, so forgotten items resurface instead of rotting at the bottom.
+// Escalating counts as a change, so a task left alone keeps climbing one
+// level per period. It reports whether the board changed.
+func escalateStale(taskList *TaskList, days int, now time.Time) bool {
+	cutoff := now.AddDate(0, 0, -days)
+	changed := false
+	for i := range taskList.Tasks {
+		task := &taskList.Tasks[i]
+		if task.Completed || offBoard(*task) || task.Priority >= maxPriority() {
+			continue
+		}
+		if !lastModified(*task).Before(cutoff) {
+			continue
+		}
+		stamp := now
+		task.Priority++
+		task.EscalatedAt = &stamp
+		task.UpdatedAt = &stamp
+		changed = true
+	}
+	return changed
+}
+
+// autoEscalate applies the escalate_after_days setting to a freshly loaded
+// board and persists the result. A zero setting disables it.
+func autoEscalate(path string, taskList *TaskList, days int) {
+	if days <= 0 {
+		return
+	}
+	if escalateStale(taskList, days, time.Now()) {
+		saveBoard(path, *taskList)
+	}
+}
+
+// isEscalated reports whether task was bumped by escalation and has not
+// been touched since
+func isEscalated(task Task) bool {
+	return task.EscalatedAt != nil && !lastModified(task).After(*task.EscalatedAt)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// cmdEstimate sets how long a task is expected to take
+func cmdEstimate(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("estimate", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	refs := parseInterspersed(fs, args)
+	if len(refs) != 2 {
+		return fmt.Errorf("usage: basket estimate <id> <duration, e.g. 90m or 2h, or none>")
+	}
+
+	minutes := 0
+	if refs[1] != "none" && refs[1] != "0" {
+		d, err := parseAge(refs[1])
+		if err != nil {
+			return err
+		}
+		minutes = int(d.Round(time.Minute).Minutes())
+	}
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	i, ok := findTask(taskList.Tasks, refs[0])
+	if !ok {
+		return fmt.Errorf("no single task matches %q", refs[0])
+	}
+	before := cloneTasks(taskList.Tasks)
+	taskList.Tasks[i].Estimate = minutes
+
+	touchTasks(before, taskList.Tasks, time.Now())
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	if minutes == 0 {
+		fmt.Printf("%s has no estimate\n", shortID(taskList.Tasks[i].ID))
+	} else {
+		fmt.Printf("%s estimated at %s\n", shortID(taskList.Tasks[i].ID), formatDuration(time.Duration(minutes)*time.Minute))
+	}
+	return nil
+}
+
+// estimateRow compares the estimated and the tracked time of a task, a
+// group of tasks or a week
+type estimateRow struct {
+	Label     string  `json:"label"`
+	Tasks     int     `json:"tasks"`
+	Estimated float64 `json:"estimated_minutes"`
+	Actual    float64 `json:"actual_minutes"`
+	Ratio     float64 `json:"ratio"` // actual over estimated; above 1 took longer
+}
+
+func (r *estimateRow) add(task Task, actual time.Duration) {
+	r.Tasks++
+	r.Estimated += float64(task.Estimate)
+	r.Actual += actual.Round(time.Minute).Minutes()
+	if r.Estimated > 0 {
+		r.Ratio = r.Actual / r.Estimated
+	}
+}
+
+// cmdReportEstimates compares estimates with the time tracked on the
+// tasks completed since a cutoff, per task, tag or project, and week by
+// week, to show how far off estimates run
+func cmdReportEstimates(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("report estimates", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	since := fs.String("since", "90d", "only tasks completed within this long")
+	by := fs.String("by", "task", "group by task, tag or project")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	fs.Parse(args)
+
+	age, err := parseAge(*since)
+	if err != nil {
+		return err
+	}
+	if *by != "task" && *by != "tag" && *by != "project" {
+		return fmt.Errorf("--by must be task, tag or project")
+	}
+	taskList, err := loadBoard(resolveBoardPath(*global, *local))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	from := now.Add(-age)
+
+	groups := map[string]*estimateRow{}
+	weeks := map[string]*estimateRow{}
+	var total estimateRow
+	for _, task := range append(append([]Task{}, taskList.Tasks...), taskList.Archive...) {
+		if !task.Completed || task.CompletedAt == nil || task.CompletedAt.Before(from) || task.Estimate == 0 {
+			continue
+		}
+		actual := trackedTotal(task, now)
+		if actual == 0 {
+			continue
+		}
+
+		var keys []string
+		switch *by {
+		case "task":
+			keys = []string{shortID(task.ID) + "  " + task.Title}
+		case "tag":
+			for _, ctx := range taskContexts(task) {
+				keys = append(keys, "@"+ctx)
+			}
+			if len(keys) == 0 {
+				keys = []string{"(no tag)"}
+			}
+		case "project":
+			keys = []string{projectLabel(task.Project)}
+		}
+		for _, k := range keys {
+			if groups[k] == nil {
+				groups[k] = &estimateRow{Label: k}
+			}
+			groups[k].add(task, actual)
+		}
+		week := startOfWeek(task.CompletedAt.Local()).Format("2006-01-02")
+		if weeks[week] == nil {
+			weeks[week] = &estimateRow{Label: week}
+		}
+		weeks[week].add(task, actual)
+		total.add(task, actual)
+	}
+
+	rows := sortedEstimateRows(groups)
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Ratio > rows[j].Ratio })
+	byWeek := sortedEstimateRows(weeks)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"since": from.Format(time.RFC3339),
+			"rows":  rows,
+			"weeks": byWeek,
+			"total": total,
+		})
+	}
+	if total.Tasks == 0 {
+		fmt.Println("no completed task since then has both an estimate and tracked time")
+		return nil
+	}
+
+	printRow := func(r estimateRow) {
+		fmt.Printf("%8s  %8s  %5.2fx  %s\n",
+			formatDuration(time.Duration(r.Estimated)*time.Minute),
+			formatDuration(time.Duration(r.Actual)*time.Minute),
+			r.Ratio, r.Label)
+	}
+	fmt.Printf("%8s  %8s  %6s\n", "estimate", "actual", "ratio")
+	for _, r := range rows {
+		printRow(r)
+	}
+	fmt.Println("\nby week completed")
+	for _, r := range byWeek {
+		printRow(r)
+	}
+	total.Label = fmt.Sprintf("total, %d task(s) since %s", total.Tasks, from.Local().Format("2006-01-02"))
+	fmt.Println()
+	printRow(total)
+	fmt.Printf("\ntasks took %.0f%% of their estimate\n", total.Ratio*100)
+	return nil
+}
+
+// sortedEstimateRows lists rows by label
+func sortedEstimateRows(rows map[string]*estimateRow) []estimateRow {
+	out := make([]estimateRow, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Label < out[j].Label })
+	return out
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Snapshot layout, in pixels
+const (
+	snapPadding      = 24
+	snapHeaderHeight = 48
+	snapColWidth     = 240
+	snapColGap       = 16
+	snapColHeader    = 40
+	snapCardHeight   = 32
+	snapCardGap      = 8
+	snapTitleChars   = 28
+)
+
+// Colors for parts of the snapshot the terminal leaves to its own theme
+const (
+	snapBackground = "#111827"
+	snapCardBorder = "#374151"
+	snapText       = "#E5E7EB"
+	snapMuted      = "#6B7280"
+)
+
+// snapColumn is one priority column of a board snapshot
+type snapColumn struct {
+	Name  string
+	Color string
+	Tasks []Task
+}
+
+// boardSnapshot is a renderer-independent description of the board
+type boardSnapshot struct {
+	Title   string
+	Accent  string
+	Columns []snapColumn
+}
+
+func buildSnapshot(title string, tasks []Task) boardSnapshot {
+	snap := boardSnapshot{
+		Title:  title,
+		Accent: colorHex(titleStyle.GetForeground()),
+	}
+	for _, p := range allPriorities() {
+		col := snapColumn{Name: p.String(), Color: colorHex(p.Color())}
+		for _, task := range tasks {
+			if clampPriority(task.Priority) == p && !offBoard(task) {
+				col.Tasks = append(col.Tasks, task)
+			}
+		}
+		snap.Columns = append(snap.Columns, col)
+	}
+	return snap
+}
+
+// size returns the pixel dimensions of the rendered snapshot
+func (s boardSnapshot) size() (int, int) {
+	most := 0
+	for _, col := range s.Columns {
+		if len(col.Tasks) > most {
+			most = len(col.Tasks)
+		}
+	}
+	if most == 0 {
+		most = 1
+	}
+	width := snapPadding*2 + len(s.Columns)*snapColWidth + (len(s.Columns)-1)*snapColGap
+	height := snapPadding*2 + snapHeaderHeight + snapColHeader + most*(snapCardHeight+snapCardGap) + snapCardGap
+	return width, height
+}
+
+// colorHex extracts the hex value from a lipgloss color, taking the
+// variant of an adaptive color that suits the terminal's background
+func colorHex(c lipgloss.TerminalColor) string {
+	switch c := c.(type) {
+	case lipgloss.Color:
+		return string(c)
+	case lipgloss.CompleteColor:
+		return c.TrueColor
+	case lipgloss.AdaptiveColor:
+		if lipgloss.HasDarkBackground() {
+			return c.Dark
+		}
+		return c.Light
+	case lipgloss.CompleteAdaptiveColor:
+		if lipgloss.HasDarkBackground() {
+			return c.Dark.TrueColor
+		}
+		return c.Light.TrueColor
+	}
+	return snapText
+}
+
+func parseHex(hex string) color.RGBA {
+	v, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return color.RGBA{0xE5, 0xE7, 0xEB, 0xFF}
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xFF}
+}
+
+func cmdExport(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	format := fs.String("format", "svg", "output format: svg, png, html, bundle, ics or csv")
+	output := fs.String("o", "", "output file (default stdout)")
+	fs.Parse(args)
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	tasks := taskList.Tasks
+
+	source := T("header.global")
+	if path != getGlobalTasksPath() {
+		source = T("header.local")
+	}
+	snap := buildSnapshot(strings.TrimSpace(fmt.Sprintf(T("header.title"), source)), tasks)
+
+	// Render in full before creating the output, so a failed export
+	// leaves no empty or partial file behind
+	var buf bytes.Buffer
+	switch *format {
+	case "svg":
+		err = renderSVG(&buf, snap)
+	case "png":
+		err = renderPNG(&buf, snap)
+	case "html":
+		err = renderHTML(&buf, snap)
+	case "bundle":
+		err = writeBundle(&buf, path, taskList)
+	case "ics":
+		err = writeTasksICS(&buf, exportableTasks(tasks))
+	case "csv":
+		err = writeRemindersCSV(&buf, exportableTasks(tasks))
+	default:
+		err = fmt.Errorf("unknown export format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = buf.WriteTo(os.Stdout)
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0644)
+}
+
+func renderSVG(w io.Writer, s boardSnapshot) error {
+	width, height := s.size()
+	var b strings.Builder
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="13">`+"\n", width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", snapBackground)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s" font-size="18" font-weight="bold">%s</text>`+"\n",
+		snapPadding, snapPadding+20, s.Accent, html.EscapeString(s.Title))
+
+	colHeight := height - snapPadding*2 - snapHeaderHeight
+	for i, col := range s.Columns {
+		x := snapPadding + i*(snapColWidth+snapColGap)
+		y := snapPadding + snapHeaderHeight
+
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="8" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+			x, y, snapColWidth, colHeight, col.Color)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s" font-weight="bold" text-anchor="middle">%s (%d)</text>`+"\n",
+			x+snapColWidth/2, y+26, col.Color, html.EscapeString(col.Name), len(col.Tasks))
+
+		for j, task := range col.Tasks {
+			cy := y + snapColHeader + j*(snapCardHeight+snapCardGap)
+			textColor, decoration, checkbox := snapText, "", "☐"
+			if task.Completed {
+				textColor, decoration, checkbox = snapMuted, ` text-decoration="line-through"`, "☑"
+			}
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s"/>`+"\n",
+				x+12, cy, snapColWidth-24, snapCardHeight, snapCardBorder)
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s"%s>%s %s</text>`+"\n",
+				x+20, cy+21, textColor, decoration, checkbox, html.EscapeString(truncate(task.Title, snapTitleChars)))
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderPNG(w io.Writer, s boardSnapshot) error {
+	width, height := s.size()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{parseHex(snapBackground)}, image.Point{}, draw.Src)
+
+	face := basicfont.Face7x13
+	text := func(x, y int, str string, c color.RGBA) {
+		d := font.Drawer{Dst: img, Src: &image.Uniform{c}, Face: face, Dot: fixed.P(x, y)}
+		d.DrawString(str)
+	}
+
+	text(snapPadding, snapPadding+20, asciiOnly(s.Title), parseHex(s.Accent))
+
+	colHeight := height - snapPadding*2 - snapHeaderHeight
+	for i, col := range s.Columns {
+		x := snapPadding + i*(snapColWidth+snapColGap)
+		y := snapPadding + snapHeaderHeight
+		colColor := parseHex(col.Color)
+
+		strokeRect(img, image.Rect(x, y, x+snapColWidth, y+colHeight), colColor, 2)
+		header := fmt.Sprintf("%s (%d)", asciiOnly(col.Name), len(col.Tasks))
+		text(x+(snapColWidth-font.MeasureString(face, header).Ceil())/2, y+26, header, colColor)
+
+		for j, task := range col.Tasks {
+			cy := y + snapColHeader + j*(snapCardHeight+snapCardGap)
+			card := image.Rect(x+12, cy, x+snapColWidth-12, cy+snapCardHeight)
+			strokeRect(img, card, parseHex(snapCardBorder), 1)
+
+			textColor := parseHex(snapText)
+			if task.Completed {
+				textColor = parseHex(snapMuted)
+			}
+
+			// Checkbox drawn as a square since the bitmap font is ASCII only
+			box := image.Rect(x+20, cy+11, x+30, cy+21)
+			strokeRect(img, box, textColor, 1)
+			if task.Completed {
+				draw.Draw(img, box.Inset(2), &image.Uniform{textColor}, image.Point{}, draw.Src)
+			}
+
+			title := asciiOnly(truncate(task.Title, snapTitleChars))
+			text(x+36, cy+20, title, textColor)
+			if task.Completed {
+				tw := font.MeasureString(face, title).Ceil()
+				draw.Draw(img, image.Rect(x+36, cy+16, x+36+tw, cy+17), &image.Uniform{textColor}, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("board").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { margin: 0; padding: 24px; background: {{.Background}}; color: {{.Text}}; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 14px; }
+  h1 { margin: 0 0 4px; font-size: 20px; color: {{.Accent}}; }
+  .generated { color: {{.Muted}}; margin-bottom: 24px; }
+  .board { display: flex; gap: 16px; align-items: flex-start; overflow-x: auto; }
+  .column { flex: 1 1 0; min-width: 220px; border: 2px solid; border-radius: 8px; padding: 12px; }
+  .column h2 { margin: 0 0 12px; font-size: 14px; text-align: center; }
+  .card { border: 1px solid {{.CardBorder}}; padding: 8px; margin-bottom: 8px; word-wrap: break-word; }
+  .card.done { color: {{.Muted}}; }
+  .card.done .title { text-decoration: line-through; }
+  .card details { margin-top: 6px; color: {{.Muted}}; white-space: pre-wrap; }
+  .card .comment { margin-top: 4px; }
+  .card .stamp, .card .assignee { color: {{.Accent}}; }
+  .empty { color: {{.Muted}}; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="generated">{{.Generated}}</div>
+<div class="board">
+{{- range .Columns}}
+  <section class="column" style="border-color: {{.Color}}">
+    <h2 style="color: {{.Color}}">{{.Name}} ({{.Done}}/{{len .Tasks}})</h2>
+    {{- range .Tasks}}
+    <div class="card{{if .Completed}} done{{end}}">
+      <span class="title">{{if .Completed}}☑{{else}}☐{{end}} {{.Title}}</span>
+      {{- if .Assignee}} <span class="assignee">@{{.Assignee}}</span>{{end}}
+      {{- if .Due}} <span class="stamp">📅 {{.Due.Local.Format "2006-01-02"}}</span>{{end}}
+      {{- if or .Description .Comments}}
+      <details><summary>Details</summary>{{.Description}}
+        {{- range .Comments}}
+        <div class="comment"><span class="stamp">{{.At.Local.Format "2006-01-02 15:04"}}</span> {{.Text}}</div>
+        {{- end}}
+      </details>
+      {{- end}}
+    </div>
+    {{- else}}
+    <div class="empty">{{$.Empty}}</div>
+    {{- end}}
+  </section>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// renderHTML writes a standalone, read-only page of the board
+func renderHTML(w io.Writer, s boardSnapshot) error {
+	type column struct {
+		snapColumn
+		Done int
+	}
+	data := struct {
+		Title, Generated, Empty                     string
+		Accent, Background, Text, Muted, CardBorder htmltemplate.CSS
+		Columns                                     []column
+	}{
+		Title:      s.Title,
+		Generated:  time.Now().Format("2006-01-02 15:04"),
+		Empty:      T("board.empty"),
+		Accent:     htmltemplate.CSS(s.Accent),
+		Background: snapBackground,
+		Text:       snapText,
+		Muted:      snapMuted,
+		CardBorder: snapCardBorder,
+	}
+	for _, col := range s.Columns {
+		c := column{snapColumn: col}
+		for _, task := range col.Tasks {
+			if task.Completed {
+				c.Done++
+			}
+		}
+		data.Columns = append(data.Columns, c)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+func strokeRect(img *image.RGBA, r image.Rectangle, c color.RGBA, width int) {
+	u := &image.Uniform{c}
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+width, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Max.X-width, r.Min.Y, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+}
+
+// asciiOnly prepares s for the ASCII-only bitmap font: symbols and emoji
+// (with their joiners and variation selectors) are dropped, other
+// non-ASCII characters, such as accented or CJK letters, become '?'
+func asciiOnly(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 0x20 && r <= 0x7e:
+			return r
+		case unicode.IsSymbol(r), unicode.Is(unicode.Variation_Selector, r), r == '\u200d':
+			return -1
+		default:
+			return '?'
+		}
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// feedEntries is how many completed tasks a feed lists, newest first
+const feedEntries = 50
+
+// atomFeed is an Atom feed of completed tasks, as served on /feed
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Links      []atomLink     `xml:"link"`
+	Content    *atomContent   `xml:"content,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// feedLabel is the short name a board goes by in feeds: "global", or the
+// directory a local board is in
+func feedLabel(label string) string {
+	if label == "global" {
+		return label
+	}
+	return filepath.Base(filepath.Dir(label))
+}
+
+// feedBoard picks the board a feed was asked for out of boards, by
+// label, path or the directory it is in
+func feedBoard(boards map[string]string, name string) (map[string]string, bool) {
+	for label, path := range boards {
+		if label == name || path == name || feedLabel(label) == name {
+			return map[string]string{label: path}, true
+		}
+	}
+	return nil, false
+}
+
+// writeFeed writes the tasks of boards completed last as an Atom feed,
+// archived ones included. Sealed descriptions are left out.
+func writeFeed(w io.Writer, boards map[string]string, self string, cfg Config, now time.Time) error {
+	type done struct {
+		board string
+		task  Task
+	}
+	var tasks []done
+	for _, label := range slices.Sorted(maps.Keys(boards)) {
+		board, err := loadBoard(boards[label])
+		if err != nil {
+			logger.Error("feed", "board", boards[label], "err", err)
+			continue
+		}
+		for _, task := range append(board.Tasks, board.Archive...) {
+			if task.Completed && task.CompletedAt != nil {
+				tasks = append(tasks, done{feedLabel(label), task})
+			}
+		}
+	}
+	slices.SortFunc(tasks, func(a, b done) int {
+		return cmp.Compare(b.task.CompletedAt.UnixNano(), a.task.CompletedAt.UnixNano())
+	})
+	if len(tasks) > feedEntries {
+		tasks = tasks[:feedEntries]
+	}
+
+	title := "basket: completed tasks"
+	if len(boards) == 1 {
+		for label := range boards {
+			title += " on " + feedLabel(label)
+		}
+	}
+	feed := atomFeed{
+		ID:      self,
+		Title:   title,
+		Updated: now.UTC().Format(time.RFC3339),
+		Author:  atomPerson{Name: "basket"},
+		Links:   []atomLink{{Rel: "self", Href: self}},
+	}
+	if len(tasks) > 0 {
+		feed.Updated = tasks[0].task.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	for _, d := range tasks {
+		task := d.task
+		entry := atomEntry{
+			// A task completed again after being reopened is a new entry
+			ID:         fmt.Sprintf("urn:basket:%s:%d", task.ID, task.CompletedAt.Unix()),
+			Title:      task.Title,
+			Updated:    task.CompletedAt.UTC().Format(time.RFC3339),
+			Categories: []atomCategory{{Term: d.board}},
+		}
+		if task.Assignee != "" {
+			entry.Author = &atomPerson{Name: task.Assignee}
+		}
+		if task.Project != "" {
+			entry.Categories = append(entry.Categories, atomCategory{Term: task.Project})
+		}
+		for _, tag := range taskContexts(task) {
+			entry.Categories = append(entry.Categories, atomCategory{Term: "@" + tag})
+		}
+		for _, url := range taskLinks(task, cfg) {
+			entry.Links = append(entry.Links, atomLink{Href: url})
+		}
+		if task.Description != "" && !isSealed(task.Description) {
+			entry.Content = &atomContent{Type: "text", Body: task.Description}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// hookMarker tells a commit-msg hook basket installed from anyone else's
+const hookMarker = "# installed by basket hook install"
+
+// commitHookScript is the commit-msg hook, calling back into basket (the
+// one on the PATH, else the one that installed it)
+func commitHookScript(self string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+basket=$(command -v basket || echo %q)
+exec "$basket" hook commit-msg "$1"
+`, hookMarker, self)
+}
+
+// trailerPattern is a git trailer line such as Signed-off-by: ...
+var trailerPattern = regexp.MustCompile(`^[\w-]+: `)
+
+// scissors is the line below which git drops the message, as written by
+// git commit --verbose
+const scissors = "# ------------------------ >8 ------------------------"
+
+// addTrailer adds line to a commit message as a trailer: after the last
+// line of text, ahead of git's comments, and in the block of trailers
+// already there if there is one
+func addTrailer(msg, line string) string {
+	lines := strings.Split(msg, "\n")
+	end := len(lines)
+	for i, l := range lines {
+		if l == scissors {
+			end = i
+			break
+		}
+	}
+	last := -1
+	for i := 0; i < end; i++ {
+		if l := strings.TrimSpace(lines[i]); l != "" && !strings.HasPrefix(l, "#") {
+			last = i
+		}
+	}
+	// The last paragraph is a block of trailers if every line is one,
+	// unless it is the subject
+	start := last
+	for start > 0 && trailerPattern.MatchString(lines[start]) {
+		start--
+	}
+	insert := []string{"", line}
+	if last >= 0 && start < last && start > 0 && strings.TrimSpace(lines[start]) == "" {
+		insert = []string{line}
+	}
+	out := append([]string{}, lines[:last+1]...)
+	out = append(out, insert...)
+	return strings.Join(append(out, lines[last+1:]...), "\n")
+}
+
+// commitText is a commit message without git's comments, to tell whether
+// there is anything to add to
+func commitText(msg string) string {
+	var text []string
+	for _, l := range strings.Split(msg, "\n") {
+		if l == scissors {
+			break
+		}
+		if !strings.HasPrefix(l, "#") {
+			text = append(text, l)
+		}
+	}
+	return strings.TrimSpace(strings.Join(text, "\n"))
+}
+
+// askTTY asks a question on the terminal, since git gives hooks no
+// stdin; ok is false when there is no terminal to ask on
+func askTTY(question string) (string, bool) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", false
+	}
+	defer tty.Close()
+	fmt.Fprint(tty, question)
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(answer)), true
+}
+
+func cmdHook(cfg Config, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "install":
+			return hookInstall(args[1:])
+		case "commit-msg":
+			if len(args) == 2 {
+				return hookCommitMsg(cfg, args[1])
+			}
+		}
+	}
+	return fmt.Errorf("usage: basket hook install [--force]")
+}
+
+// hookInstall puts the commit-msg hook in the repository's hooks
+// directory, which git may have moved (core.hooksPath, worktrees)
+func hookInstall(args []string) error {
+	fs := flag.NewFlagSet("hook install", flag.ExitOnError)
+	force := fs.Bool("force", false, "replace a commit-msg hook basket did not install")
+	fs.Parse(args)
+
+	out, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return fmt.Errorf("not in a git repository")
+	}
+	dir := strings.TrimSpace(string(out))
+	path := filepath.Join(dir, "commit-msg")
+	if data, err := os.ReadFile(path); err == nil && !strings.Contains(string(data), hookMarker) && !*force {
+		return fmt.Errorf("%s exists; pass --force to replace it", path)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		self = "basket"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(commitHookScript(self)), 0o755); err != nil {
+		return err
+	}
+	fmt.Printf("installed %s\n", path)
+	return nil
+}
+
+// hookCommitMsg runs as the commit-msg hook. When the branch is linked to
+// an open task it offers to reference the task in the message, or to
+// complete it as well; without a terminal to ask on it references it.
+// It never fails the commit over basket's own troubles.
+func hookCommitMsg(cfg Config, msgFile string) error {
+	branch := gitBranch()
+	if branch == "" {
+		return nil
+	}
+	data, err := os.ReadFile(msgFile)
+	if err != nil {
+		return nil
+	}
+	msg := string(data)
+	text := commitText(msg)
+	if text == "" || strings.HasPrefix(text, "Merge ") || strings.HasPrefix(text, "fixup! ") || strings.HasPrefix(text, "squash! ") {
+		return nil
+	}
+
+	var path string
+	var taskList TaskList
+	var task Task
+	found := false
+	var paths []string
+	if local, ok := getLocalTasksPath(); ok {
+		paths = append(paths, local)
+	}
+	for _, p := range append(paths, getGlobalTasksPath()) {
+		board, err := loadBoard(p)
+		if err != nil {
+			continue
+		}
+		if t, ok := branchTask(board.Tasks, branch); ok && !t.Completed {
+			path, taskList, task, found = p, board, t, true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+	id := shortID(task.ID)
+	if strings.Contains(text, id) {
+		return nil
+	}
+
+	answer, asked := askTTY(fmt.Sprintf("basket: branch %s is task %s %q. Reference it, complete it too, or skip? [R/c/s] ", branch, id, task.Title))
+	trailer := "Refs: " + id
+	switch {
+	case !asked, answer == "", answer == "r":
+	case answer == "c":
+		trailer = "Closes: " + id
+	default:
+		return nil
+	}
+	if err := os.WriteFile(msgFile, []byte(addTrailer(msg, trailer)), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "basket: %v\n", err)
+		return nil
+	}
+
+	if strings.HasPrefix(trailer, "Closes: ") {
+		before := cloneTasks(taskList.Tasks)
+		for i := range taskList.Tasks {
+			if taskList.Tasks[i].ID == task.ID {
+				setCompleted(&taskList.Tasks[i], true)
+			}
+		}
+		touchTasks(before, taskList.Tasks, time.Now())
+		if err := saveBoard(path, taskList); err != nil {
+			fmt.Fprintf(os.Stderr, "basket: %v\n", err)
+			return nil
+		}
+		afterSave(cfg, path, before, taskList.Tasks)
+		fmt.Fprintf(os.Stderr, "basket: completed %s\n", id)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// GitHubConfig is how basket reaches GitHub. The token falls back to
+// GITHUB_TOKEN and then to the GitHub CLI's login.
+type GitHubConfig struct {
+	Token    string `json:"token"`
+	URL      string `json:"url"`      // the API, for GitHub Enterprise
+	Priority string `json:"priority"` // column for review requests, "high" by default
+	Project  string `json:"project"`
+}
+
+const defaultGitHubAPI = "https://api.github.com"
+
+// reviewTitlePattern finds the pull request a review task is for, in
+// the title it was given
+var reviewTitlePattern = regexp.MustCompile(`^Review (GH-[\w.-]+/[\w.-]+#\d+)\b`)
+
+// githubPull is a pull request as the search API lists it
+type githubPull struct {
+	Title         string    `json:"title"`
+	Number        int       `json:"number"`
+	HTMLURL       string    `json:"html_url"`
+	RepositoryURL string    `json:"repository_url"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	User          struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// ref is the GH-owner/repo#12 reference to the pull request
+func (p githubPull) ref() string {
+	// https://api.github.com/repos/owner/repo
+	parts := strings.Split(p.RepositoryURL, "/")
+	repo := strings.Join(parts[max(0, len(parts)-2):], "/")
+	return fmt.Sprintf("GH-%s#%d", repo, p.Number)
+}
+
+// githubToken is the configured token, GITHUB_TOKEN, or the GitHub CLI's
+func githubToken(cfg *GitHubConfig) string {
+	if cfg != nil && cfg.Token != "" {
+		return cfg.Token
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	if out, err := exec.Command("gh", "auth", "token").Output(); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return ""
+}
+
+// reviewRequests lists the open pull requests waiting on your review
+func reviewRequests(cfg *GitHubConfig) ([]githubPull, error) {
+	token := githubToken(cfg)
+	if token == "" {
+		return nil, fmt.Errorf("no GitHub token: set github.token in %s, GITHUB_TOKEN, or log in with gh", getConfigPath())
+	}
+	api := defaultGitHubAPI
+	if cfg != nil && cfg.URL != "" {
+		api = strings.TrimRight(cfg.URL, "/")
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	var pulls []githubPull
+	for page := 1; ; page++ {
+		q := url.Values{
+			"q":        {"is:pr is:open archived:false review-requested:@me"},
+			"per_page": {"100"},
+			"page":     {fmt.Sprint(page)},
+		}
+		req, err := http.NewRequest(http.MethodGet, api+"/search/issues?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Items   []githubPull `json:"items"`
+			Message string       `json:"message"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("github: %s %s", resp.Status, result.Message)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("github: %w", err)
+		}
+		pulls = append(pulls, result.Items...)
+		if len(result.Items) < 100 {
+			return pulls, nil
+		}
+	}
+}
+
+// reviewTitle is the title of the task for reviewing p
+func reviewTitle(p githubPull) string {
+	return fmt.Sprintf("Review %s: %s", p.ref(), p.Title)
+}
+
+// syncReviews brings the review tasks among tasks in line with pulls:
+// new requests get a task, and those whose request is gone (the review
+// was submitted, or the pull request closed) are completed. A task done
+// before the pull request last changed is reopened, as the review was
+// asked for again. It returns what it did, for printing.
+func syncReviews(tasks []Task, pulls []githubPull, priority Priority, project string, now time.Time) ([]Task, []string) {
+	var changes []string
+	requested := map[string]githubPull{}
+	for _, p := range pulls {
+		requested[p.ref()] = p
+	}
+	seen := map[string]bool{}
+	for i := range tasks {
+		m := reviewTitlePattern.FindStringSubmatch(tasks[i].Title)
+		if m == nil {
+			continue
+		}
+		ref := m[1]
+		p, ok := requested[ref]
+		seen[ref] = true
+		switch {
+		case !ok && !tasks[i].Completed:
+			setCompleted(&tasks[i], true)
+			changes = append(changes, "done     "+tasks[i].Title)
+		case ok && tasks[i].Completed && tasks[i].CompletedAt != nil && p.UpdatedAt.After(*tasks[i].CompletedAt):
+			setCompleted(&tasks[i], false)
+			tasks[i].Title = reviewTitle(p)
+			changes = append(changes, "reopened "+tasks[i].Title)
+		case ok && !tasks[i].Completed && tasks[i].Title != reviewTitle(p):
+			tasks[i].Title = reviewTitle(p)
+			changes = append(changes, "renamed  "+tasks[i].Title)
+		}
+	}
+	for _, p := range pulls {
+		if seen[p.ref()] {
+			continue
+		}
+		tasks = append(tasks, Task{
+			ID:          generateID(),
+			Title:       reviewTitle(p),
+			Description: fmt.Sprintf("%s\n\nOpened by %s.", p.HTMLURL, p.User.Login),
+			Priority:    priority,
+			Project:     project,
+			CreatedAt:   now,
+		})
+		changes = append(changes, "added    "+reviewTitle(p))
+	}
+	return tasks, changes
+}
+
+// reviewPriority is the column review tasks go in: the configured one,
+// else the one called "high", else the second highest
+func reviewPriority(cfg *GitHubConfig) (Priority, error) {
+	name := "high"
+	if cfg != nil && cfg.Priority != "" {
+		name = cfg.Priority
+	}
+	if p, ok := findColumn(name); ok {
+		return p, nil
+	}
+	if cfg != nil && cfg.Priority != "" {
+		return 0, fmt.Errorf("no column %q", cfg.Priority)
+	}
+	return clampPriority(maxPriority() - 1), nil
+}
+
+func cmdGitHub(cfg Config, args []string) error {
+	if len(args) == 0 || args[0] != "reviews" {
+		return fmt.Errorf("usage: basket github reviews [--dry-run]")
+	}
+	fs := flag.NewFlagSet("github reviews", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "show what would change without touching the board")
+	fs.Parse(args[1:])
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	priority, err := reviewPriority(cfg.GitHub)
+	if err != nil {
+		return err
+	}
+	pulls, err := reviewRequests(cfg.GitHub)
+	if err != nil {
+		return err
+	}
+	project := ""
+	if cfg.GitHub != nil {
+		project = cfg.GitHub.Project
+	}
+
+	before := cloneTasks(taskList.Tasks)
+	now := time.Now()
+	var changes []string
+	taskList.Tasks, changes = syncReviews(taskList.Tasks, pulls, priority, project, now)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("%d review request(s), %s is up to date\n", len(pulls), path)
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("would make %d change(s) to %s\n", len(changes), path)
+		return nil
+	}
+	touchTasks(before, taskList.Tasks, now)
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("%d review request(s), made %d change(s) to %s\n", len(pulls), len(changes), path)
+	return nil
+}
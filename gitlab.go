@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// GitLabConfig holds the GitLab token, which stays with you; which issues
+// a board mirrors is up to the board, in its settings
+type GitLabConfig struct {
+	Token string `json:"token"`
+	URL   string `json:"url"` // the API, when it is not https://<host>/api/v4
+}
+
+// GitLabSettings pick the issues a board mirrors, from the project the
+// repository's remote points at
+type GitLabSettings struct {
+	Remote   string   `json:"remote,omitempty"`   // "origin" unless set
+	Assignee string   `json:"assignee,omitempty"` // a username, or "me"
+	Labels   []string `json:"labels,omitempty"`   // issues with all of these
+}
+
+// gitlabIssue is an issue as the API has it
+type gitlabIssue struct {
+	IID    int      `json:"iid"`
+	Title  string   `json:"title"`
+	WebURL string   `json:"web_url"`
+	Labels []string `json:"labels"`
+	DueOn  string   `json:"due_date"` // 2006-01-02
+}
+
+// gitlabProject is where a repository's issues live
+type gitlabProject struct {
+	API  string // e.g. https://gitlab.com/api/v4
+	Path string // group/project
+}
+
+// remotePattern reads the host and path out of a git remote URL, in the
+// scp-like form git@host:group/project.git or as a URL
+var remotePattern = regexp.MustCompile(`^(?:[\w+.-]+://)?(?:[^@/]+@)?([^:/]+)(?::\d+)?[:/](.+?)(?:\.git)?/?$`)
+
+// parseRemote finds the GitLab project behind a git remote URL
+func parseRemote(remote string) (gitlabProject, error) {
+	m := remotePattern.FindStringSubmatch(strings.TrimSpace(remote))
+	if m == nil {
+		return gitlabProject{}, fmt.Errorf("cannot read the remote %q", remote)
+	}
+	return gitlabProject{API: "https://" + m[1] + "/api/v4", Path: m[2]}, nil
+}
+
+// issuesURL is where the project's issues are, with the given path and
+// query after it
+func (p gitlabProject) issuesURL(rest string, q url.Values) string {
+	u := p.API + "/projects/" + url.PathEscape(p.Path) + "/issues" + rest
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	return u
+}
+
+// gitlabClient calls the API of one project with a token
+type gitlabClient struct {
+	project gitlabProject
+	token   string
+	http    *http.Client
+}
+
+func (c gitlabClient) do(method, u string, body any, out any) (http.Header, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return nil, fmt.Errorf("gitlab: %s %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("gitlab: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// openIssues lists the project's open issues the settings pick
+func (c gitlabClient) openIssues(settings GitLabSettings) ([]gitlabIssue, error) {
+	q := url.Values{"state": {"opened"}, "per_page": {"100"}}
+	switch settings.Assignee {
+	case "":
+	case "me":
+		q.Set("scope", "assigned_to_me")
+	default:
+		q.Set("assignee_username", settings.Assignee)
+	}
+	if len(settings.Labels) > 0 {
+		q.Set("labels", strings.Join(settings.Labels, ","))
+	}
+
+	var issues []gitlabIssue
+	for page := "1"; page != ""; {
+		q.Set("page", page)
+		var batch []gitlabIssue
+		header, err := c.do(http.MethodGet, c.project.issuesURL("", q), nil, &batch)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, batch...)
+		page = header.Get("X-Next-Page")
+	}
+	return issues, nil
+}
+
+// closeIssue closes the issue numbered iid
+func (c gitlabClient) closeIssue(iid int) error {
+	_, err := c.do(http.MethodPut, c.project.issuesURL(fmt.Sprintf("/%d", iid), nil), map[string]string{"state_event": "close"}, nil)
+	return err
+}
+
+// issueTitle is the title of the task mirroring issue
+func issueTitle(issue gitlabIssue) string {
+	return fmt.Sprintf("#%d %s", issue.IID, issue.Title)
+}
+
+// issueOf is the URL of the issue a task mirrors, kept on the first line
+// of its description, and its number; ok is false for other tasks
+func issueOf(task Task, project gitlabProject) (string, int, bool) {
+	first, _, _ := strings.Cut(task.Description, "\n")
+	first = strings.TrimSpace(first)
+	_, n, ok := strings.Cut(first, "/"+project.Path+"/-/issues/")
+	if !ok {
+		return "", 0, false
+	}
+	var iid int
+	if _, err := fmt.Sscanf(n, "%d", &iid); err != nil {
+		return "", 0, false
+	}
+	return first, iid, true
+}
+
+// gitlabRemote is the URL of the repository's remote called name
+func gitlabRemote(name string) (string, error) {
+	out, err := exec.Command("git", "remote", "get-url", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("no git remote %q here", name)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func cmdGitLab(cfg Config, args []string) error {
+	if len(args) == 0 || args[0] != "sync" {
+		return fmt.Errorf("usage: basket gitlab sync [--assignee name|me] [--labels a,b] [--dry-run]")
+	}
+	fs := flag.NewFlagSet("gitlab sync", flag.ExitOnError)
+	assignee := fs.String("assignee", "", "only issues assigned to this user, or to me")
+	labels := fs.String("labels", "", "only issues with all of these labels, comma separated")
+	dryRun := fs.Bool("dry-run", false, "show what would change without touching the board or GitLab")
+	fs.Parse(args[1:])
+
+	path := resolveBoardPath(false, true)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	settings := GitLabSettings{}
+	if s := settingsOf(taskList); s.GitLab != nil {
+		settings = *s.GitLab
+	}
+	if *assignee != "" {
+		settings.Assignee = *assignee
+	}
+	if *labels != "" {
+		settings.Labels = strings.Split(*labels, ",")
+	}
+	if settings.Remote == "" {
+		settings.Remote = "origin"
+	}
+
+	remote, err := gitlabRemote(settings.Remote)
+	if err != nil {
+		return err
+	}
+	project, err := parseRemote(remote)
+	if err != nil {
+		return err
+	}
+	token := os.Getenv("GITLAB_TOKEN")
+	if cfg.GitLab != nil {
+		if cfg.GitLab.Token != "" {
+			token = cfg.GitLab.Token
+		}
+		if cfg.GitLab.URL != "" {
+			project.API = strings.TrimRight(cfg.GitLab.URL, "/")
+		}
+	}
+	if token == "" {
+		return fmt.Errorf("no GitLab token: set gitlab.token in %s or GITLAB_TOKEN", getConfigPath())
+	}
+	client := gitlabClient{project: project, token: token, http: &http.Client{Timeout: 30 * time.Second}}
+
+	issues, err := client.openIssues(settings)
+	if err != nil {
+		return err
+	}
+	open := map[int]gitlabIssue{}
+	for _, issue := range issues {
+		open[issue.IID] = issue
+	}
+
+	before := cloneTasks(taskList.Tasks)
+	now := time.Now()
+	changes := 0
+	note := func(verb, title string) {
+		fmt.Printf("%-8s %s\n", verb, title)
+		changes++
+	}
+	mirrored := map[int]bool{}
+	for i := range taskList.Tasks {
+		task := &taskList.Tasks[i]
+		_, iid, ok := issueOf(*task, project)
+		if !ok {
+			continue
+		}
+		mirrored[iid] = true
+		issue, isOpen := open[iid]
+		switch {
+		case task.Completed && isOpen:
+			// Done in basket: close it on GitLab too
+			if !*dryRun {
+				if err := client.closeIssue(iid); err != nil {
+					return err
+				}
+			}
+			note("closed", task.Title)
+		case !task.Completed && !isOpen:
+			setCompleted(task, true)
+			note("done", task.Title)
+		case isOpen && task.Title != issueTitle(issue):
+			task.Title = issueTitle(issue)
+			note("renamed", task.Title)
+		}
+	}
+	for _, issue := range issues {
+		if mirrored[issue.IID] {
+			continue
+		}
+		task := Task{
+			ID:          generateID(),
+			Title:       issueTitle(issue),
+			Description: issue.WebURL,
+			Priority:    settingsOf(taskList).defaultColumn(),
+			CreatedAt:   now,
+		}
+		var tags []string
+		for _, label := range issue.Labels {
+			if tag := strings.Join(strings.Fields(label), "-"); tag != "" {
+				tags = append(tags, "@"+tag)
+			}
+		}
+		if len(tags) > 0 {
+			task.Description += "\n\n" + strings.Join(tags, " ")
+		}
+		if due, err := time.ParseInLocation("2006-01-02", issue.DueOn, time.Local); err == nil {
+			task.Due = &due
+		}
+		taskList.Tasks = append(taskList.Tasks, task)
+		note("added", task.Title)
+	}
+
+	if changes == 0 {
+		fmt.Printf("%d open issue(s) in %s, %s is up to date\n", len(issues), project.Path, path)
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("would make %d change(s)\n", changes)
+		return nil
+	}
+	touchTasks(before, taskList.Tasks, now)
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("%d open issue(s) in %s, made %d change(s) to %s\n", len(issues), project.Path, changes, path)
+	return nil
+}
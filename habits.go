@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// habitDayFormat is how days are recorded in a habit's log
+const habitDayFormat = "2006-01-02"
+
+// habitStripDays is how many days the habit strip shows
+const habitStripDays = 7
+
+// offBoard reports whether task lives outside the kanban columns: an idea
+// parked in someday/maybe, or a habit
+func offBoard(task Task) bool {
+	return task.Someday || task.Habit
+}
+
+// habitDoneOn reports whether the habit was done on the day holding t
+func habitDoneOn(task Task, t time.Time) bool {
+	return slices.Contains(task.HabitLog, t.Format(habitDayFormat))
+}
+
+// habitStreak counts the days in a row the habit was done, up to today.
+// An unchecked today does not break the streak yet.
+func habitStreak(task Task, now time.Time) int {
+	day := startOfDay(now)
+	if !habitDoneOn(task, day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for habitDoneOn(task, day) {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// habits lists the board's habits, in creation order
+func (m model) habits() []Task {
+	var habits []Task
+	for _, task := range m.tasks {
+		if task.Habit {
+			habits = append(habits, task)
+		}
+	}
+	return habits
+}
+
+func (m *model) openHabits() {
+	m.mode = ViewHabits
+	m.habitCursor = 0
+}
+
+// toggleHabitToday checks the habit under the cursor off for today, or
+// unchecks it
+func (m *model) toggleHabitToday() {
+	items := m.habits()
+	if m.habitCursor >= len(items) {
+		return
+	}
+	today := time.Now().Format(habitDayFormat)
+	for i := range m.tasks {
+		if m.tasks[i].ID != items[m.habitCursor].ID {
+			continue
+		}
+		// Copy the log, since the saved board shares it
+		log := slices.DeleteFunc(slices.Clone(m.tasks[i].HabitLog), func(d string) bool { return d == today })
+		if len(log) == len(m.tasks[i].HabitLog) {
+			log = append(log, today)
+			slices.Sort(log)
+			m.tasks[i].HabitLog = log
+			m.commit(fmt.Sprintf(T("status.habit_done"), habitStreak(m.tasks[i], time.Now())))
+		} else {
+			m.tasks[i].HabitLog = log
+			m.commit(T("status.habit_undone"))
+		}
+		return
+	}
+}
+
+func (m model) updateHabits(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.habits()
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Habits):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Up):
+		if m.habitCursor > 0 {
+			m.habitCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.habitCursor < len(items)-1 {
+			m.habitCursor++
+		}
+
+	case key.Matches(msg, m.keys.Toggle):
+		m.toggleHabitToday()
+
+	case key.Matches(msg, m.keys.New):
+		m.mode = ViewAdd
+		m.addingSomeday = false
+		m.addingHabit = true
+		m.textarea.Reset()
+		m.textarea.Placeholder = T("habits.placeholder")
+		m.textarea.SetHeight(1)
+		return m, m.textarea.Focus()
+
+	case key.Matches(msg, m.keys.Delete):
+		if m.habitCursor >= len(items) {
+			break
+		}
+		for i := range m.tasks {
+			if m.tasks[i].ID == items[m.habitCursor].ID {
+				m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+				m.commit(T("status.deleted"))
+				break
+			}
+		}
+		if m.habitCursor > 0 && m.habitCursor >= len(items)-1 {
+			m.habitCursor--
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) viewHabits() string {
+	var b strings.Builder
+	now := time.Now()
+
+	b.WriteString(titleStyle.Render(T("habits.title")) + "\n")
+
+	items := m.habits()
+	if len(items) == 0 {
+		b.WriteString(helpStyle.Render(T("habits.empty")) + "\n")
+	}
+
+	// Weekday initials over the strip, oldest day first
+	var head []string
+	for d := habitStripDays - 1; d >= 0; d-- {
+		head = append(head, string([]rune(T("habits.weekdays"))[now.AddDate(0, 0, -d).Weekday()]))
+	}
+	if len(items) > 0 {
+		b.WriteString(fmt.Sprintf("  %-32s %s\n", "", helpStyle.Render(strings.Join(head, " "))))
+	}
+
+	done := lipgloss.NewStyle().Foreground(colorSuccess)
+	for i, habit := range items {
+		var strip []string
+		for d := habitStripDays - 1; d >= 0; d-- {
+			if habitDoneOn(habit, now.AddDate(0, 0, -d)) {
+				strip = append(strip, done.Render("●"))
+			} else {
+				strip = append(strip, helpStyle.Render("○"))
+			}
+		}
+		name := fmt.Sprintf("%-32s", truncate(habit.Title, 32))
+		if i == m.habitCursor {
+			name = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + name)
+		} else {
+			name = "  " + name
+		}
+		streak := ""
+		if n := habitStreak(habit, now); n > 0 {
+			streak = fmt.Sprintf("  🔥 %d", n)
+		}
+		b.WriteString(name + " " + strings.Join(strip, " ") + streak + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// HooksConfig maps task events to shell commands. Each command receives
+// the task as JSON on stdin.
+type HooksConfig struct {
+	OnAdd      string `json:"on_add"`
+	OnComplete string `json:"on_complete"`
+	OnDelete   string `json:"on_delete"`
+
+	// Webhooks are posted task events, after the commands
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// Task events
+const (
+	EventAdd      = "add"
+	EventComplete = "complete"
+	EventDelete   = "delete"
+)
+
+// taskEvent is something that happened to a task on a board
+type taskEvent struct {
+	Name  string
+	Task  Task
+	Board string // path of the board file
+}
+
+// taskEvents derives the events between two versions of a board
+func taskEvents(board string, old, new []Task) []taskEvent {
+	before := make(map[string]Task, len(old))
+	for _, task := range old {
+		before[task.ID] = task
+	}
+
+	var events []taskEvent
+	seen := make(map[string]bool, len(new))
+	for _, task := range new {
+		seen[task.ID] = true
+		prev, existed := before[task.ID]
+		switch {
+		case !existed:
+			events = append(events, taskEvent{Name: EventAdd, Task: task, Board: board})
+			if task.Completed {
+				events = append(events, taskEvent{Name: EventComplete, Task: task, Board: board})
+			}
+		case task.Completed && !prev.Completed:
+			events = append(events, taskEvent{Name: EventComplete, Task: task, Board: board})
+		}
+	}
+	for _, task := range old {
+		if !seen[task.ID] {
+			events = append(events, taskEvent{Name: EventDelete, Task: task, Board: board})
+		}
+	}
+	return events
+}
+
+func (h HooksConfig) command(event string) string {
+	switch event {
+	case EventAdd:
+		return h.OnAdd
+	case EventComplete:
+		return h.OnComplete
+	case EventDelete:
+		return h.OnDelete
+	default:
+		return ""
+	}
+}
+
+// runHook runs the configured command for ev, if any
+func runHook(hooks HooksConfig, ev taskEvent) error {
+	command := hooks.command(ev.Name)
+	if command == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(ev.Task)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"BASKET_EVENT="+ev.Name,
+		"BASKET_BOARD="+ev.Board,
+		"BASKET_TASK_ID="+ev.Task.ID,
+	)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("on_%s hook: %s", ev.Name, msg)
+	}
+	return nil
+}
+
+// hookResultMsg reports hooks that failed
+type hookResultMsg struct {
+	errs []error
+}
+
+// hooksCmd runs the hooks for events recorded since the last key press.
+// Hooks run in order but off the UI goroutine, so a slow script never
+// freezes the board.
+func (m *model) hooksCmd() tea.Cmd {
+	if len(m.pendingEvents) == 0 {
+		return nil
+	}
+	events := m.pendingEvents
+	m.pendingEvents = nil
+	hooks := m.config.Hooks
+
+	return func() tea.Msg {
+		return hookResultMsg{errs: runHooks(hooks, events)}
+	}
+}
+
+// runHooks runs the commands and webhooks for events, in order, and
+// returns what failed
+func runHooks(hooks HooksConfig, events []taskEvent) []error {
+	var errs []error
+	for _, ev := range events {
+		logger.Debug("hook", "event", ev.Name, "task", ev.Task.ID, "board", ev.Board)
+		if err := runHook(hooks, ev); err != nil {
+			errs = append(errs, err)
+		}
+		for _, h := range hooks.Webhooks {
+			if err := runWebhook(h, ev); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs
+}
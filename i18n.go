@@ -0,0 +1,606 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// catalogs holds the built-in message catalogs keyed by language code
+var catalogs = map[string]map[string]string{
+	"en": {
+		"priority.lowest":  "LOWEST",
+		"priority.low":     "LOW",
+		"priority.medium":  "MEDIUM",
+		"priority.high":    "HIGH",
+		"priority.highest": "HIGHEST",
+
+		"header.title":  "  🧺 BASKET  %s  ",
+		"header.global": "🌍 GLOBAL",
+		"header.local":  "📂 LOCAL",
+
+		"board.column_count": "%s (%d/%d)",
+		"board.hidden_done":  "(+%d done)",
+		"card.ago":           "%s ago",
+		"card.due_in":        "due in %s",
+		"card.due_ago":       "due %s ago",
+		"card.due_today":     "due today",
+		"board.filter":       "filter: %s",
+		"board.query":        "query: %s",
+		"legend.title":       "tags",
+		"legend.empty":       "no tags yet; write @tag in a task",
+		"preview.empty":      "no task selected",
+		"board.workspace":    "workspace %s",
+		"board.only_mine":    "only %s",
+		"board.by_urgency":   "⏱ by urgency",
+		"board.wip":          "WIP %d/%d",
+		"board.hidden_cols":  "(%d columns hidden)",
+		"board.branch":       "⎇ %s",
+		"board.project":      "project: %s",
+		"board.someday":      "💭 %d someday",
+		"board.empty":        "No tasks",
+		"board.more_above":   "    ▲ more above",
+		"board.more_below":   "    ▼ more below",
+
+		"add.title":         "📝 ADD TASK TO %s",
+		"add.placeholder":   "Enter task title...",
+		"add.someday_title": "💭 ADD TO SOMEDAY/MAYBE",
+		"add.habit_title":   "🌱 NEW HABIT",
+
+		"edit.title":       "✏️  EDIT TASK",
+		"edit.task_title":  "✏️  %s",
+		"edit.placeholder": "Enter task description...",
+
+		"comments.title":       "💬 %s",
+		"comments.empty":       "No comments yet",
+		"comments.placeholder": "Add a progress note...",
+
+		"detail.assignee": "→ %s",
+		"detail.due":      "due %s",
+		"detail.comments": "%d comment(s), latest:",
+		"detail.planned":  "planned %s",
+		"detail.estimate": "estimated %s",
+		"detail.tracked":  "%s tracked",
+		"detail.created":  "created %s",
+		"detail.waits_on": "waits on %s (#%s)",
+
+		"breakdown.title": "🪄 BREAK DOWN %s",
+		"breakdown.hint":  "Suggested steps, one per line. Edit or delete lines, then save to add them as a checklist.",
+
+		"assign.title":       "👤 ASSIGN TASK",
+		"assign.task_title":  "👤 ASSIGN %s",
+		"assign.placeholder": "Name, empty to unassign",
+
+		"projects.title":  "📁 PROJECTS",
+		"projects.all":    "All projects",
+		"projects.none":   "No project",
+		"projects.counts": "%d open / %d",
+
+		"someday.title":   "💭 SOMEDAY/MAYBE",
+		"someday.empty":   "Nothing parked here. Press z on a card to park it, or n to jot down an idea",
+		"someday.promote": "Promote to:",
+
+		"habits.title":       "🌱 HABITS",
+		"habits.empty":       "No habits yet. Press n to start one",
+		"habits.placeholder": "Habit, e.g. Read 20 minutes",
+		"habits.weekdays":    "SMTWTFS",
+
+		"links.title": "🔗 OPEN LINK",
+
+		"boards.title":     "📋 BOARDS",
+		"boards.empty":     "No local boards yet",
+		"boards.repo":      "This repository",
+		"boards.elsewhere": "Elsewhere",
+		"boards.counts":    "%d open · opened %d×",
+
+		"week.title":     "🗓  WEEK OF %s",
+		"week.unplanned": "Unplanned",
+		"week.days":      "Mon Tue Wed Thu Fri Sat Sun",
+		"week.date":      "Jan 2",
+
+		"diary.title":       "📓 DIARY · %s",
+		"diary.date":        "Mon Jan 2, 2006",
+		"diary.empty":       "Nothing logged this day. Press i to add a note",
+		"diary.placeholder": "What did you do?",
+		"diary.note_title":  "📓 LOG A NOTE",
+
+		"graph.title": "⛓  BLOCKED BY",
+		"graph.empty": "Nothing is blocked. Link tasks with basket block <id> <blocker-id>",
+		"graph.seen":  "(see above)",
+
+		"matrix.title":         "🧭 EISENHOWER MATRIX",
+		"matrix.urgent":        "URGENT",
+		"matrix.not_urgent":    "NOT URGENT",
+		"matrix.do":            "Do",
+		"matrix.do_hint":       "urgent and important",
+		"matrix.schedule":      "Schedule",
+		"matrix.schedule_hint": "important, not urgent",
+		"matrix.delegate":      "Delegate",
+		"matrix.delegate_hint": "urgent, not important",
+		"matrix.drop":          "Drop",
+		"matrix.drop_hint":     "neither",
+		"matrix.more":          "  … %d more",
+
+		"contexts.title": "📍 CONTEXTS",
+		"contexts.any":   "Any context",
+		"contexts.empty": "Add @home, @errands... to task titles to use contexts",
+
+		"project.title":       "📁 SET PROJECT",
+		"project.task_title":  "📁 PROJECT OF %s",
+		"project.placeholder": "Project name, empty for none",
+
+		"goto.title":       "🔎 GO TO TASK",
+		"goto.placeholder": "Task ID or short ID",
+		"goto.not_found":   "No single task matches %q",
+
+		"query.title":       "🔍 FILTER BY QUERY",
+		"query.placeholder": "priority>=high and @infra and due<7d and not completed",
+		"query.hint":        "Leave empty to show every task",
+
+		"passphrase.title":             "🔒 PASSPHRASE",
+		"passphrase.placeholder":       "Passphrase for sensitive descriptions",
+		"passphrase.hint":              "Kept until basket quits; BASKET_PASSPHRASE sets it up front",
+		"passphrase.wrong":             "Wrong passphrase: it does not open the board's sealed descriptions",
+		"passphrase.board_placeholder": "Passphrase for this protected board",
+		"passphrase.board_hint":        "Enter opens the board, esc quits basket",
+		"passphrase.board_wrong":       "Wrong passphrase",
+		"sensitive.locked":             "🔒 Sensitive; press e to unlock",
+
+		"backups.title":   "🗄  BACKUPS",
+		"backups.empty":   "No backups of this board yet",
+		"backups.tasks":   "%d tasks",
+		"backups.preview": "Preview",
+
+		"corrupt.title": "⚠  DAMAGED BOARD",
+		"corrupt.body":  "Basket will not save over this file. Fix it in an editor and retry, restore a backup, or start the board over.",
+		"corrupt.copy":  "A copy of the damaged file was saved to %s",
+
+		"sync.synced":  "☁ synced",
+		"sync.pending": "⟳ syncing",
+		"sync.error":   "⚠ sync error",
+		"sync.offline": "⚡ offline",
+
+		"status.added":        "Task added to %s",
+		"status.saved":        "Task saved",
+		"status.sealed":       "Description sealed",
+		"status.unsealed":     "Description no longer sealed",
+		"status.completed":    "Task completed",
+		"status.reopened":     "Task reopened",
+		"status.deleted":      "Task deleted",
+		"status.moved":        "Moved to %s",
+		"status.reordered":    "Reordered",
+		"status.pinned":       "Pinned to the top",
+		"status.unpinned":     "Unpinned",
+		"status.rank_urgency": "Columns are sorted by urgency; press O to order them by hand",
+		"status.ran":          "Ran %s",
+		"status.save_failed":  "Could not save: %v",
+		"status.load_failed":  "Could not load board: %v",
+		"status.shared":       "Task copied to clipboard",
+		"status.share_failed": "Could not copy to clipboard: %v",
+		"status.recovered":    "Board loaded",
+		"status.discarded":    "Started over, the damaged file is kept at %s",
+		"status.splitting":    "Asking the model for steps...",
+		"status.split_failed": "Could not break the task down: %v",
+		"status.split":        "Added %d checklist items",
+		"status.timing":       "Timer started",
+		"status.habit_added":  "Habit added",
+		"status.habit_done":   "Done today, %d day streak",
+		"status.habit_undone": "Unchecked for today",
+		"status.logged":       "Logged",
+		"status.log_failed":   "Could not write the diary: %v",
+		"status.diary_copied": "Day copied to clipboard",
+		"status.timed":        "Timer stopped after %s",
+		"status.opened":       "Opened %s",
+		"status.open_failed":  "Could not open the link: %v",
+		"status.no_links":     "This task has no links",
+		"status.no_location":  "This task points at no file:line",
+		"status.edit_failed":  "The editor failed: %v",
+		"status.planned":      "Planned for %s",
+		"status.unplanned":    "Back to unplanned",
+		"status.commented":    "Comment added",
+		"status.assigned":     "Assigned to %s",
+		"status.unassigned":   "Unassigned",
+		"status.project":      "Project set to %s",
+		"status.deferred":     "Parked in someday/maybe",
+		"status.promoted":     "Moved onto the board in %s",
+		"status.urgent":       "Marked urgent",
+		"status.not_urgent":   "No longer urgent",
+		"status.task_gone":    "The task was removed by a sync",
+		"status.script_keys":  "Script commands on keys basket uses never run: %s",
+
+		"key.left":     "left column",
+		"key.right":    "right column",
+		"key.up":       "up",
+		"key.down":     "down",
+		"key.columns":  "columns",
+		"key.tasks":    "tasks",
+		"key.toggle":   "toggle",
+		"key.move":     "move",
+		"key.new":      "new",
+		"key.edit":     "edit",
+		"key.delete":   "delete",
+		"key.share":    "copy as text",
+		"key.open":     "open link",
+		"key.code":     "open in editor",
+		"key.comments": "comments",
+		"key.assign":   "assign",
+		"key.mine":     "only mine",
+		"key.project":  "set project",
+		"key.projects": "projects",
+		"key.contexts": "contexts",
+		"key.defer":    "to someday",
+		"key.someday":  "someday/maybe",
+		"key.habits":   "habits",
+		"key.diary":    "diary",
+		"key.log":      "log a note",
+		"key.days":     "days",
+		"key.promote":  "promote",
+		"key.column":   "jump to column",
+		"key.urgent":   "urgent",
+		"key.pin":      "pin",
+		"key.raise":    "move up",
+		"key.lower":    "move down",
+		"key.reorder":  "reorder",
+		"key.split":    "break down",
+		"key.timer":    "start/stop timer",
+		"key.matrix":   "eisenhower matrix",
+		"key.switch":   "switch board",
+		"key.boards":   "pick board",
+		"key.week":     "week planner",
+		"key.earlier":  "a day earlier",
+		"key.later":    "a day later",
+		"key.plan":     "plan",
+		"key.graph":    "blocked-by graph",
+		"key.scroll":   "scroll",
+		"key.sink":     "sink completed",
+		"key.details":  "details",
+		"key.hide":     "hide completed",
+		"key.urgency":  "sort by urgency",
+		"key.goto":     "goto",
+		"key.query":    "query",
+		"key.legend":   "tag legend",
+		"key.jump":     "jump to a column",
+		"key.density":  "compact cards",
+		"key.seal":     "sensitive",
+		"key.backups":  "backups",
+		"key.sync":     "sync now",
+		"key.filter":   "script filter",
+		"key.help":     "help",
+		"key.quit":     "quit",
+		"key.save":     "save",
+		"key.confirm":  "confirm",
+		"key.complete": "complete tag",
+		"key.cancel":   "cancel",
+		"key.restore":  "restore",
+		"key.retry":    "retry",
+		"key.discard":  "start over",
+
+		"error": "Error: %v",
+	},
+	"de": {
+		"priority.lowest":  "MINIMAL",
+		"priority.low":     "NIEDRIG",
+		"priority.medium":  "MITTEL",
+		"priority.high":    "HOCH",
+		"priority.highest": "KRITISCH",
+
+		"header.title":  "  🧺 BASKET  %s  ",
+		"header.global": "🌍 GLOBAL",
+		"header.local":  "📂 LOKAL",
+
+		"board.column_count": "%s (%d/%d)",
+		"board.hidden_done":  "(+%d erledigt)",
+		"card.ago":           "vor %s",
+		"card.due_in":        "fällig in %s",
+		"card.due_ago":       "seit %s fällig",
+		"card.due_today":     "heute fällig",
+		"board.filter":       "Filter: %s",
+		"board.query":        "Abfrage: %s",
+		"legend.title":       "Tags",
+		"legend.empty":       "noch keine Tags; @tag in eine Aufgabe schreiben",
+		"preview.empty":      "keine Aufgabe ausgewählt",
+		"board.workspace":    "Arbeitsbereich %s",
+		"board.only_mine":    "nur %s",
+		"board.by_urgency":   "⏱ nach Dringlichkeit",
+		"board.wip":          "WIP %d/%d",
+		"board.hidden_cols":  "(%d Spalten ausgeblendet)",
+		"board.branch":       "⎇ %s",
+		"board.project":      "Projekt: %s",
+		"board.someday":      "💭 %d irgendwann",
+		"board.empty":        "Keine Aufgaben",
+		"board.more_above":   "    ▲ weitere oben",
+		"board.more_below":   "    ▼ weitere unten",
+
+		"add.title":         "📝 NEUE AUFGABE IN %s",
+		"add.placeholder":   "Titel der Aufgabe eingeben...",
+		"add.someday_title": "💭 ZU IRGENDWANN/VIELLEICHT",
+		"add.habit_title":   "🌱 NEUE GEWOHNHEIT",
+
+		"edit.title":       "✏️  AUFGABE BEARBEITEN",
+		"edit.task_title":  "✏️  %s",
+		"edit.placeholder": "Beschreibung eingeben...",
+
+		"comments.title":       "💬 %s",
+		"comments.empty":       "Noch keine Kommentare",
+		"comments.placeholder": "Fortschrittsnotiz hinzufügen...",
+
+		"detail.assignee": "→ %s",
+		"detail.due":      "fällig %s",
+		"detail.comments": "%d Kommentar(e), zuletzt:",
+		"detail.planned":  "geplant %s",
+		"detail.estimate": "geschätzt %s",
+		"detail.tracked":  "%s erfasst",
+		"detail.created":  "erstellt %s",
+		"detail.waits_on": "wartet auf %s (#%s)",
+
+		"breakdown.title": "🪄 %s AUFTEILEN",
+		"breakdown.hint":  "Vorgeschlagene Schritte, einer pro Zeile. Zeilen bearbeiten oder löschen, dann speichern, um sie als Checkliste anzuhängen.",
+
+		"assign.title":       "👤 AUFGABE ZUWEISEN",
+		"assign.task_title":  "👤 %s ZUWEISEN",
+		"assign.placeholder": "Name, leer für niemanden",
+
+		"projects.title":  "📁 PROJEKTE",
+		"projects.all":    "Alle Projekte",
+		"projects.none":   "Kein Projekt",
+		"projects.counts": "%d offen / %d",
+
+		"someday.title":   "💭 IRGENDWANN/VIELLEICHT",
+		"someday.empty":   "Hier ist nichts geparkt. z parkt eine Karte, n notiert eine Idee",
+		"someday.promote": "Aufs Board:",
+
+		"habits.title":       "🌱 GEWOHNHEITEN",
+		"habits.empty":       "Noch keine Gewohnheiten. n legt eine an",
+		"habits.placeholder": "Gewohnheit, z.B. 20 Minuten lesen",
+		"habits.weekdays":    "SMDMDFS",
+
+		"links.title": "🔗 LINK ÖFFNEN",
+
+		"boards.title":     "📋 BOARDS",
+		"boards.empty":     "Noch keine lokalen Boards",
+		"boards.repo":      "Dieses Repository",
+		"boards.elsewhere": "Anderswo",
+		"boards.counts":    "%d offen · %d× geöffnet",
+
+		"week.title":     "🗓  WOCHE AB %s",
+		"week.unplanned": "Ungeplant",
+		"week.days":      "Mo Di Mi Do Fr Sa So",
+		"week.date":      "2.1.",
+
+		"diary.title":       "📓 TAGEBUCH · %s",
+		"diary.date":        "2.1.2006",
+		"diary.empty":       "An diesem Tag nichts notiert. i fügt eine Notiz hinzu",
+		"diary.placeholder": "Was hast du gemacht?",
+		"diary.note_title":  "📓 NOTIZ ANLEGEN",
+
+		"graph.title": "⛓  BLOCKIERT DURCH",
+		"graph.empty": "Nichts ist blockiert. Verknüpfen mit basket block <id> <blocker-id>",
+		"graph.seen":  "(siehe oben)",
+
+		"matrix.title":         "🧭 EISENHOWER-MATRIX",
+		"matrix.urgent":        "DRINGEND",
+		"matrix.not_urgent":    "NICHT DRINGEND",
+		"matrix.do":            "Sofort erledigen",
+		"matrix.do_hint":       "dringend und wichtig",
+		"matrix.schedule":      "Einplanen",
+		"matrix.schedule_hint": "wichtig, nicht dringend",
+		"matrix.delegate":      "Abgeben",
+		"matrix.delegate_hint": "dringend, nicht wichtig",
+		"matrix.drop":          "Streichen",
+		"matrix.drop_hint":     "weder noch",
+		"matrix.more":          "  … %d weitere",
+
+		"contexts.title": "📍 KONTEXTE",
+		"contexts.any":   "Jeder Kontext",
+		"contexts.empty": "Schreibe @zuhause, @unterwegs... in Aufgabentitel, um Kontexte zu nutzen",
+
+		"project.title":       "📁 PROJEKT FESTLEGEN",
+		"project.task_title":  "📁 PROJEKT VON %s",
+		"project.placeholder": "Projektname, leer für keins",
+
+		"goto.title":       "🔎 GEHE ZU AUFGABE",
+		"goto.placeholder": "Aufgaben-ID oder Kurz-ID",
+		"goto.not_found":   "Keine eindeutige Aufgabe passt zu %q",
+
+		"query.title":       "🔍 NACH ABFRAGE FILTERN",
+		"query.placeholder": "priority>=high and @infra and due<7d and not completed",
+		"query.hint":        "Leer lassen, um alle Aufgaben zu zeigen",
+
+		"passphrase.title":             "🔒 PASSPHRASE",
+		"passphrase.placeholder":       "Passphrase für vertrauliche Beschreibungen",
+		"passphrase.hint":              "Gilt bis basket beendet wird; BASKET_PASSPHRASE setzt sie vorab",
+		"passphrase.wrong":             "Falsche Passphrase: sie öffnet die versiegelten Beschreibungen nicht",
+		"passphrase.board_placeholder": "Passphrase für dieses geschützte Board",
+		"passphrase.board_hint":        "Enter öffnet das Board, Esc beendet basket",
+		"passphrase.board_wrong":       "Falsche Passphrase",
+		"sensitive.locked":             "🔒 Vertraulich; e zum Entsperren",
+
+		"backups.title":   "🗄  SICHERUNGEN",
+		"backups.empty":   "Noch keine Sicherungen dieses Boards",
+		"backups.tasks":   "%d Aufgaben",
+		"backups.preview": "Vorschau",
+
+		"corrupt.title": "⚠  BESCHÄDIGTES BOARD",
+		"corrupt.body":  "Basket überschreibt diese Datei nicht. Repariere sie in einem Editor und lade neu, stelle eine Sicherung wieder her oder beginne das Board neu.",
+		"corrupt.copy":  "Eine Kopie der beschädigten Datei liegt unter %s",
+
+		"sync.synced":  "☁ synchron",
+		"sync.pending": "⟳ synchronisiere",
+		"sync.error":   "⚠ Sync-Fehler",
+		"sync.offline": "⚡ offline",
+
+		"status.added":        "Aufgabe zu %s hinzugefügt",
+		"status.saved":        "Aufgabe gespeichert",
+		"status.sealed":       "Beschreibung versiegelt",
+		"status.unsealed":     "Beschreibung nicht mehr versiegelt",
+		"status.completed":    "Aufgabe erledigt",
+		"status.reopened":     "Aufgabe wieder geöffnet",
+		"status.deleted":      "Aufgabe gelöscht",
+		"status.moved":        "Nach %s verschoben",
+		"status.reordered":    "Umsortiert",
+		"status.pinned":       "Oben angeheftet",
+		"status.unpinned":     "Nicht mehr angeheftet",
+		"status.rank_urgency": "Die Spalten sind nach Dringlichkeit sortiert; O ordnet sie wieder von Hand",
+		"status.ran":          "%s ausgeführt",
+		"status.save_failed":  "Speichern fehlgeschlagen: %v",
+		"status.load_failed":  "Board konnte nicht geladen werden: %v",
+		"status.shared":       "Aufgabe in die Zwischenablage kopiert",
+		"status.share_failed": "Kopieren in die Zwischenablage fehlgeschlagen: %v",
+		"status.recovered":    "Board geladen",
+		"status.discarded":    "Neu begonnen, die beschädigte Datei liegt unter %s",
+		"status.splitting":    "Frage das Modell nach Schritten...",
+		"status.split_failed": "Aufgabe konnte nicht aufgeteilt werden: %v",
+		"status.split":        "%d Checklistenpunkte hinzugefügt",
+		"status.timing":       "Zeiterfassung gestartet",
+		"status.habit_added":  "Gewohnheit angelegt",
+		"status.habit_done":   "Heute erledigt, %d Tage in Folge",
+		"status.habit_undone": "Für heute zurückgenommen",
+		"status.logged":       "Notiert",
+		"status.log_failed":   "Tagebuch nicht gespeichert: %v",
+		"status.diary_copied": "Tag in die Zwischenablage kopiert",
+		"status.timed":        "Zeiterfassung nach %s gestoppt",
+		"status.opened":       "%s geöffnet",
+		"status.open_failed":  "Link konnte nicht geöffnet werden: %v",
+		"status.no_links":     "Diese Aufgabe enthält keine Links",
+		"status.no_location":  "Diese Aufgabe verweist auf keine Datei:Zeile",
+		"status.edit_failed":  "Der Editor ist fehlgeschlagen: %v",
+		"status.planned":      "Für %s eingeplant",
+		"status.unplanned":    "Wieder ungeplant",
+		"status.commented":    "Kommentar hinzugefügt",
+		"status.assigned":     "%s zugewiesen",
+		"status.unassigned":   "Zuweisung entfernt",
+		"status.project":      "Projekt: %s",
+		"status.deferred":     "In Irgendwann/Vielleicht geparkt",
+		"status.promoted":     "Aufs Board nach %s verschoben",
+		"status.urgent":       "Als dringend markiert",
+		"status.not_urgent":   "Nicht mehr dringend",
+		"status.task_gone":    "Die Aufgabe wurde beim Synchronisieren entfernt",
+		"status.script_keys":  "Skriptbefehle auf von basket belegten Tasten laufen nie: %s",
+
+		"key.left":     "linke Spalte",
+		"key.right":    "rechte Spalte",
+		"key.up":       "hoch",
+		"key.down":     "runter",
+		"key.columns":  "Spalten",
+		"key.tasks":    "Aufgaben",
+		"key.toggle":   "erledigt",
+		"key.move":     "verschieben",
+		"key.new":      "neu",
+		"key.edit":     "bearbeiten",
+		"key.delete":   "löschen",
+		"key.share":    "als Text kopieren",
+		"key.open":     "Link öffnen",
+		"key.code":     "im Editor öffnen",
+		"key.comments": "Kommentare",
+		"key.assign":   "zuweisen",
+		"key.mine":     "nur meine",
+		"key.project":  "Projekt setzen",
+		"key.projects": "Projekte",
+		"key.contexts": "Kontexte",
+		"key.defer":    "nach irgendwann",
+		"key.someday":  "Irgendwann/Vielleicht",
+		"key.habits":   "Gewohnheiten",
+		"key.diary":    "Tagebuch",
+		"key.log":      "Notiz anlegen",
+		"key.days":     "Tage",
+		"key.promote":  "aufs Board",
+		"key.column":   "zur Spalte",
+		"key.urgent":   "dringend",
+		"key.pin":      "anheften",
+		"key.raise":    "nach oben",
+		"key.lower":    "nach unten",
+		"key.reorder":  "umsortieren",
+		"key.split":    "aufteilen",
+		"key.timer":    "Zeit starten/stoppen",
+		"key.matrix":   "Eisenhower-Matrix",
+		"key.switch":   "Board wechseln",
+		"key.boards":   "Board auswählen",
+		"key.week":     "Wochenplan",
+		"key.earlier":  "einen Tag früher",
+		"key.later":    "einen Tag später",
+		"key.plan":     "planen",
+		"key.graph":    "Abhängigkeiten",
+		"key.scroll":   "blättern",
+		"key.sink":     "Erledigte nach unten",
+		"key.details":  "Details",
+		"key.hide":     "Erledigte ausblenden",
+		"key.urgency":  "nach Dringlichkeit",
+		"key.goto":     "gehe zu",
+		"key.query":    "Abfrage",
+		"key.legend":   "Tag-Legende",
+		"key.jump":     "zu einer Spalte springen",
+		"key.density":  "kompakte Karten",
+		"key.seal":     "vertraulich",
+		"key.backups":  "Sicherungen",
+		"key.sync":     "jetzt synchronisieren",
+		"key.filter":   "Skript-Filter",
+		"key.help":     "Hilfe",
+		"key.quit":     "beenden",
+		"key.save":     "speichern",
+		"key.confirm":  "bestätigen",
+		"key.complete": "Tag ergänzen",
+		"key.cancel":   "abbrechen",
+		"key.restore":  "wiederherstellen",
+		"key.retry":    "neu laden",
+		"key.discard":  "neu beginnen",
+
+		"error": "Fehler: %v",
+	},
+}
+
+// messages is the active catalog, with English as the fallback for missing keys
+var messages = catalogs["en"]
+
+// T returns the localized message for key
+func T(key string) string {
+	if msg, ok := messages[key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs["en"][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// detectLocale picks the locale from the config, falling back to the
+// usual POSIX environment variables
+func detectLocale(cfg Config) string {
+	candidates := []string{cfg.Locale, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")}
+	for _, c := range candidates {
+		if c == "" || c == "C" || c == "POSIX" {
+			continue
+		}
+		// "de_DE.UTF-8" -> "de"
+		c = strings.SplitN(c, ".", 2)[0]
+		c = strings.SplitN(c, "_", 2)[0]
+		c = strings.SplitN(c, "-", 2)[0]
+		return strings.ToLower(c)
+	}
+	return "en"
+}
+
+// setLocale activates the catalog for locale. A user catalog at
+// <config dir>/locales/<locale>.json is layered over the built-in one,
+// so translations can be added or tweaked without rebuilding.
+func setLocale(locale string) {
+	active := map[string]string{}
+	for k, v := range catalogs["en"] {
+		active[k] = v
+	}
+	for k, v := range catalogs[locale] {
+		active[k] = v
+	}
+
+	data, err := os.ReadFile(filepath.Join(getConfigDir(), "locales", locale+".json"))
+	if err == nil {
+		var user map[string]string
+		if err := json.Unmarshal(data, &user); err == nil {
+			for k, v := range user {
+				active[k] = v
+			}
+		}
+	}
+
+	messages = active
+}
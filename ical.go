@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// icalProp is one content line of an iCalendar object, such as
+// DTSTART;TZID=Europe/Berlin:20250102T090000
+type icalProp struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icalComponent is a BEGIN/END block with its properties and nested
+// components
+type icalComponent struct {
+	Name     string
+	Props    []icalProp
+	Children []*icalComponent
+}
+
+// get returns the first property called name
+func (c *icalComponent) get(name string) (icalProp, bool) {
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return icalProp{}, false
+}
+
+// text returns the unescaped value of the first property called name
+func (c *icalComponent) text(name string) string {
+	p, _ := c.get(name)
+	return icalUnescape(p.Value)
+}
+
+// walk calls fn for c and every component nested in it
+func (c *icalComponent) walk(fn func(*icalComponent)) {
+	fn(c)
+	for _, child := range c.Children {
+		child.walk(fn)
+	}
+}
+
+// parseICal reads an iCalendar stream (RFC 5545) into its top-level
+// components. Folded lines are joined and unknown properties kept.
+func parseICal(data string) ([]*icalComponent, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	// Unfold: a line starting with a space or tab continues the previous
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var roots []*icalComponent
+	var stack []*icalComponent
+	for n, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICalLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		switch prop.Name {
+		case "BEGIN":
+			c := &icalComponent{Name: strings.ToUpper(prop.Value)}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, c)
+			} else {
+				roots = append(roots, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", n+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: %s outside of a component", n+1, prop.Name)
+			}
+			c := stack[len(stack)-1]
+			c.Props = append(c.Props, prop)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	return roots, nil
+}
+
+// parseICalLine splits NAME;PARAM=x:value. Colons inside quoted parameter
+// values do not end the name.
+func parseICalLine(line string) (icalProp, error) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icalProp{}, fmt.Errorf("no value in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop := icalProp{Name: strings.ToUpper(parts[0]), Value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			if prop.Params == nil {
+				prop.Params = map[string]string{}
+			}
+			prop.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return prop, nil
+}
+
+// icalTime parses a DATE or DATE-TIME value. Floating times and times
+// in a TZID that is not known here are read as local time. allDay is set
+// for plain dates.
+func icalTime(p icalProp) (t time.Time, allDay bool, err error) {
+	loc := time.Local
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	v := strings.TrimSpace(p.Value)
+	switch {
+	case len(v) == 8:
+		t, err = time.ParseInLocation("20060102", v, time.Local)
+		return t, true, err
+	case strings.HasSuffix(v, "Z"):
+		t, err = time.Parse("20060102T150405Z", v)
+	default:
+		t, err = time.ParseInLocation("20060102T150405", v, loc)
+	}
+	return t, false, err
+}
+
+// icalEscape escapes a TEXT value
+func icalEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// icalUnescape reverses icalEscape
+func icalUnescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// icalUTC formats t as a UTC DATE-TIME value
+func icalUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// writeICalLine appends a content line, folded at 75 octets without
+// splitting a UTF-8 sequence
+func writeICalLine(b *strings.Builder, name, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space counts
+	}
+	b.WriteString(line + "\r\n")
+}
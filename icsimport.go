@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isICSFile reports whether path is an iCalendar file
+func isICSFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".ics" || ext == ".ical"
+}
+
+// icsTasks turns the VEVENTs and VTODOs of a calendar into due-dated
+// tasks. Events are due when they start; events that ended before now
+// are skipped unless past is set.
+func icsTasks(data string, now time.Time, past bool) ([]Task, error) {
+	roots, err := parseICal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []Task
+	var walkErr error
+	for _, root := range roots {
+		root.walk(func(c *icalComponent) {
+			if walkErr != nil || (c.Name != "VEVENT" && c.Name != "VTODO") {
+				return
+			}
+			task := Task{
+				ID:          generateID(),
+				Title:       strings.TrimSpace(c.text("SUMMARY")),
+				Description: strings.TrimSpace(c.text("DESCRIPTION")),
+				Priority:    defaultPriority(),
+				CreatedAt:   now,
+			}
+			if task.Title == "" {
+				task.Title = "(untitled)"
+			}
+			if loc := c.text("LOCATION"); loc != "" {
+				task.Description = strings.TrimSpace(task.Description + "\n\n" + loc)
+			}
+
+			dueProp := "DUE"
+			if c.Name == "VEVENT" {
+				dueProp = "DTSTART"
+			}
+			if p, ok := c.get(dueProp); ok {
+				due, _, err := icalTime(p)
+				if err != nil {
+					walkErr = fmt.Errorf("%s %q: %w", dueProp, task.Title, err)
+					return
+				}
+				task.Due = &due
+			}
+
+			if c.Name == "VEVENT" && !past {
+				end := task.Due
+				if p, ok := c.get("DTEND"); ok {
+					if t, _, err := icalTime(p); err == nil {
+						end = &t
+					}
+				}
+				if end != nil && end.Before(now) {
+					return
+				}
+			}
+			if c.Name == "VTODO" {
+				n, _ := strconv.Atoi(c.text("PRIORITY"))
+				task.Priority = fromICalPriority(n)
+				if strings.EqualFold(c.text("STATUS"), "COMPLETED") {
+					setCompleted(&task, true)
+				}
+			}
+			tasks = append(tasks, task)
+		})
+	}
+	return tasks, walkErr
+}
+
+// icsKey identifies an imported entry by title and due date, so importing
+// the same file again skips what it already added
+func icsKey(task Task) string {
+	key := task.Title
+	if task.Due != nil {
+		key += "\x00" + task.Due.UTC().Format(time.RFC3339)
+	}
+	return key
+}
+
+// importICS adds the events and to-dos of an iCalendar file to the board
+// at path, skipping those imported before
+func importICS(cfg Config, path, file, project string, past, dryRun bool) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	incoming, err := icsTasks(string(data), time.Now(), past)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return addImported(cfg, path, incoming, project, dryRun)
+}
+
+// addImported adds tasks from another app to the board at path, skipping
+// those with the title and due date of a task already on it
+func addImported(cfg Config, path string, incoming []Task, project string, dryRun bool) error {
+	board, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, task := range append(append([]Task{}, board.Tasks...), board.Archive...) {
+		known[icsKey(task)] = true
+	}
+
+	before := cloneTasks(board.Tasks)
+	added := 0
+	for _, task := range incoming {
+		if known[icsKey(task)] {
+			continue
+		}
+		known[icsKey(task)] = true
+		if project != "" {
+			task.Project = project
+		}
+		board.Tasks = append(board.Tasks, task)
+		added++
+		due := ""
+		if task.Due != nil {
+			due = formatDue(*task.Due, time.Now())
+		}
+		fmt.Printf("added    %-14s  %s\n", due, task.Title)
+	}
+
+	if dryRun {
+		fmt.Printf("would add %d task(s) to %s\n", added, path)
+		return nil
+	}
+	if added == 0 {
+		fmt.Printf("%s is already up to date\n", path)
+		return nil
+	}
+	if err := saveBoard(path, board); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, board.Tasks)
+	fmt.Printf("added %d task(s) to %s\n", added, path)
+	return nil
+}
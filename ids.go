@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// shortIDLen is how many trailing characters of an ID are shown to humans
+const shortIDLen = 6
+
+var (
+	idMu   sync.Mutex
+	lastID [16]byte
+)
+
+// generateID returns a ULID: a 48-bit millisecond timestamp followed by
+// 80 random bits, encoded as 26 Crockford base32 characters. IDs sort
+// lexically in creation order. IDs generated within the same millisecond
+// increment the previous random part instead of drawing a new one, so
+// bulk creation in a tight loop can never collide or reorder.
+func generateID() string {
+	idMu.Lock()
+	defer idMu.Unlock()
+
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+
+	if binary.BigEndian.Uint64(b[:8])>>16 <= binary.BigEndian.Uint64(lastID[:8])>>16 {
+		// Same millisecond (or the clock stepped back): continue the
+		// sequence from the last ID
+		b = lastID
+		for i := 15; i >= 6; i-- {
+			b[i]++
+			if b[i] != 0 {
+				break
+			}
+		}
+	} else {
+		rand.Read(b[6:])
+	}
+
+	lastID = b
+	return encodeULID(b)
+}
+
+func encodeULID(b [16]byte) string {
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	// 128 bits encode into 26 characters; the first carries only 3 bits
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+// shortID is the compact, human-facing form of a task ID. It uses the
+// random tail of the ID, which is also where legacy nanosecond IDs vary.
+func shortID(id string) string {
+	if len(id) <= shortIDLen {
+		return strings.ToLower(id)
+	}
+	return strings.ToLower(id[len(id)-shortIDLen:])
+}
+
+// findTask resolves ref to an index in tasks. It accepts a full ID or a
+// case-insensitive prefix or suffix (such as a short ID), as long as it
+// matches a single task.
+func findTask(tasks []Task, ref string) (int, bool) {
+	ref = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(ref), "#"))
+	if ref == "" {
+		return -1, false
+	}
+
+	for i, task := range tasks {
+		if strings.ToLower(task.ID) == ref {
+			return i, true
+		}
+	}
+
+	match := -1
+	for i, task := range tasks {
+		id := strings.ToLower(task.ID)
+		if strings.HasPrefix(id, ref) || strings.HasSuffix(id, ref) {
+			if match >= 0 {
+				return -1, false
+			}
+			match = i
+		}
+	}
+	return match, match >= 0
+}
+
+// dedupeIDs assigns fresh IDs to tasks whose ID is empty or already used
+// by an earlier task. Boards written by older versions used UnixNano IDs,
+// which collided when tasks were created in quick succession. It reports
+// whether anything changed.
+func dedupeIDs(tasks []Task) bool {
+	seen := make(map[string]bool, len(tasks))
+	changed := false
+	for i := range tasks {
+		if tasks[i].ID == "" || seen[tasks[i].ID] {
+			tasks[i].ID = generateID()
+			changed = true
+		}
+		seen[tasks[i].ID] = true
+	}
+	return changed
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Issue references recognized in task text. Plain #123 needs whitespace or
+// a parenthesis before it, so it is not mistaken for part of a word.
+var (
+	ghRefPattern   = regexp.MustCompile(`\bGH-([\w.-]+/[\w.-]+)#(\d+)\b`)
+	keyRefPattern  = regexp.MustCompile(`\b([A-Z][A-Z0-9]+)-(\d+)\b`)
+	hashRefPattern = regexp.MustCompile(`(?:^|[\s(])#(\d+)\b`)
+)
+
+// defaultGHLink is where GH-owner/repo#12 points unless "GH" is configured
+const defaultGHLink = "https://github.com/{repo}/issues/{n}"
+
+// issueRef is an issue reference found in a task, with the link it
+// resolves to
+type issueRef struct {
+	Text string
+	URL  string
+}
+
+// expandIssueLink fills a configured link template: {ref} is the whole
+// reference, {n} its number and {repo} the owner/repo of a GH- reference
+func expandIssueLink(template, ref, n, repo string) string {
+	return strings.NewReplacer("{ref}", ref, "{n}", n, "{repo}", repo).Replace(template)
+}
+
+// issueRefs finds the issue references in a task's title and description
+// that the issue_links config can resolve, in order and without
+// duplicates. Text inside URLs is skipped.
+func issueRefs(task Task, links map[string]string) []issueRef {
+	var out []issueRef
+	seen := map[string]bool{}
+	add := func(ref, url string) {
+		if url != "" && !seen[ref] {
+			seen[ref] = true
+			out = append(out, issueRef{Text: ref, URL: url})
+		}
+	}
+
+	for _, text := range []string{task.Title, task.Description} {
+		text = urlPattern.ReplaceAllString(text, " ")
+
+		for _, m := range ghRefPattern.FindAllStringSubmatch(text, -1) {
+			template := links["GH"]
+			if template == "" {
+				template = defaultGHLink
+			}
+			add(m[0], expandIssueLink(template, m[0], m[2], m[1]))
+		}
+		text = ghRefPattern.ReplaceAllString(text, " ")
+
+		for _, m := range keyRefPattern.FindAllStringSubmatch(text, -1) {
+			if template, ok := links[m[1]]; ok {
+				add(m[0], expandIssueLink(template, m[0], m[2], ""))
+			}
+		}
+		if template, ok := links["#"]; ok {
+			for _, m := range hashRefPattern.FindAllStringSubmatch(text, -1) {
+				add("#"+m[1], expandIssueLink(template, "#"+m[1], m[1], ""))
+			}
+		}
+	}
+	return out
+}
+
+// taskLinks is every link a task carries: its URLs, then its resolvable
+// issue references
+func taskLinks(task Task, cfg Config) []string {
+	out := taskURLs(task)
+	for _, ref := range issueRefs(task, cfg.IssueLinks) {
+		if !slices.Contains(out, ref.URL) {
+			out = append(out, ref.URL)
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// The journal records each edit before the board file is rewritten. If
+// basket dies between the two, the next load replays the journal, so an
+// edit the UI has confirmed is never lost.
+
+func getJournalPath(boardPath string) string {
+	return filepath.Join(getDataDir(), "journal", backupKey(boardPath)+".jsonl")
+}
+
+// appendJournal durably records muts for the board at path
+func appendJournal(path string, muts []mutation) error {
+	if len(muts) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, mut := range muts {
+		line, err := json.Marshal(mut)
+		if err != nil {
+			return err
+		}
+		if line, err = sealBoardData(path, line); err != nil {
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+
+	jpath := getJournalPath(path)
+	if err := os.MkdirAll(filepath.Dir(jpath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(jpath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// readJournal returns the edits recorded for the board at path. A line
+// cut short by a crash mid-write is ignored; its edit had not been
+// confirmed yet. Lines of a protected board are sealed.
+func readJournal(path string) []mutation {
+	f, err := os.Open(getJournalPath(path))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var muts []mutation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line, err := openBoardData(path, scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		var mut mutation
+		if json.Unmarshal(line, &mut) == nil {
+			muts = append(muts, mut)
+		}
+	}
+	return muts
+}
+
+// clearJournal drops the journal once the board file holds its edits
+func clearJournal(path string) error {
+	err := os.Remove(getJournalPath(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// replayJournal applies edits left over from a crash to the loaded board
+// and writes the result back
+func replayJournal(path string, taskList *TaskList) error {
+	muts := readJournal(path)
+	if len(muts) == 0 {
+		return nil
+	}
+	logger.Info("replay journal", "path", path, "mutations", len(muts))
+	taskList.Tasks = applyMutations(taskList.Tasks, muts)
+	if err := saveBoard(path, *taskList); err != nil {
+		return err
+	}
+	return clearJournal(path)
+}
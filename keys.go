@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap holds every remappable key binding
+type keyMap struct {
+	Left     key.Binding
+	Right    key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Column   key.Binding // jump to a column by its number
+	Jump     key.Binding // may lead a column number, as in g2
+	Toggle   key.Binding
+	Details  key.Binding // show the selected task in a popup
+	Move     key.Binding
+	New      key.Binding
+	Edit     key.Binding
+	Delete   key.Binding
+	Share    key.Binding
+	Open     key.Binding // open a link in the selected task
+	Code     key.Binding // open the file:line the selected task points at
+	Comments key.Binding
+	Assign   key.Binding
+	Mine     key.Binding
+
+	SetProject key.Binding
+	Projects   key.Binding
+	Contexts   key.Binding
+	Defer      key.Binding // park the selected task in someday/maybe
+	Someday    key.Binding
+	Habits     key.Binding
+	Diary      key.Binding // the log of the day's work
+	Log        key.Binding // add a note to the diary
+	Promote    key.Binding
+	Urgent     key.Binding
+	Pin        key.Binding // keep the selected card at the top of its column
+	Raise      key.Binding // move the selected card up its column
+	Lower      key.Binding
+	Split      key.Binding // ask the LLM to split the selected task
+	Timer      key.Binding
+	Matrix     key.Binding
+	Week       key.Binding
+	Graph      key.Binding // what holds up blocked tasks
+	Earlier    key.Binding // plan a task a day earlier in the week planner
+	Later      key.Binding
+	Switch     key.Binding
+	Boards     key.Binding // pick among the repository's boards
+	Sink       key.Binding
+	Hide       key.Binding // hide completed tasks
+	ByUrgency  key.Binding
+	Goto       key.Binding
+	Query      key.Binding // filter the board with a query
+	Legend     key.Binding // list the board's tags and their colors
+	Density    key.Binding // switch between full and one-line cards
+	Seal       key.Binding // seal or open the selected task's description
+	Complete   key.Binding // complete the @tag being typed in a form
+	Backups    key.Binding
+	Sync       key.Binding
+	Filter     key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+
+	Save    key.Binding // submit a text form
+	Confirm key.Binding // submit a prompt or pick an entry
+	Cancel  key.Binding
+	Restore key.Binding
+	Retry   key.Binding // reload a damaged board
+	Discard key.Binding // start a damaged board over
+}
+
+// defaultKeys are the built-in bindings, keyed by the action names used in
+// the config's "keys" section
+var defaultKeys = map[string][]string{
+	"left":     {"left", "h"},
+	"right":    {"right", "l"},
+	"up":       {"up", "k"},
+	"down":     {"down", "j"},
+	"column":   {"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+	"toggle":   {" "},
+	"details":  {"enter"},
+	"move":     {"m"},
+	"new":      {"n"},
+	"edit":     {"e"},
+	"delete":   {"d"},
+	"share":    {"y"},
+	"open":     {"o"},
+	"code":     {"E"},
+	"comments": {"c"},
+	"assign":   {"a"},
+	"mine":     {"u"},
+	"project":  {"p"},
+	"projects": {"+"},
+	"contexts": {"@"},
+	"defer":    {"z"},
+	"someday":  {"Z"},
+	"habits":   {"H"},
+	"diary":    {"I"},
+	"log":      {"i"},
+	"promote":  {"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+	"urgent":   {"!"},
+	"pin":      {"P"},
+	"raise":    {"K"},
+	"lower":    {"J"},
+	"split":    {"b"},
+	"timer":    {"T"},
+	"matrix":   {"X"},
+	"week":     {"W"},
+	"graph":    {"G"},
+	"earlier":  {"<"},
+	"later":    {">"},
+	"switch":   {"t"},
+	"boards":   {"L"},
+	"sink":     {"s"},
+	"hide":     {"x"},
+	"urgency":  {"O"},
+	"goto":     {"#"},
+	"query":    {"/"},
+	"legend":   {"C"},
+	"jump":     {"g"},
+	"density":  {"="},
+	"seal":     {"S"},
+	"backups":  {"B"},
+	"sync":     {"r"},
+	"filter":   {"f"},
+	"help":     {"?"},
+	"quit":     {"q", "ctrl+c"},
+	"save":     {"ctrl+s"},
+	"confirm":  {"enter"},
+	"complete": {"tab"},
+	"cancel":   {"esc"},
+	"restore":  {"r", "enter"},
+	"retry":    {"r"},
+	"discard":  {"D"},
+}
+
+// newKeyMap builds the bindings, with the config's remappings applied
+func newKeyMap(remap map[string][]string) keyMap {
+	bind := func(action string) key.Binding {
+		keys := defaultKeys[action]
+		if custom, ok := remap[action]; ok && len(custom) > 0 {
+			keys = custom
+		}
+		return key.NewBinding(
+			key.WithKeys(keys...),
+			key.WithHelp(keyLabel(keys), T("key."+action)),
+		)
+	}
+
+	return keyMap{
+		Left:     bind("left"),
+		Right:    bind("right"),
+		Up:       bind("up"),
+		Down:     bind("down"),
+		Column:   bind("column"),
+		Jump:     bind("jump"),
+		Toggle:   bind("toggle"),
+		Details:  bind("details"),
+		Move:     bind("move"),
+		New:      bind("new"),
+		Edit:     bind("edit"),
+		Delete:   bind("delete"),
+		Share:    bind("share"),
+		Open:     bind("open"),
+		Code:     bind("code"),
+		Comments: bind("comments"),
+		Assign:   bind("assign"),
+		Mine:     bind("mine"),
+
+		SetProject: bind("project"),
+		Projects:   bind("projects"),
+		Contexts:   bind("contexts"),
+		Defer:      bind("defer"),
+		Someday:    bind("someday"),
+		Habits:     bind("habits"),
+		Diary:      bind("diary"),
+		Log:        bind("log"),
+		Promote:    bind("promote"),
+		Urgent:     bind("urgent"),
+		Pin:        bind("pin"),
+		Raise:      bind("raise"),
+		Lower:      bind("lower"),
+		Split:      bind("split"),
+		Timer:      bind("timer"),
+		Matrix:     bind("matrix"),
+		Week:       bind("week"),
+		Graph:      bind("graph"),
+		Earlier:    bind("earlier"),
+		Later:      bind("later"),
+		Switch:     bind("switch"),
+		Boards:     bind("boards"),
+		Sink:       bind("sink"),
+		Hide:       bind("hide"),
+		ByUrgency:  bind("urgency"),
+		Goto:       bind("goto"),
+		Query:      bind("query"),
+		Legend:     bind("legend"),
+		Density:    bind("density"),
+		Seal:       bind("seal"),
+		Backups:    bind("backups"),
+		Sync:       bind("sync"),
+		Filter:     bind("filter"),
+		Help:       bind("help"),
+		Quit:       bind("quit"),
+		Save:       bind("save"),
+		Confirm:    bind("confirm"),
+		Complete:   bind("complete"),
+		Cancel:     bind("cancel"),
+		Restore:    bind("restore"),
+		Retry:      bind("retry"),
+		Discard:    bind("discard"),
+	}
+}
+
+// boardBindings are the bindings the board answers to itself, ahead of
+// script commands
+func (k keyMap) boardBindings() []key.Binding {
+	return []key.Binding{
+		k.Left, k.Right, k.Up, k.Down, k.Column, k.Jump, k.Toggle, k.Details, k.Move, k.New, k.Edit,
+		k.Delete, k.Share, k.Open, k.Code, k.Comments, k.Assign, k.Mine, k.SetProject,
+		k.Projects, k.Contexts, k.Defer, k.Someday, k.Habits, k.Diary, k.Log, k.Urgent,
+		k.Pin, k.Raise, k.Lower, k.Split, k.Timer, k.Matrix, k.Week, k.Graph, k.Switch,
+		k.Boards, k.Sink, k.Hide, k.ByUrgency, k.Goto, k.Query, k.Legend, k.Density,
+		k.Seal, k.Backups, k.Sync, k.Filter, k.Help, k.Quit, k.Cancel,
+	}
+}
+
+// keyLabel is how a binding's keys are shown in the footer
+func keyLabel(keys []string) string {
+	symbols := map[string]string{"left": "←", "right": "→", "up": "↑", "down": "↓", " ": "space"}
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		if s, ok := symbols[k]; ok {
+			k = s
+		}
+		labels[i] = k
+	}
+	return strings.Join(labels, "/")
+}
+
+// pairBinding folds two opposite bindings into one footer entry, such as
+// "h/l columns"
+func pairBinding(a, b key.Binding, desc string) key.Binding {
+	keys := append(append([]string{}, a.Keys()...), b.Keys()...)
+	label := lastKey(a) + "/" + lastKey(b)
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(label, desc))
+}
+
+// lastKey is the key shown for a binding in compact help; the arrow keys
+// come first in the defaults, so this favors the letter
+func lastKey(b key.Binding) string {
+	keys := b.Keys()
+	if len(keys) == 0 {
+		return ""
+	}
+	return keyLabel(keys[len(keys)-1:])
+}
+
+// footerKeys implements help.KeyMap for the bindings of one mode
+type footerKeys struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (f footerKeys) ShortHelp() []key.Binding  { return f.short }
+func (f footerKeys) FullHelp() [][]key.Binding { return f.full }
+
+// helpKeys returns the bindings that apply in the current mode
+func (m model) helpKeys() help.KeyMap {
+	k := m.keys
+	if m.mode == ViewBoard && m.detailTask() != nil {
+		short := []key.Binding{k.Cancel, k.Quit}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	}
+	switch m.mode {
+	case ViewAdd, ViewEdit, ViewComments, ViewBreakdown:
+		short := []key.Binding{k.Save, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewGoto, ViewQuery, ViewAssign, ViewSetProject, ViewPassphrase, ViewLogNote:
+		short := []key.Binding{k.Confirm, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewBackups:
+		short := []key.Binding{k.Up, k.Down, k.Restore, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewProjects, ViewContexts, ViewLinks, ViewBoards:
+		short := []key.Binding{k.Up, k.Down, k.Confirm, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewMatrix:
+		short := []key.Binding{k.Cancel, k.Quit}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewGraph:
+		short := []key.Binding{pairBinding(k.Up, k.Down, T("key.scroll")), k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewSomeday:
+		promote := key.NewBinding(key.WithKeys(k.Promote.Keys()...), key.WithHelp(fmt.Sprintf("1-%d", min(numPriorities(), 9)), T("key.promote")))
+		short := []key.Binding{k.Up, k.Down, promote, k.New, k.Delete, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewHabits:
+		short := []key.Binding{k.Up, k.Down, k.Toggle, k.New, k.Delete, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewWeek:
+		short := []key.Binding{pairBinding(k.Left, k.Right, T("key.columns")), pairBinding(k.Up, k.Down, T("key.tasks")), pairBinding(k.Earlier, k.Later, T("key.plan")), k.Toggle, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewDiary:
+		short := []key.Binding{pairBinding(k.Left, k.Right, T("key.days")), pairBinding(k.Up, k.Down, T("key.scroll")), k.Log, k.Share, k.Cancel}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	case ViewCorrupt:
+		short := []key.Binding{k.Retry, k.Backups, k.Discard, k.Quit}
+		return footerKeys{short: short, full: [][]key.Binding{short}}
+	}
+
+	column := key.NewBinding(key.WithKeys(k.Column.Keys()...), key.WithHelp(fmt.Sprintf("1-%d", min(len(m.shownColumns()), 9)), T("key.column")))
+	view := []key.Binding{k.Switch, k.Boards, k.Projects, k.Contexts, k.Someday, k.Habits, k.Diary, k.Matrix, k.Week, k.Graph, k.Sink, k.Hide, k.ByUrgency, k.Mine, k.Goto, k.Query, k.Legend, k.Density, k.Backups}
+	if m.syncer != nil {
+		view = append(view, k.Sync)
+	}
+	if m.script != nil && len(m.script.filters) > 0 {
+		view = append(view, k.Filter)
+	}
+	actions := []key.Binding{k.Toggle, k.Details, k.Move, k.New, k.Edit, k.Comments, k.Assign, k.SetProject, k.Urgent, k.Pin, pairBinding(k.Raise, k.Lower, T("key.reorder")), k.Timer, k.Defer, k.Seal, k.Delete, k.Share, k.Open, k.Code, k.Log}
+	if m.llm != nil {
+		actions = append(actions, k.Split)
+	}
+	full := [][]key.Binding{
+		{k.Left, k.Right, k.Up, k.Down, column},
+		actions,
+		append(view, k.Help, k.Quit),
+	}
+	if m.script != nil && len(m.script.keys) > 0 {
+		var commands []key.Binding
+		for _, name := range m.script.keys {
+			commands = append(commands, key.NewBinding(key.WithKeys(name), key.WithHelp(name, m.script.commands[name].Name)))
+		}
+		full = append(full, commands)
+	}
+
+	return footerKeys{
+		short: []key.Binding{
+			pairBinding(k.Left, k.Right, T("key.columns")),
+			pairBinding(k.Up, k.Down, T("key.tasks")),
+			k.Toggle, k.Move, k.New, k.Edit, k.Delete, k.Goto, k.Switch, k.Help, k.Quit,
+		},
+		full: full,
+	}
+}
+
+// renderFooter shows the current mode's shortcuts, expanded with the help
+// key on the board
+func (m model) renderFooter() string {
+	return m.help.View(m.helpKeys())
+}
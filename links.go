@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// urlPattern matches web links in task text
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// taskURLs returns the links in a task's title and description, in order
+// and without duplicates. Punctuation ending a sentence is not part of the
+// link.
+func taskURLs(task Task) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, text := range []string{task.Title, task.Description} {
+		for _, u := range urlPattern.FindAllString(text, -1) {
+			u = strings.TrimRight(u, ".,;:!?")
+			if strings.HasSuffix(u, ")") && !strings.Contains(u, "(") {
+				u = strings.TrimSuffix(u, ")")
+			}
+			if !seen[u] {
+				seen[u] = true
+				out = append(out, u)
+			}
+		}
+	}
+	return out
+}
+
+// hyperlink wraps text in an OSC 8 escape, which supporting terminals show
+// as a clickable link to url and others ignore
+func hyperlink(text, url string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// linksEnabled reports whether cards carry hyperlinks; on unless the
+// config turns them off
+func (c Config) linksEnabled() bool {
+	return c.Hyperlinks == nil || *c.Hyperlinks
+}
+
+// openURL hands url to the desktop's default handler without waiting for
+// it
+func openURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// openSelectedLinks opens the selected task's link, or lets the user pick
+// one when it has several
+func (m *model) openSelectedLinks() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	urls := taskLinks(tasksInCol[m.selectedTask], m.config)
+	switch len(urls) {
+	case 0:
+		m.fail(errors.New(T("status.no_links")))
+	case 1:
+		m.openLink(urls[0])
+	default:
+		m.links = urls
+		m.linkCursor = 0
+		m.mode = ViewLinks
+	}
+}
+
+func (m *model) openLink(url string) {
+	if err := openURL(url); err != nil {
+		m.fail(fmt.Errorf(T("status.open_failed"), err))
+		return
+	}
+	m.notify(fmt.Sprintf(T("status.opened"), url))
+}
+
+func (m model) updateLinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Up):
+		if m.linkCursor > 0 {
+			m.linkCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.linkCursor < len(m.links)-1 {
+			m.linkCursor++
+		}
+
+	case key.Matches(msg, m.keys.Confirm):
+		m.openLink(m.links[m.linkCursor])
+		m.mode = ViewBoard
+	}
+
+	return m, nil
+}
+
+func (m model) viewLinks() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("links.title")) + "\n")
+	for i, url := range m.links {
+		line := truncate(url, 72)
+		if i == m.linkCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LLMConfig points basket at an OpenAI-compatible chat completions API.
+// Nothing is ever sent unless an API key is configured.
+type LLMConfig struct {
+	// URL is the API base, e.g. https://api.openai.com/v1; requests go
+	// to URL/chat/completions
+	URL    string `json:"url"`
+	APIKey string `json:"api_key"`
+	Model  string `json:"model"`
+}
+
+// defaultLLMURL and defaultLLMModel apply when the config leaves them out
+const (
+	defaultLLMURL   = "https://api.openai.com/v1"
+	defaultLLMModel = "gpt-4o-mini"
+)
+
+// llmClient talks to the configured endpoint
+type llmClient struct {
+	url    string
+	key    string
+	model  string
+	client *http.Client
+}
+
+// newLLMClient builds the client described by the config, or nil when no
+// API key is set
+func newLLMClient(cfg Config) *llmClient {
+	if cfg.LLM == nil || cfg.LLM.APIKey == "" {
+		return nil
+	}
+	c := &llmClient{
+		url:    strings.TrimSuffix(cfg.LLM.URL, "/"),
+		key:    cfg.LLM.APIKey,
+		model:  cfg.LLM.Model,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+	if c.url == "" {
+		c.url = defaultLLMURL
+	}
+	if c.model == "" {
+		c.model = defaultLLMModel
+	}
+	return c
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chat sends one system and one user message and returns the reply
+func (c *llmClient) chat(system, user string) (string, error) {
+	body, err := json.Marshal(map[string]any{
+		"model": c.model,
+		"messages": []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.url+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.key)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%s: %s", c.url, resp.Status)
+	}
+
+	var reply struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return "", err
+	}
+	if len(reply.Choices) == 0 {
+		return "", fmt.Errorf("%s: empty reply", c.url)
+	}
+	logger.Debug("llm", "model", c.model, "reply", len(reply.Choices[0].Message.Content))
+	return reply.Choices[0].Message.Content, nil
+}
+
+// listMarker is a bullet or number a model puts before a list item
+var listMarker = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+(?:\[ \]\s+)?`)
+
+// replyLines splits a reply into its non-empty lines, without the list
+// markers models like to add
+func replyLines(reply string) []string {
+	var lines []string
+	for _, line := range strings.Split(reply, "\n") {
+		line = listMarker.ReplaceAllString(strings.TrimSpace(line), "")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
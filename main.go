@@ -1,76 +1,75 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
-// Priority levels
-type Priority int
-
-const (
-	PriorityLowest Priority = iota
-	PriorityLow
-	PriorityMedium
-	PriorityHigh
-	PriorityHighest
-)
-
-func (p Priority) String() string {
-	switch p {
-	case PriorityLowest:
-		return "LOWEST"
-	case PriorityLow:
-		return "LOW"
-	case PriorityMedium:
-		return "MEDIUM"
-	case PriorityHigh:
-		return "HIGH"
-	case PriorityHighest:
-		return "HIGHEST"
-	default:
-		return "MEDIUM"
-	}
-}
-
-func (p Priority) Color() lipgloss.Color {
-	switch p {
-	case PriorityLowest:
-		return lipgloss.Color("#6B7280")
-	case PriorityLow:
-		return lipgloss.Color("#3B82F6")
-	case PriorityMedium:
-		return lipgloss.Color("#8B5CF6")
-	case PriorityHigh:
-		return lipgloss.Color("#F59E0B")
-	case PriorityHighest:
-		return lipgloss.Color("#EF4444")
-	default:
-		return lipgloss.Color("#8B5CF6")
-	}
-}
-
 // Task represents a single task
 type Task struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
-	Priority    Priority  `json:"priority"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Completed   bool        `json:"completed"`
+	Priority    Priority    `json:"priority"`
+	CreatedAt   time.Time   `json:"created_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+	ArchivedAt  *time.Time  `json:"archived_at,omitempty"`
+	UpdatedAt   *time.Time  `json:"updated_at,omitempty"`
+	Comments    []Comment   `json:"comments,omitempty"`
+	Assignee    string      `json:"assignee,omitempty"`
+	Project     string      `json:"project,omitempty"`
+	Someday     bool        `json:"someday,omitempty"` // parked off the board
+	Urgent      bool        `json:"urgent,omitempty"`
+	EscalatedAt *time.Time  `json:"escalated_at,omitempty"` // last bumped for going stale
+	TimeLog     []TimeEntry `json:"time_log,omitempty"`
+	Due         *time.Time  `json:"due,omitempty"`       // midnight for a date without a time
+	Habit       bool        `json:"habit,omitempty"`     // a recurring habit, kept off the columns
+	HabitLog    []string    `json:"habit_log,omitempty"` // days the habit was done, as 2006-01-02
+	Branch      string      `json:"branch,omitempty"`    // the git branch the work happens on
+
+	// PlannedOn is the day the week planner has the task on
+	PlannedOn *time.Time `json:"planned_on,omitempty"`
+
+	// BlockedBy lists the IDs of the tasks this one waits on
+	BlockedBy []string `json:"blocked_by,omitempty"`
+
+	// Estimate is how long the task is expected to take, in minutes
+	Estimate int `json:"estimate,omitempty"`
+
+	// Sensitive keeps the description sealed with the passphrase
+	Sensitive bool `json:"sensitive,omitempty"`
+
+	// Rank orders the task within its column; see rank.go
+	Rank string `json:"rank,omitempty"`
+
+	// Pinned keeps the task at the top of its column
+	Pinned bool `json:"pinned,omitempty"`
 }
 
-// TaskList holds tasks
+// TaskList is the on-disk board: the working tasks plus archived ones
 type TaskList struct {
-	Tasks []Task `json:"tasks"`
+	Tasks   []Task `json:"tasks"`
+	Archive []Task `json:"archive,omitempty"`
+
+	// Columns replaces the configured priority levels for this board,
+	// lowest first
+	Columns []PriorityLevel `json:"columns,omitempty"`
+
+	Settings *BoardSettings `json:"settings,omitempty"`
 }
 
 // ViewMode represents the current view
@@ -80,46 +79,127 @@ const (
 	ViewBoard ViewMode = iota
 	ViewAdd
 	ViewEdit
-	ViewHelp
+	ViewGoto
+	ViewBackups
+	ViewCorrupt
+	ViewComments
+	ViewAssign
+	ViewProjects
+	ViewSetProject
+	ViewContexts
+	ViewSomeday
+	ViewMatrix
+	ViewBreakdown
+	ViewHabits
+	ViewLinks
+	ViewBoards
+	ViewWeek
+	ViewGraph
+	ViewQuery
+	ViewPassphrase
+	ViewDiary
+	ViewLogNote
 )
 
 type model struct {
 	tasks           []Task
-	globalTasks     []Task
-	localTasks      []Task
+	globalBoard     TaskList
+	localBoard      TaskList
 	selectedCol     int // which priority column
 	selectedTask    int // which task in that column
 	scrollOffset    int // scroll offset for tasks in column
 	colScrollOffset int // horizontal scroll offset for columns
 	mode            ViewMode
 	showingLocal    bool
+	sinkCompleted   bool // list completed tasks after active ones
+	hideCompleted   bool // leave completed tasks out of the columns
+	sortUrgency     bool // order columns by urgency score
+	onlyMine        bool // hide tasks assigned to someone else or no one
+	showLegend      bool // list the board's tags under the columns
+	compact         bool // draw each card on a single line
+	user            string
+	query           *taskQuery
+	workspace       string
+	vault           *vault
+	branch          string // the checked-out git branch, if any
+	projectFilter   bool   // only show tasks of project
+	project         string // active project, empty for tasks without one
+	detail          string // the task shown in the detail popup, by ID
+	unlockAction    string // what to do once the passphrase is in
+	unlockTask      string
+	lockedPath      string    // protected board waiting for its passphrase
+	lastInput       time.Time // of the last key press, to lock when idle
+	projects        []groupSummary
+	projectCursor   int
+	context         string // active GTD context without the @, empty for all
+	contexts        []groupSummary
+	contextCursor   int
+	somedayCursor   int
+	addingSomeday   bool // the add form files into someday/maybe
+	addingHabit     bool // the add form creates a habit
+	habitCursor     int
+	links           []string // the selected task's links, when picking one
+	linkCursor      int
+	boards          []boardChoice // the repository's boards, when picking one
+	boardCursor     int
+	weekCol         int // 0 is unplanned, 1 to 7 Monday to Sunday
+	weekRow         int
+	graphOffset     int
+	diaryDay        time.Time // the day the diary shows
+	diaryOffset     int
+	logReturn       ViewMode // the screen the log prompt goes back to
 	textarea        textarea.Model
+	input           textinput.Model
+	inputErr        string
 	editingTask     *Task
 	width           int
 	height          int
 	globalPath      string
 	localPath       string
 	hasLocal        bool
+	config          Config
+	backups         []backupInfo
+	backupCursor    int
+	backupPreview   []Task
+	backupErr       string
+	corrupt         *corruptBoardError // board that failed to parse, never saved over
+	corruptCopy     string             // where the damaged file was copied
+	corruptErr      string
+	syncer          syncBackend
+	llm             *llmClient // nil unless an API key is configured
+	syncState       syncState
+	syncErr         string
+	syncQueue       []mutation // global board changes not yet on the remote
+	syncDirty       bool       // a sync should run
+	syncBusy        bool       // a sync is in flight
+	pendingEvents   []taskEvent
+	script          *scriptEngine
+	scriptFilter    int // 1-based index of the active script filter, 0 for none
+	scriptErr       string
+	status          string // status bar message
+	statusErr       bool   // status is an error, kept until replaced
+	statusSeq       int    // bumped per message, so stale timers are ignored
+	statusTimer     bool   // a clear timer should start for status
+	keys            keyMap
+	help            help.Model // footer shortcuts, expanded with ?
 }
 
 var (
 	headerStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FBBF24")).
-			Background(lipgloss.Color("#1F2937")).
+			Foreground(colorAccent).
+			Background(colorHeaderBg).
 			Padding(0, 2)
 
 	columnStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			Padding(1, 2).
-			Width(30).
 			Height(20)
 
 	selectedColumnStyle = lipgloss.NewStyle().
 				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("#FBBF24")).
+				BorderForeground(colorAccent).
 				Padding(1, 2).
-				Width(30).
 				Height(20)
 
 	taskCardStyle = lipgloss.NewStyle().
@@ -129,7 +209,7 @@ var (
 
 	selectedTaskStyle = lipgloss.NewStyle().
 				Border(lipgloss.ThickBorder()).
-				BorderForeground(lipgloss.Color("#FBBF24")).
+				BorderForeground(colorAccent).
 				Padding(0, 1).
 				MarginBottom(1).
 				Bold(true)
@@ -138,113 +218,203 @@ var (
 				Border(lipgloss.NormalBorder()).
 				Padding(0, 1).
 				MarginBottom(1).
-				Foreground(lipgloss.Color("#6B7280")).
+				Foreground(colorDone).
 				Strikethrough(true)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF"))
+			Foreground(colorMuted)
 
 	titleStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FBBF24")).
-			Background(lipgloss.Color("#1F2937")).
+			Foreground(colorAccent).
+			Background(colorHeaderBg).
 			Padding(0, 2).
 			MarginBottom(1)
 )
 
-func getGlobalTasksPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "basket-tasks.json"
-	}
-	return filepath.Join(home, "basket-tasks.json")
-}
-
-func getLocalTasksPath() (string, bool) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", false
+// setCompleted marks task done or not done, keeping its completion time
+// in step
+func setCompleted(task *Task, done bool) {
+	if task.Completed == done {
+		return
 	}
-	path := filepath.Join(cwd, ".basket.json")
-	if _, err := os.Stat(path); err == nil {
-		return path, true
+	task.Completed = done
+	task.CompletedAt = nil
+	if done {
+		now := time.Now()
+		task.CompletedAt = &now
 	}
-	return path, false
 }
 
-func loadTasks(path string) ([]Task, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []Task{}, nil
-		}
-		return nil, err
-	}
-
-	var taskList TaskList
-	if err := json.Unmarshal(data, &taskList); err != nil {
-		return nil, err
-	}
-	return taskList.Tasks, nil
+// truncate shortens s to at most width terminal cells, measuring display
+// width rather than bytes so multibyte and wide characters stay intact
+func truncate(s string, width int) string {
+	return runewidth.Truncate(s, width, "...")
 }
 
-func saveTasks(path string, tasks []Task) error {
-	taskList := TaskList{Tasks: tasks}
-	data, err := json.MarshalIndent(taskList, "", "  ")
-	if err != nil {
-		return err
+// wrapLines word-wraps s to width and keeps the first n lines, ending
+// the last in … when some were left out
+func wrapLines(s string, width, n int) []string {
+	lines := strings.Split(lipgloss.NewStyle().Width(width).Render(s), "\n")
+	if len(lines) > n {
+		lines = lines[:n]
+		lines[n-1] = runewidth.Truncate(strings.TrimRight(lines[n-1], " "), width-1, "") + "…"
 	}
-	return os.WriteFile(path, data, 0644)
-}
-
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return lines
 }
 
-func initialModel() model {
+func initialModel(cfg Config) model {
 	ta := textarea.New()
-	ta.Placeholder = "Enter task title..."
+	ta.Placeholder = T("add.placeholder")
 	ta.Focus()
 	ta.CharLimit = 500
 	ta.SetWidth(60)
 	ta.SetHeight(3)
 
+	// One input serves every prompt (goto, queries, passphrases, notes,
+	// assignees), so it has no length limit: a cap would cut long queries
+	// short, even when the active one is shown again
+	ti := textinput.New()
+	ti.Width = 40
+
 	globalPath := getGlobalTasksPath()
 	localPath, hasLocal := getLocalTasksPath()
 
-	globalTasks, _ := loadTasks(globalPath)
-	var localTasks []Task
+	var loadErrs, saveErrs []error
+	var corrupt *corruptBoardError
+	globalBoard, err := loadBoard(globalPath)
+	if err != nil && !errors.As(err, &corrupt) && !isLocked(err) {
+		loadErrs = append(loadErrs, err)
+	}
+
+	syncer := newSyncBackend(cfg)
+	var syncQueue []mutation
+	if syncer != nil {
+		syncQueue = loadSyncQueue(globalBoard.Tasks)
+	}
+
+	beforeStartup := cloneTasks(globalBoard.Tasks)
+	useColumns(globalBoard.Columns)
+	if err := autoArchive(globalPath, &globalBoard, cfg.ArchiveAfterDays); err != nil {
+		saveErrs = append(saveErrs, err)
+	}
+	autoEscalate(globalPath, &globalBoard, cfg.EscalateAfterDays)
+	autoSchedule(globalPath, &globalBoard, cfg.Schedules, "global")
+	if syncer != nil {
+		syncQueue = append(syncQueue, diffTasks(beforeStartup, globalBoard.Tasks)...)
+		saveSyncQueue(syncQueue)
+	}
+	var localBoard TaskList
+	localLocked := false
 	if hasLocal {
-		localTasks, _ = loadTasks(localPath)
+		localBoard, err = loadBoard(localPath)
+		localLocked = isLocked(err)
+		var localCorrupt *corruptBoardError
+		if errors.As(err, &localCorrupt) {
+			corrupt = localCorrupt
+		} else if err != nil && !localLocked {
+			loadErrs = append(loadErrs, err)
+		}
+		useColumns(localBoard.Columns)
+		if err := autoArchive(localPath, &localBoard, cfg.ArchiveAfterDays); err != nil {
+			saveErrs = append(saveErrs, err)
+		}
+		autoEscalate(localPath, &localBoard, cfg.EscalateAfterDays)
+		autoSchedule(localPath, &localBoard, cfg.Schedules, "local")
 	}
 
-	tasks := localTasks
+	tasks := cloneTasks(localBoard.Tasks)
 	showingLocal := true
 
-	if !hasLocal || len(localTasks) == 0 {
-		tasks = globalTasks
+	if !hasLocal || (len(localBoard.Tasks) == 0 && !localLocked) {
+		tasks = cloneTasks(globalBoard.Tasks)
 		showingLocal = false
 		if !hasLocal {
-			localTasks = []Task{}
+			localBoard.Tasks = []Task{}
 		}
 	}
+	if showingLocal {
+		useColumns(localBoard.Columns)
+	} else {
+		useColumns(globalBoard.Columns)
+	}
+
+	state := SyncNone
+	if syncer != nil {
+		state = SyncPending
+	}
 
-	return model{
-		tasks:        tasks,
-		globalTasks:  globalTasks,
-		localTasks:   localTasks,
-		mode:         ViewBoard,
-		showingLocal: showingLocal,
-		textarea:     ta,
-		globalPath:   globalPath,
-		localPath:    localPath,
-		hasLocal:     hasLocal,
-		selectedCol:  2, // Start at MEDIUM
+	script, err := loadScript(getScriptPath())
+	scriptErr := ""
+	if err != nil {
+		logger.Error("load script", "err", err)
+		scriptErr = err.Error()
+	}
+
+	m := model{
+		syncer:        syncer,
+		llm:           newLLMClient(cfg),
+		syncQueue:     syncQueue,
+		script:        script,
+		scriptErr:     scriptErr,
+		syncState:     state,
+		syncBusy:      syncer != nil,
+		tasks:         tasks,
+		globalBoard:   globalBoard,
+		localBoard:    localBoard,
+		mode:          ViewBoard,
+		showingLocal:  showingLocal,
+		textarea:      ta,
+		input:         ti,
+		globalPath:    globalPath,
+		localPath:     localPath,
+		hasLocal:      hasLocal,
+		selectedCol:   int(defaultPriority()), // Start in the middle
+		sinkCompleted: cfg.SinkCompleted,
+		hideCompleted: cfg.HideCompleted,
+		compact:       cfg.Density == densityCompact,
+		sortUrgency:   cfg.SortByUrgency,
+		user:          currentUser(cfg),
+		vault:         newVault(),
+		branch:        gitBranch(),
+		config:        cfg,
+		keys:          newKeyMap(cfg.Keys),
+		help:          help.New(),
+	}
+	if script != nil {
+		if taken := script.dropTakenKeys(m.keys.boardBindings()); len(taken) > 0 {
+			m.scriptErr = fmt.Sprintf(T("status.script_keys"), strings.Join(taken, ", "))
+		}
 	}
+	m.applyBoardSettings()
+	if len(loadErrs) > 0 {
+		m.fail(fmt.Errorf(T("status.load_failed"), loadErrs[0]))
+	} else if len(saveErrs) > 0 {
+		m.fail(fmt.Errorf(T("status.save_failed"), saveErrs[0]))
+	}
+	if needsBoardPick() {
+		m.openBoards()
+	} else if hasLocal {
+		recordOpen(localPath)
+	}
+	if m.mode == ViewBoard && boardLocked(m.currentPath()) {
+		m.promptBoard(m.currentPath())
+	}
+	if corrupt != nil {
+		m.openCorrupt(corrupt)
+	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	if m.syncer != nil {
+		// Pick up remote changes and replay anything queued while offline
+		return tea.Batch(runSync(m.syncer, m.syncQueue), m.relativeTick(), m.lockTick())
+	}
+	return tea.Batch(m.relativeTick(), m.lockTick())
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -252,38 +422,141 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.updateHorizontalScroll()
+		return m, nil
+
+	case syncResultMsg:
+		return m.handleSyncResult(msg)
+
+	case relativeTickMsg:
+		return m, m.relativeTick()
+
+	case lockTickMsg:
+		if boardsUnlocked() && time.Since(m.lastInput) >= m.config.lockAfter() {
+			return m, tea.Batch(m.lockBoards(), m.lockTick())
+		}
+		return m, m.lockTick()
+
+	case syncRetryMsg:
+		m.syncDirty = true
+		return m, m.syncCmd()
+
+	case breakdownMsg:
+		next, cmd := m.handleBreakdown(msg)
+		if nm, ok := next.(model); ok {
+			return nm, tea.Batch(cmd, nm.statusCmd())
+		}
+		return next, cmd
+
+	case hookResultMsg:
+		if len(msg.errs) > 0 {
+			m.fail(msg.errs[0])
+		}
+		return m, nil
+
+	case editorDoneMsg:
+		if msg.err != nil {
+			m.fail(fmt.Errorf(T("status.edit_failed"), msg.err))
+		}
+		return m, nil
+
+	case statusClearMsg:
+		if msg.seq == m.statusSeq && !m.statusErr {
+			m.status = ""
+		}
 		return m, nil
 
 	case tea.KeyMsg:
-		switch m.mode {
-		case ViewBoard:
-			return m.updateBoard(msg)
-		case ViewAdd:
-			return m.updateAdd(msg)
-		case ViewEdit:
-			return m.updateEdit(msg)
-		case ViewHelp:
-			if msg.String() == "esc" || msg.String() == "q" {
-				m.mode = ViewBoard
-			}
-			return m, nil
+		logger.Debug("key", "key", msg.String(), "mode", int(m.mode), "column", m.selectedCol, "task", m.selectedTask)
+		m.lastInput = time.Now()
+		next, cmd := m.updateKey(msg)
+		// Push any edits the key made to the sync remote and run hooks
+		if nm, ok := next.(model); ok {
+			cmd = tea.Batch(cmd, nm.syncCmd(), nm.hooksCmd(), nm.statusCmd())
+			return nm, cmd
 		}
+		return next, cmd
 	}
 
 	return m, nil
 }
 
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.mode {
+	case ViewBoard:
+		return m.updateBoard(msg)
+	case ViewAdd:
+		return m.updateAdd(msg)
+	case ViewEdit:
+		return m.updateEdit(msg)
+	case ViewGoto:
+		return m.updateGoto(msg)
+	case ViewQuery:
+		return m.updateQuery(msg)
+	case ViewPassphrase:
+		return m.updatePassphrase(msg)
+	case ViewBackups:
+		return m.updateBackups(msg)
+	case ViewCorrupt:
+		return m.updateCorrupt(msg)
+	case ViewComments:
+		return m.updateComments(msg)
+	case ViewAssign:
+		return m.updateAssign(msg)
+	case ViewProjects:
+		return m.updateProjects(msg)
+	case ViewSetProject:
+		return m.updateSetProject(msg)
+	case ViewContexts:
+		return m.updateContexts(msg)
+	case ViewSomeday:
+		return m.updateSomeday(msg)
+	case ViewHabits:
+		return m.updateHabits(msg)
+	case ViewLinks:
+		return m.updateLinks(msg)
+	case ViewBoards:
+		return m.updateBoards(msg)
+	case ViewWeek:
+		return m.updateWeek(msg)
+	case ViewGraph:
+		return m.updateGraph(msg)
+	case ViewMatrix:
+		return m.updateMatrix(msg)
+	case ViewBreakdown:
+		return m.updateBreakdown(msg)
+	case ViewDiary:
+		return m.updateDiary(msg)
+	case ViewLogNote:
+		return m.updateLogNote(msg)
+	}
+	return m, nil
+}
+
 func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "q":
+	if m.detailTask() != nil {
+		return m.updateDetail(msg)
+	}
+	switch {
+	case key.Matches(msg, m.keys.Column):
+		if i := slices.Index(m.keys.Column.Keys(), msg.String()); i < len(m.shownColumns()) {
+			m.jumpToColumn(int(m.shownColumns()[i]))
+		}
+
+	case key.Matches(msg, m.keys.Quit):
 		return m, tea.Quit
 
-	case "left", "h":
-		if m.selectedCol > 0 {
-			m.selectedCol--
-		} else {
-			m.selectedCol = 4
+	case key.Matches(msg, m.keys.Left):
+		shown := m.shownColumns()
+		i := m.shownIndex(Priority(m.selectedCol))
+		if shown[i] == Priority(m.selectedCol) {
+			i--
 		}
+		if i < 0 {
+			i = len(shown) - 1
+		}
+		m.selectedCol = int(shown[i])
 		tasksInNewCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInNewCol) == 0 {
 			m.selectedTask = 0
@@ -292,12 +565,9 @@ func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.updateHorizontalScroll()
 
-	case "right", "l":
-		if m.selectedCol < 4 {
-			m.selectedCol++
-		} else {
-			m.selectedCol = 0
-		}
+	case key.Matches(msg, m.keys.Right):
+		shown := m.shownColumns()
+		m.selectedCol = int(shown[(m.shownIndex(Priority(m.selectedCol))+1)%len(shown)])
 		tasksInNewCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInNewCol) == 0 {
 			m.selectedTask = 0
@@ -306,7 +576,7 @@ func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.updateHorizontalScroll()
 
-	case "up", "k":
+	case key.Matches(msg, m.keys.Up):
 		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if m.selectedTask > 0 && len(tasksInCol) > 0 {
 			m.selectedTask--
@@ -316,51 +586,62 @@ func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "down", "j":
+	case key.Matches(msg, m.keys.Down):
 		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol)-1 {
 			m.selectedTask++
-			maxVisible := 8
+			maxVisible := m.tasksPerPage()
 			if m.selectedTask >= m.scrollOffset+maxVisible {
 				m.scrollOffset = m.selectedTask - maxVisible + 1
 			}
 		}
 
-	case " ", "enter":
+	case key.Matches(msg, m.keys.Details):
+		m.openDetail()
+
+	case key.Matches(msg, m.keys.Toggle):
 		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
 			for i := range m.tasks {
 				if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
-					m.tasks[i].Completed = !m.tasks[i].Completed
-					m.saveCurrent()
+					setCompleted(&m.tasks[i], !m.tasks[i].Completed)
+					if m.tasks[i].Completed {
+						m.commit(T("status.completed"))
+					} else {
+						m.commit(T("status.reopened"))
+					}
+					m.clampSelection()
 					break
 				}
 			}
 		}
 
-	case "n":
+	case key.Matches(msg, m.keys.New):
 		m.mode = ViewAdd
+		m.addingSomeday = false
+		m.addingHabit = false
 		m.textarea.Reset()
-		m.textarea.Placeholder = "Enter task title..."
+		m.textarea.Placeholder = T("add.placeholder")
 		m.textarea.SetHeight(3)
 		return m, m.textarea.Focus()
 
-	case "e":
+	case key.Matches(msg, m.keys.Edit):
 		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
-			for i := range m.tasks {
-				if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
-					m.mode = ViewEdit
-					m.editingTask = &m.tasks[i]
-					m.textarea.SetValue(m.editingTask.Description)
-					m.textarea.Placeholder = "Enter task description..."
-					m.textarea.SetHeight(10)
-					return m, m.textarea.Focus()
-				}
+			task := tasksInCol[m.selectedTask]
+			if isSealed(task.Description) {
+				return m, m.unlockThen("edit", task.ID)
 			}
+			return m, m.editTask(task.ID)
 		}
 
-	case "d":
+	case key.Matches(msg, m.keys.Seal):
+		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
+			return m, m.unlockThen("seal", tasksInCol[m.selectedTask].ID)
+		}
+
+	case key.Matches(msg, m.keys.Delete):
 		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
 			taskID := tasksInCol[m.selectedTask].ID
@@ -370,20 +651,21 @@ func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if m.selectedTask >= len(m.getTasksInColumn(Priority(m.selectedCol))) && m.selectedTask > 0 {
 						m.selectedTask--
 					}
-					m.saveCurrent()
+					m.commit(T("status.deleted"))
 					break
 				}
 			}
 		}
 
-	case "m":
+	case key.Matches(msg, m.keys.Move):
 		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
 		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
 			for i := range m.tasks {
 				if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
-					newPriority := (m.tasks[i].Priority + 1) % 5
+					shown := m.shownColumns()
+					newPriority := shown[(m.shownIndex(m.tasks[i].Priority)+1)%len(shown)]
 					m.tasks[i].Priority = newPriority
-					m.saveCurrent()
+					m.commit(fmt.Sprintf(T("status.moved"), newPriority))
 
 					m.selectedCol = int(newPriority)
 					tasksInNewCol := m.getTasksInColumn(newPriority)
@@ -398,49 +680,280 @@ func (m model) updateBoard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "t":
+	case key.Matches(msg, m.keys.Share):
+		m.shareSelected()
+
+	case key.Matches(msg, m.keys.Open):
+		m.openSelectedLinks()
+
+	case key.Matches(msg, m.keys.Code):
+		return m, m.openSelectedLocation()
+
+	case key.Matches(msg, m.keys.Comments):
+		return m, m.openComments()
+
+	case key.Matches(msg, m.keys.Assign):
+		return m, m.openAssign()
+
+	case key.Matches(msg, m.keys.SetProject):
+		return m, m.openSetProject()
+
+	case key.Matches(msg, m.keys.Projects):
+		m.openProjects()
+
+	case key.Matches(msg, m.keys.Contexts):
+		m.openContexts()
+
+	case key.Matches(msg, m.keys.Defer):
+		m.deferSelected()
+
+	case key.Matches(msg, m.keys.Split):
+		cmd := m.askBreakdown()
+		return m, cmd
+
+	case key.Matches(msg, m.keys.Urgent):
+		m.toggleUrgent()
+
+	case key.Matches(msg, m.keys.Pin):
+		m.togglePinned()
+
+	case key.Matches(msg, m.keys.Raise):
+		m.moveInColumn(-1)
+
+	case key.Matches(msg, m.keys.Lower):
+		m.moveInColumn(1)
+
+	case key.Matches(msg, m.keys.Timer):
+		m.toggleTimer()
+
+	case key.Matches(msg, m.keys.Matrix):
+		m.mode = ViewMatrix
+
+	case key.Matches(msg, m.keys.Someday):
+		m.openSomeday()
+
+	case key.Matches(msg, m.keys.Habits):
+		m.openHabits()
+
+	case key.Matches(msg, m.keys.Diary):
+		m.openDiary()
+
+	case key.Matches(msg, m.keys.Log):
+		return m, m.openLogNote()
+
+	case key.Matches(msg, m.keys.Boards):
+		m.openBoards()
+
+	case key.Matches(msg, m.keys.Week):
+		m.openWeek()
+
+	case key.Matches(msg, m.keys.Graph):
+		m.openGraph()
+
+	case key.Matches(msg, m.keys.Mine):
+		m.onlyMine = !m.onlyMine
+		m.selectedTask = 0
+		m.scrollOffset = 0
+
+	case key.Matches(msg, m.keys.Switch):
 		if m.hasLocal {
-			m.showingLocal = !m.showingLocal
-			if m.showingLocal {
-				m.tasks = m.localTasks
-			} else {
-				m.tasks = m.globalTasks
-			}
-			// Reset position
-			m.selectedCol = 2
-			m.selectedTask = 0
-			m.scrollOffset = 0
-			m.colScrollOffset = 0
-			m.updateHorizontalScroll()
+			m.showBoard(!m.showingLocal)
 		} else {
 			// If no local file exists, create it by switching to local mode
 			m.showingLocal = true
 			m.hasLocal = true
-			m.localTasks = []Task{}
-			m.tasks = m.localTasks
-			m.selectedCol = 2
+			m.localBoard = TaskList{Tasks: []Task{}}
+			m.tasks = cloneTasks(m.localBoard.Tasks)
+			m.useBoardColumns()
+			m.applyBoardSettings()
 			m.selectedTask = 0
 			m.scrollOffset = 0
 			m.colScrollOffset = 0
 			m.updateHorizontalScroll()
 		}
 
-	case "?":
-		m.mode = ViewHelp
+	case key.Matches(msg, m.keys.Sink):
+		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+		m.sinkCompleted = !m.sinkCompleted
+		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
+			m.selectTask(tasksInCol[m.selectedTask].ID)
+		}
+
+	case key.Matches(msg, m.keys.Hide):
+		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+		m.hideCompleted = !m.hideCompleted
+		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
+			m.selectTask(tasksInCol[m.selectedTask].ID)
+		}
+		m.clampSelection()
+
+	case key.Matches(msg, m.keys.ByUrgency):
+		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+		m.sortUrgency = !m.sortUrgency
+		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
+			m.selectTask(tasksInCol[m.selectedTask].ID)
+		}
+
+	case key.Matches(msg, m.keys.Jump):
+		// Only leads a column number, as in g2, which jumps either way
+
+	case key.Matches(msg, m.keys.Legend):
+		m.showLegend = !m.showLegend
+
+	case key.Matches(msg, m.keys.Density):
+		tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+		m.compact = !m.compact
+		if len(tasksInCol) > 0 && m.selectedTask < len(tasksInCol) {
+			m.selectTask(tasksInCol[m.selectedTask].ID)
+		}
+
+	case key.Matches(msg, m.keys.Goto):
+		m.mode = ViewGoto
+		m.inputErr = ""
+		m.input.Reset()
+		m.input.Placeholder = T("goto.placeholder")
+		return m, m.input.Focus()
+
+	case key.Matches(msg, m.keys.Query):
+		m.mode = ViewQuery
+		m.inputErr = ""
+		m.input.Reset()
+		m.input.SetValue(m.query.String())
+		m.input.Placeholder = T("query.placeholder")
+		return m, m.input.Focus()
+
+	case key.Matches(msg, m.keys.Backups):
+		m.openBackups()
+
+	case key.Matches(msg, m.keys.Sync):
+		if m.syncer != nil {
+			m.syncDirty = true
+		}
+
+	case key.Matches(msg, m.keys.Filter):
+		if m.script != nil && len(m.script.filters) > 0 {
+			m.scriptFilter = (m.scriptFilter + 1) % (len(m.script.filters) + 1)
+			m.selectedTask = 0
+			m.scrollOffset = 0
+		}
+
+	case key.Matches(msg, m.keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+
+	case key.Matches(msg, m.keys.Cancel):
+		m.status = ""
+		m.statusErr = false
+
+	default:
+		if m.script != nil {
+			if _, ok := m.script.commands[msg.String()]; ok {
+				m.runScriptCommand(msg.String())
+			}
+		}
 	}
 
 	return m, nil
 }
 
-func (m model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// runScriptCommand applies a user script command to the selected task
+func (m *model) runScriptCommand(key string) {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID != tasksInCol[m.selectedTask].ID {
+			continue
+		}
+		updated, err := m.script.runCommand(key, m.tasks[i])
+		if err != nil {
+			logger.Error("script command", "key", key, "err", err)
+			m.scriptErr = err.Error()
+			return
+		}
+		m.scriptErr = ""
+		m.tasks[i] = updated
+		m.commit(fmt.Sprintf(T("status.ran"), m.script.commands[key].Name))
+		m.selectTask(updated.ID)
+		return
+	}
+}
+
+func (m model) updateGoto(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
-	switch msg.String() {
-	case "esc":
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
 		m.mode = ViewBoard
 		return m, nil
 
-	case "ctrl+s":
+	case key.Matches(msg, m.keys.Confirm):
+		i, ok := findTask(m.tasks, m.input.Value())
+		if !ok {
+			m.inputErr = fmt.Sprintf(T("goto.not_found"), strings.TrimSpace(m.input.Value()))
+			return m, nil
+		}
+		m.selectTask(m.tasks[i].ID)
+		m.mode = ViewBoard
+		return m, nil
+	}
+
+	m.inputErr = ""
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// selectTask moves the cursor to the task with the given ID, switching
+// columns and scrolling as needed
+func (m *model) selectTask(id string) {
+	for _, task := range m.tasks {
+		if task.ID != id {
+			continue
+		}
+		m.selectedCol = int(clampPriority(task.Priority))
+		for idx, t := range m.getTasksInColumn(clampPriority(task.Priority)) {
+			if t.ID == id {
+				m.selectedTask = idx
+				break
+			}
+		}
+		maxVisible := m.tasksPerPage()
+		if m.selectedTask < m.scrollOffset {
+			m.scrollOffset = m.selectedTask
+		} else if m.selectedTask >= m.scrollOffset+maxVisible {
+			m.scrollOffset = m.selectedTask - maxVisible + 1
+		}
+		m.updateHorizontalScroll()
+		return
+	}
+}
+
+// addReturnMode is the screen the add form was opened from
+func (m model) addReturnMode() ViewMode {
+	switch {
+	case m.addingSomeday:
+		return ViewSomeday
+	case m.addingHabit:
+		return ViewHabits
+	}
+	return ViewBoard
+}
+
+func (m model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = m.addReturnMode()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Complete):
+		if tags := tagSuggestions(m.tasks, m.textarea.Value()); len(tags) > 0 {
+			m.textarea.SetValue(completeTag(m.textarea.Value(), tags[0]))
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Save):
 		title := strings.TrimSpace(m.textarea.Value())
 		if title != "" {
 			newTask := Task{
@@ -448,11 +961,21 @@ func (m model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				Title:     title,
 				Priority:  Priority(m.selectedCol),
 				CreatedAt: time.Now(),
+				Project:   m.project,
+				Someday:   m.addingSomeday,
+				Habit:     m.addingHabit,
 			}
 			m.tasks = append(m.tasks, newTask)
-			m.saveCurrent()
+			switch {
+			case m.addingSomeday:
+				m.commit(T("status.deferred"))
+			case m.addingHabit:
+				m.commit(T("status.habit_added"))
+			default:
+				m.commit(fmt.Sprintf(T("status.added"), newTask.Priority))
+			}
 		}
-		m.mode = ViewBoard
+		m.mode = m.addReturnMode()
 		return m, nil
 	}
 
@@ -463,16 +986,31 @@ func (m model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
-	switch msg.String() {
-	case "esc":
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
 		m.mode = ViewBoard
 		m.editingTask = nil
 		return m, nil
 
-	case "ctrl+s":
+	case key.Matches(msg, m.keys.Complete):
+		if tags := tagSuggestions(m.tasks, m.textarea.Value()); len(tags) > 0 {
+			m.textarea.SetValue(completeTag(m.textarea.Value(), tags[0]))
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Save):
 		if m.editingTask != nil {
-			m.editingTask.Description = strings.TrimSpace(m.textarea.Value())
-			m.saveCurrent()
+			text := strings.TrimSpace(m.textarea.Value())
+			if m.editingTask.Sensitive {
+				sealed, err := m.vault.seal(text)
+				if err != nil {
+					m.fail(err)
+					return m, nil
+				}
+				text = sealed
+			}
+			m.editingTask.Description = text
+			m.commit(T("status.saved"))
 		}
 		m.mode = ViewBoard
 		m.editingTask = nil
@@ -483,61 +1021,255 @@ func (m model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *model) saveCurrent() {
-	if m.showingLocal {
-		m.localTasks = make([]Task, len(m.tasks))
-		copy(m.localTasks, m.tasks)
-		if !m.hasLocal {
-			m.hasLocal = true
+// editTask opens the edit form on a task's description
+func (m *model) editTask(id string) tea.Cmd {
+	for i := range m.tasks {
+		if m.tasks[i].ID == id {
+			m.mode = ViewEdit
+			m.editingTask = &m.tasks[i]
+			m.textarea.SetValue(m.description(m.tasks[i]))
+			m.textarea.Placeholder = T("edit.placeholder")
+			m.textarea.SetHeight(10)
+			return m.textarea.Focus()
 		}
-		saveTasks(m.localPath, m.localTasks)
+	}
+	return nil
+}
+
+// currentPath is the file backing the board on screen
+func (m model) currentPath() string {
+	if m.showingLocal {
+		return m.localPath
+	}
+	return m.globalPath
+}
+
+// reloadCurrent rereads the board on screen from disk
+func (m *model) reloadCurrent() error {
+	board, err := loadBoard(m.currentPath())
+	if err != nil {
+		return err
+	}
+	if m.showingLocal {
+		m.localBoard = board
+	} else {
+		m.globalBoard = board
+	}
+	m.tasks = cloneTasks(board.Tasks)
+	m.useBoardColumns()
+	m.corrupt = nil
+	m.selectedTask = 0
+	m.scrollOffset = 0
+	return nil
+}
+
+// showBoard puts the local or the global board on screen, from the top
+func (m *model) showBoard(local bool) {
+	m.showingLocal = local
+	if path := m.currentPath(); boardLocked(path) {
+		m.promptBoard(path)
+		return
+	}
+	if local {
+		m.tasks = cloneTasks(m.localBoard.Tasks)
 	} else {
-		m.globalTasks = make([]Task, len(m.tasks))
-		copy(m.globalTasks, m.tasks)
-		saveTasks(m.globalPath, m.globalTasks)
+		m.tasks = cloneTasks(m.globalBoard.Tasks)
+	}
+	m.useBoardColumns()
+	m.projectFilter = false
+	m.project = ""
+	m.context = ""
+	m.applyBoardSettings()
+	m.selectedTask = 0
+	m.scrollOffset = 0
+	m.colScrollOffset = 0
+	m.updateHorizontalScroll()
+}
+
+// useBoardColumns switches the levels to the columns of the board on
+// screen
+func (m *model) useBoardColumns() {
+	board := m.globalBoard
+	if m.showingLocal {
+		board = m.localBoard
+	}
+	useColumns(board.Columns)
+	m.selectedCol = min(m.selectedCol, int(maxPriority()))
+}
+
+// saveCurrent writes the board on screen to disk. The edit is journaled
+// first, so it survives a crash before the write completes.
+func (m *model) saveCurrent() error {
+	path, board := m.globalPath, &m.globalBoard
+	if m.showingLocal {
+		path, board = m.localPath, &m.localBoard
+	}
+	if m.isCorrupt(path) {
+		return fmt.Errorf("%s is damaged and was not overwritten", path)
+	}
+	if m.showingLocal {
+		m.hasLocal = true
+	}
+
+	ensureRanks(m.tasks)
+	before := board.Tasks
+	touchTasks(board.Tasks, m.tasks, time.Now())
+	muts := diffTasks(board.Tasks, m.tasks)
+	if err := appendJournal(path, muts); err != nil {
+		logger.Error("journal", "path", path, "err", err)
+	}
+	if !m.showingLocal {
+		m.queueSync(muts)
+	}
+
+	board.Tasks = make([]Task, len(m.tasks))
+	copy(board.Tasks, m.tasks)
+	if err := saveBoard(path, *board); err != nil {
+		return err
+	}
+	m.pendingEvents = append(m.pendingEvents, savedEvents(path, before, board.Tasks)...)
+	return clearJournal(path)
+}
+
+// commit saves the board on screen and confirms with done in the status
+// bar, or reports why saving failed
+func (m *model) commit(done string) {
+	if err := m.saveCurrent(); err != nil {
+		m.fail(fmt.Errorf(T("status.save_failed"), err))
+		return
+	}
+	m.notify(done)
+}
+
+// passesScriptFilter applies the script filter selected with f
+func (m model) passesScriptFilter(task Task) bool {
+	if m.script == nil || m.scriptFilter == 0 {
+		return true
+	}
+	ok, err := m.script.match(m.scriptFilter-1, task)
+	return err == nil && ok
+}
+
+// clampSelection keeps the cursor on an existing task after the board
+// changed underneath it
+func (m *model) clampSelection() {
+	n := len(m.getTasksInColumn(Priority(m.selectedCol)))
+	if m.selectedTask >= n {
+		m.selectedTask = n - 1
+	}
+	if m.selectedTask < 0 {
+		m.selectedTask = 0
+	}
+	if m.scrollOffset > m.selectedTask {
+		m.scrollOffset = m.selectedTask
 	}
 }
 
 func (m model) getTasksInColumn(priority Priority) []Task {
+	if !m.hideCompleted {
+		return m.columnTasks(priority)
+	}
 	var tasks []Task
+	for _, task := range m.columnTasks(priority) {
+		if !task.Completed {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// columnTasks is every task of a column that passes the filters, completed
+// ones included even while they are hidden
+func (m model) columnTasks(priority Priority) []Task {
+	var tasks []Task
+	now := time.Now()
 	for _, task := range m.tasks {
-		if task.Priority == priority {
+		if m.onlyMine && !sameAssignee(task.Assignee, m.user) {
+			continue
+		}
+		if offBoard(task) || !m.inProject(task) || !m.inContext(task) {
+			continue
+		}
+		if clampPriority(task.Priority) == priority && m.passesScriptFilter(task) && m.query.Match(task, m.tasks, now) {
 			tasks = append(tasks, task)
 		}
 	}
+	sortByRank(tasks)
+	if m.sortUrgency {
+		sortByUrgency(tasks, now)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return cardGroup(tasks[i], m.sinkCompleted) < cardGroup(tasks[j], m.sinkCompleted)
+	})
 	return tasks
 }
 
+// jumpToColumn selects column col, keeping the cursor's row where it can
+func (m *model) jumpToColumn(col int) {
+	if col < 0 || col > int(maxPriority()) {
+		return
+	}
+	m.selectedCol = col
+	tasksInNewCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInNewCol) == 0 {
+		m.selectedTask = 0
+	} else if m.selectedTask >= len(tasksInNewCol) {
+		m.selectedTask = len(tasksInNewCol) - 1
+	}
+	m.updateHorizontalScroll()
+}
+
 func (m *model) updateHorizontalScroll() {
-	visibleCols := 3
+	visibleCols, _ := m.columnLayout()
+	maxScroll := len(m.shownColumns()) - visibleCols
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
 
-	desiredScroll := m.selectedCol - (visibleCols / 2)
+	desiredScroll := m.shownIndex(Priority(m.selectedCol)) - (visibleCols / 2)
 
 	if desiredScroll < 0 {
 		m.colScrollOffset = 0
-	} else if desiredScroll > 5-visibleCols {
-		m.colScrollOffset = 5 - visibleCols
+	} else if desiredScroll > maxScroll {
+		m.colScrollOffset = maxScroll
 	} else {
 		m.colScrollOffset = desiredScroll
 	}
 }
 
+// Column widths, borders included. Columns are laid out at the preferred
+// width, then stretched to fill the terminal up to the maximum; when
+// fewer than three fit, they shrink down to the minimum first.
+const (
+	minColumnWidth       = 22
+	preferredColumnWidth = 32
+	maxColumnWidth       = 56
+)
+
+// columnLayout is how many columns the board shows and how wide each is
+func (m model) columnLayout() (visible, width int) {
+	n := len(m.shownColumns())
+	board := m.boardWidth() - 2 // the scroll arrows
+	if m.width == 0 {
+		return min(3, n), preferredColumnWidth
+	}
+	visible = board / preferredColumnWidth
+	if visible < 3 {
+		visible = min(3, board/minColumnWidth)
+	}
+	visible = max(min(visible, n), 1)
+	width = min(max(board/visible, minColumnWidth), maxColumnWidth)
+	return visible, width
+}
+
 func (m model) getVisibleColumns() (int, int) {
-	if m.width >= 160 {
-		return 0, 5
-	} else if m.width >= 128 {
-		start := m.colScrollOffset
-		if start > 1 {
-			start = 1
-		}
-		return start, start + 4
-	} else {
-		start := m.colScrollOffset
-		if start > 2 {
-			start = 2
-		}
-		return start, start + 3
+	visible, _ := m.columnLayout()
+	n := len(m.shownColumns())
+	start := m.colScrollOffset
+	if start > n-visible {
+		start = n - visible
 	}
+	return start, start + visible
 }
 
 func (m model) View() string {
@@ -546,9 +1278,50 @@ func (m model) View() string {
 		return m.viewAdd()
 	case ViewEdit:
 		return m.viewEdit()
-	case ViewHelp:
-		return m.viewHelp()
+	case ViewGoto:
+		return m.viewGoto()
+	case ViewQuery:
+		return m.viewQuery()
+	case ViewPassphrase:
+		return m.viewPassphrase()
+	case ViewBackups:
+		return m.viewBackups()
+	case ViewCorrupt:
+		return m.viewCorrupt()
+	case ViewComments:
+		return m.viewComments()
+	case ViewAssign:
+		return m.viewAssign()
+	case ViewProjects:
+		return m.viewProjects()
+	case ViewSetProject:
+		return m.viewSetProject()
+	case ViewContexts:
+		return m.viewContexts()
+	case ViewSomeday:
+		return m.viewSomeday()
+	case ViewHabits:
+		return m.viewHabits()
+	case ViewLinks:
+		return m.viewLinks()
+	case ViewBoards:
+		return m.viewBoards()
+	case ViewWeek:
+		return m.viewWeek()
+	case ViewGraph:
+		return m.viewGraph()
+	case ViewMatrix:
+		return m.viewMatrix()
+	case ViewBreakdown:
+		return m.viewBreakdown()
+	case ViewDiary:
+		return m.viewDiary()
+	case ViewLogNote:
+		return m.viewLogNote()
 	default:
+		if box := m.renderDetail(); box != "" {
+			return overlay(m.viewBoard(), box, m.width, m.height)
+		}
 		return m.viewBoard()
 	}
 }
@@ -557,20 +1330,54 @@ func (m model) viewBoard() string {
 	var b strings.Builder
 
 	// Header
-	source := "🌍 GLOBAL"
+	source := T("header.global")
 	if m.showingLocal {
-		source = "📂 LOCAL"
+		source = T("header.local")
+	}
+	header := headerStyle.Render(fmt.Sprintf(T("header.title"), source))
+	if m.workspace != "" {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.workspace"), m.workspace))
+	}
+	if n := len(m.somedayTasks()); n > 0 {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.someday"), n))
+	}
+	if m.script != nil && m.scriptFilter > 0 {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.filter"), m.script.filters[m.scriptFilter-1].Name))
+	}
+	if m.query != nil {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.query"), truncate(m.query.String(), 40)))
+	}
+	if m.projectFilter {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.project"), projectLabel(m.project)))
+	}
+	if m.context != "" {
+		header += helpStyle.Render(" @" + m.context)
+	}
+	if m.onlyMine {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.only_mine"), m.user))
 	}
-	header := headerStyle.Render(fmt.Sprintf("  🧺 BASKET  %s  ", source))
-	b.WriteString(header + "\n\n")
+	if m.sortUrgency {
+		header += helpStyle.Render(" " + T("board.by_urgency"))
+	}
+	if hidden := numPriorities() - len(m.shownColumns()); hidden > 0 {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.hidden_cols"), hidden))
+	}
+	if task, ok := branchTask(m.tasks, m.branch); ok {
+		header += helpStyle.Render(" " + fmt.Sprintf(T("board.branch"), truncate(task.Title, 30)))
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, header, m.renderSyncStatus()) + "\n")
+	if m.scriptErr != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(colorError).Render(m.scriptErr))
+	}
+	b.WriteString("\n")
 
 	startCol, endCol := m.getVisibleColumns()
-	priorities := []Priority{PriorityLowest, PriorityLow, PriorityMedium, PriorityHigh, PriorityHighest}
+	priorities := m.shownColumns()
 
 	var visibleColumns []string
-	for i := startCol; i < endCol && i < 5; i++ {
+	for i := startCol; i < endCol && i < len(priorities); i++ {
 		priority := priorities[i]
-		column := m.renderColumn(priority, i == m.selectedCol)
+		column := m.renderColumn(priority, int(priority) == m.selectedCol)
 		visibleColumns = append(visibleColumns, column)
 	}
 
@@ -578,7 +1385,7 @@ func (m model) viewBoard() string {
 
 	if startCol > 0 {
 		leftIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FBBF24")).
+			Foreground(colorAccent).
 			Bold(true).
 			Render("◀")
 		columnsWithIndicators = append(columnsWithIndicators, leftIndicator)
@@ -586,19 +1393,26 @@ func (m model) viewBoard() string {
 
 	columnsWithIndicators = append(columnsWithIndicators, visibleColumns...)
 
-	if endCol < 5 {
+	if endCol < len(priorities) {
 		rightIndicator := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FBBF24")).
+			Foreground(colorAccent).
 			Bold(true).
 			Render("▶")
 		columnsWithIndicators = append(columnsWithIndicators, rightIndicator)
 	}
 
+	if m.previewShown() {
+		columnsWithIndicators = append(columnsWithIndicators, m.renderPreview())
+	}
+
 	columnsJoined := lipgloss.JoinHorizontal(lipgloss.Top, columnsWithIndicators...)
-	b.WriteString(columnsJoined + "\n\n")
+	b.WriteString(columnsJoined + "\n")
+	if m.showLegend {
+		b.WriteString(m.renderTagLegend(m.width) + "\n")
+	}
+	b.WriteString(m.renderStatus() + "\n")
 
-	help := helpStyle.Render("h/l columns • j/k tasks • space toggle • m move • n new • e edit • d delete • t switch • ? help • q quit")
-	b.WriteString(help)
+	b.WriteString(m.renderFooter())
 
 	return b.String()
 }
@@ -606,22 +1420,43 @@ func (m model) viewBoard() string {
 func (m model) renderColumn(priority Priority, isSelected bool) string {
 	var b strings.Builder
 
-	headerText := priority.String()
+	all := m.columnTasks(priority)
+	tasks := m.getTasksInColumn(priority)
+	_, width := m.columnLayout()
+	inner := width - 6 // less the border and padding
+
+	done := 0
+	for _, task := range all {
+		if task.Completed {
+			done++
+		}
+	}
+	headerText := fmt.Sprintf(T("board.column_count"), priority.String(), done, len(all))
+	if len(all) > 0 {
+		headerText += fmt.Sprintf(" %d%%", done*100/len(all))
+	}
+	headerColor := priority.Color()
+	if limit := m.boardSettings().wipLimit(priority); limit > 0 {
+		headerText += fmt.Sprintf(" "+T("board.wip"), len(all)-done, limit)
+		if len(all)-done > limit {
+			headerColor = colorError
+		}
+	}
 	if isSelected {
 		headerText = "▶ " + headerText + " ◀"
 	}
 	colHeader := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(priority.Color()).
-		Width(26).
+		Foreground(headerColor).
+		Width(inner).
 		Align(lipgloss.Center).
 		Render(headerText)
 
 	b.WriteString(colHeader + "\n")
 
-	separator := strings.Repeat("─", 26)
+	separator := strings.Repeat("─", inner)
 	if isSelected {
-		separator = strings.Repeat("═", 26)
+		separator = strings.Repeat("═", inner)
 	}
 	sepStyle := lipgloss.NewStyle()
 	if isSelected {
@@ -629,16 +1464,14 @@ func (m model) renderColumn(priority Priority, isSelected bool) string {
 	}
 	b.WriteString(sepStyle.Render(separator) + "\n\n")
 
-	tasks := m.getTasksInColumn(priority)
-
-	if len(tasks) == 0 {
+	if len(tasks) == 0 && len(tasks) == len(all) {
 		emptyText := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#4B5563")).
+			Foreground(colorFaint).
 			Italic(true).
-			Render("No tasks")
+			Render(T("board.empty"))
 		b.WriteString(emptyText + "\n")
 	} else {
-		maxVisible := 8
+		maxVisible := m.tasksPerPage()
 		start := 0
 		end := len(tasks)
 
@@ -652,24 +1485,27 @@ func (m model) renderColumn(priority Priority, isSelected bool) string {
 
 		if isSelected && start > 0 {
 			indicator := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#9CA3AF")).
-				Render("    ▲ more above")
+				Foreground(colorMuted).
+				Render(T("board.more_above"))
 			b.WriteString(indicator + "\n")
 		}
 
 		for i := start; i < end; i++ {
 			task := tasks[i]
 			isTaskSelected := isSelected && i == m.selectedTask
-			b.WriteString(m.renderTask(task, isTaskSelected) + "\n")
+			b.WriteString(m.renderTask(task, isTaskSelected, inner-2) + "\n")
 		}
 
 		if isSelected && end < len(tasks) {
 			indicator := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#9CA3AF")).
-				Render("    ▼ more below")
+				Foreground(colorMuted).
+				Render(T("board.more_below"))
 			b.WriteString(indicator + "\n")
 		}
 	}
+	if hidden := len(all) - len(tasks); hidden > 0 {
+		b.WriteString(helpStyle.Render(fmt.Sprintf(T("board.hidden_done"), hidden)) + "\n")
+	}
 
 	content := b.String()
 
@@ -677,12 +1513,16 @@ func (m model) renderColumn(priority Priority, isSelected bool) string {
 	if isSelected {
 		style = selectedColumnStyle
 	}
-	style = style.BorderForeground(priority.Color())
+	style = style.BorderForeground(priority.Color()).Width(width - 2)
 
 	return style.Render(content)
 }
 
-func (m model) renderTask(task Task, isSelected bool) string {
+// renderTask draws a card width columns wide, border included
+func (m model) renderTask(task Task, isSelected bool, width int) string {
+	if m.compact {
+		return m.renderCompactTask(task, isSelected, width)
+	}
 	var b strings.Builder
 
 	checkbox := "☐"
@@ -690,12 +1530,30 @@ func (m model) renderTask(task Task, isSelected bool) string {
 		checkbox = "☑"
 	}
 
-	title := task.Title
-	if len(title) > 20 {
-		title = title[:17] + "..."
+	titleLines := []string{truncate(task.Title, width-6)} // less the border, padding and checkbox
+	if m.config.TitleLines > 1 {
+		titleLines = wrapLines(task.Title, width-6, m.config.TitleLines)
+	}
+	if urls := taskLinks(task, m.config); len(urls) > 0 && m.config.linksEnabled() {
+		for i := range titleLines {
+			titleLines[i] = hyperlink(titleLines[i], urls[0])
+		}
 	}
+	// Wrapped lines line up under the first, past the checkbox
+	title := strings.Join(titleLines, "\n  ")
 
 	content := fmt.Sprintf("%s %s", checkbox, title)
+	if isSelected {
+		if desc := m.cardDescription(task, width-4); desc != "" {
+			content += "\n" + desc
+		}
+	}
+	if extras := m.cardBadges(task); len(extras) > 0 {
+		content += "\n" + helpStyle.Render(strings.Join(extras, " "))
+	}
+	if chips := renderTagChips(task, m.config.TagColors); chips != "" {
+		content += "\n" + chips
+	}
 
 	style := taskCardStyle
 	if isSelected {
@@ -703,93 +1561,206 @@ func (m model) renderTask(task Task, isSelected bool) string {
 	} else if task.Completed {
 		style = completedTaskStyle
 	}
+	if accent := m.cardAccent(task); accent != nil {
+		style = style.BorderLeftForeground(accent)
+	}
 
-	b.WriteString(style.Width(22).Render(content))
+	b.WriteString(style.Width(width - 2).Render(content))
 
 	return b.String()
 }
 
+// cardBadges are the small markers under a card's title
+func (m model) cardBadges(task Task) []string {
+	var extras []string
+	if m.config.ShowIDs {
+		extras = append(extras, "#"+shortID(task.ID))
+	}
+	if task.Pinned {
+		extras = append(extras, "📌")
+	}
+	if task.Urgent {
+		extras = append(extras, "⚡")
+	}
+	if task.Sensitive {
+		extras = append(extras, "🔒")
+	}
+	if refs := issueRefs(task, m.config.IssueLinks); len(refs) > 0 {
+		badge := "↗" + refs[0].Text
+		if m.config.linksEnabled() {
+			badge = hyperlink(badge, refs[0].URL)
+		}
+		if len(refs) > 1 {
+			badge += fmt.Sprintf("+%d", len(refs)-1)
+		}
+		extras = append(extras, badge)
+	}
+	if task.Due != nil {
+		due := "📅 " + formatDue(*task.Due, time.Now())
+		if m.config.RelativeTimes {
+			due = "📅 " + formatDueIn(*task.Due, time.Now())
+		}
+		if isOverdue(task, time.Now()) {
+			due = lipgloss.NewStyle().Foreground(colorError).Render(due)
+		}
+		extras = append(extras, due)
+	}
+	if isEscalated(task) {
+		extras = append(extras, "⇡")
+	}
+	if n := len(openBlockers(m.tasks, task)); n > 0 && !task.Completed {
+		extras = append(extras, fmt.Sprintf("⛓%d", n))
+	}
+	if len(task.TimeLog) > 0 {
+		clock := "⏱ " + formatDuration(trackedTotal(task, time.Now()))
+		if runningEntry(&task) != nil {
+			clock = lipgloss.NewStyle().Foreground(colorSuccess).Render(clock)
+		}
+		extras = append(extras, clock)
+	}
+	if task.Assignee != "" {
+		extras = append(extras, "@"+initials(task.Assignee))
+	}
+	if m.config.RelativeTimes && !task.CreatedAt.IsZero() {
+		extras = append(extras, formatAgo(task.CreatedAt, time.Now()))
+	}
+	if m.script != nil {
+		if badge, err := m.script.decorate(task); err == nil && badge != "" {
+			extras = append(extras, badge)
+		}
+	}
+	return extras
+}
+
 func (m model) viewAdd() string {
 	priorityName := Priority(m.selectedCol).String()
-	priorityColor := Priority(m.selectedCol).Color()
+	var priorityColor lipgloss.TerminalColor = Priority(m.selectedCol).Color()
 
-	titleText := fmt.Sprintf("📝 ADD TASK TO %s", priorityName)
+	titleText := fmt.Sprintf(T("add.title"), priorityName)
+	if m.addingSomeday {
+		titleText = T("add.someday_title")
+		priorityColor = colorAccent
+	}
+	if m.addingHabit {
+		titleText = T("add.habit_title")
+		priorityColor = colorSuccess
+	}
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(priorityColor).
 		Render(titleText)
 
 	return fmt.Sprintf(
-		"%s\n\n%s\n\n%s",
+		"%s\n\n%s\n%s\n%s",
 		title,
 		m.textarea.View(),
-		helpStyle.Render("ctrl+s to save • esc to cancel"),
+		renderTagSuggestions(tagSuggestions(m.tasks, m.textarea.Value()), m.keys),
+		m.renderFooter(),
 	)
 }
 
 func (m model) viewEdit() string {
-	title := "✏️  EDIT TASK"
+	title := T("edit.title")
 	if m.editingTask != nil {
-		taskTitle := m.editingTask.Title
-		if len(taskTitle) > 40 {
-			taskTitle = taskTitle[:37] + "..."
-		}
-		title = fmt.Sprintf("✏️  %s", taskTitle)
+		taskTitle := truncate(m.editingTask.Title, 40)
+		title = fmt.Sprintf(T("edit.task_title"), taskTitle)
 	}
 
 	styledTitle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#FBBF24")).
+		Foreground(colorAccent).
 		Render(title)
 
 	return fmt.Sprintf(
-		"%s\n\n%s\n\n%s",
+		"%s\n\n%s\n%s\n%s",
 		styledTitle,
 		m.textarea.View(),
-		helpStyle.Render("ctrl+s to save • esc to cancel"),
+		renderTagSuggestions(tagSuggestions(m.tasks, m.textarea.Value()), m.keys),
+		m.renderFooter(),
 	)
 }
 
-func (m model) viewHelp() string {
-	help := `
-╔═══════════════════════════════════════╗
-║          🧺 BASKET HELP               ║
-╚═══════════════════════════════════════╝
-
-NAVIGATION
-  h/←  Move to left column
-  l/→  Move to right column  
-  k/↑  Move up in column
-  j/↓  Move down in column
+func (m model) viewGoto() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent).
+		Render(T("goto.title"))
+
+	errLine := ""
+	if m.inputErr != "" {
+		errLine = lipgloss.NewStyle().
+			Foreground(colorError).
+			Render(m.inputErr)
+	}
 
-TASK ACTIONS
-  space    Toggle completion
-  m        Move task to next priority
-  n        Add new task
-  e        Edit task description
-  d        Delete task
+	return fmt.Sprintf(
+		"%s\n\n%s\n%s\n\n%s",
+		title,
+		m.input.View(),
+		errLine,
+		m.renderFooter(),
+	)
+}
 
-VIEW
-  t        Switch global/local
-  ?        Show this help
-  q        Quit
+func main() {
+	args, debug := takeDebugFlag(os.Args[1:])
+	if debug {
+		closeLog, err := enableDebugLog()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, T("error")+"\n", err)
+			os.Exit(1)
+		}
+		defer closeLog()
+		fmt.Fprintf(os.Stderr, "debug log: %s\n", getDebugLogPath())
+	}
 
-STORAGE
-  Global   ~/basket-tasks.json
-  Local    ./.basket.json
+	migrateWindowsStorage()
+	cfg, err := loadConfig()
+	if err != nil {
+		// Carrying on would quietly drop every setting
+		logger.Error("load config", "path", getConfigPath(), "err", err)
+		fmt.Fprintf(os.Stderr, T("error")+"\n", fmt.Errorf("%s: %w", getConfigPath(), err))
+		os.Exit(1)
+	}
+	setLocale(detectLocale(cfg))
+	if cfg.BackupRetention != nil {
+		backupRetention = *cfg.BackupRetention
+	}
+	setConfigLevels(cfg.Priorities, cfg.Columns)
 
-Priority columns from left to right:
-  LOWEST → LOW → MEDIUM → HIGH → HIGHEST
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if err := runCommand(cfg, args[0], args[1:]); err != nil {
+			logger.Error("command", "name", args[0], "err", err)
+			fmt.Fprintf(os.Stderr, T("error")+"\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-Press ESC or q to return
-`
-	return help
+	opts, err := parseStartupFlags(cfg, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, T("error")+"\n", err)
+		os.Exit(2)
+	}
+	m := initialModel(cfg)
+	if err := m.applyStartup(opts); err != nil {
+		fmt.Fprintf(os.Stderr, T("error")+"\n", err)
+		os.Exit(1)
+	}
+	if err := runTUI(m); err != nil {
+		fmt.Printf(T("error"), err)
+		os.Exit(1)
+	}
 }
 
-func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
+// runTUI shows the board until the user quits
+func runTUI(m model) error {
+	applyTheme(m.config.Theme)
+	applyColors(m.config.Colors)
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v", err)
-		os.Exit(1)
+		logger.Error("run", "err", err)
+		return err
 	}
+	return nil
 }
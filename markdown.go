@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// markdownFormat stores a board as a directory with one Markdown file per
+// task, so boards merge cleanly in git and can be edited in any editor:
+//
+//	---
+//	id: 01J...
+//	priority: 2
+//	created_at: 2025-01-02T15:04:05Z
+//	---
+//	# Title
+//
+//	Description
+//
+// Archived tasks live in an archive/ subdirectory, and the board's own
+// columns and settings, if any, in columns.json and settings.json.
+type markdownFormat struct{}
+
+const frontmatterFence = "---"
+
+func (markdownFormat) read(path string) (TaskList, error) {
+	tasks, err := readTaskFiles(path)
+	if err != nil {
+		return TaskList{}, err
+	}
+	archive, err := readTaskFiles(filepath.Join(path, "archive"))
+	if err != nil && !os.IsNotExist(err) {
+		return TaskList{}, err
+	}
+	taskList := TaskList{Tasks: tasks, Archive: archive}
+	if data, err := os.ReadFile(filepath.Join(path, "columns.json")); err == nil {
+		if err := json.Unmarshal(data, &taskList.Columns); err != nil {
+			return TaskList{}, &corruptBoardError{Path: path, Err: fmt.Errorf("columns.json: %w", err)}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(path, "settings.json")); err == nil {
+		if err := json.Unmarshal(data, &taskList.Settings); err != nil {
+			return TaskList{}, &corruptBoardError{Path: path, Err: fmt.Errorf("settings.json: %w", err)}
+		}
+	}
+	return taskList, nil
+}
+
+func (markdownFormat) write(path string, taskList TaskList) error {
+	if err := writeTaskFiles(path, taskList.Tasks); err != nil {
+		return err
+	}
+	if err := writeBoardExtra(filepath.Join(path, "columns.json"), taskList.Columns, len(taskList.Columns) > 0); err != nil {
+		return err
+	}
+	if err := writeBoardExtra(filepath.Join(path, "settings.json"), taskList.Settings, taskList.Settings != nil); err != nil {
+		return err
+	}
+	if len(taskList.Archive) == 0 {
+		// Keep the board directory tidy until something is archived
+		if err := os.RemoveAll(filepath.Join(path, "archive")); err != nil {
+			return err
+		}
+		return nil
+	}
+	return writeTaskFiles(filepath.Join(path, "archive"), taskList.Archive)
+}
+
+// writeBoardExtra writes v to file as JSON, or removes the file when the
+// board has nothing to keep there
+func writeBoardExtra(file string, v any, keep bool) error {
+	if !keep {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
+
+// taskFileName is the file of a task. Naming by ID keeps the name stable
+// when the title changes.
+func taskFileName(task Task) string {
+	return task.ID + ".md"
+}
+
+// readTaskFiles parses every task file in dir, in file name order, which
+// for ULIDs is creation order
+func readTaskFiles(dir string) ([]Task, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	tasks := []Task{}
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		task, err := parseTaskMarkdown(data)
+		if err != nil {
+			return nil, &corruptBoardError{Path: dir, Err: fmt.Errorf("%s: %w", name, err)}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// writeTaskFiles makes dir hold exactly tasks. Unchanged files are left
+// alone, so saving only touches the tasks that were edited.
+func writeTaskFiles(dir string, tasks []Task) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		name := taskFileName(task)
+		keep[name] = true
+		data, err := formatTaskMarkdown(task)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(dir, name)
+		if old, err := os.ReadFile(file); err == nil && bytes.Equal(old, data) {
+			continue
+		}
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") && !keep[e.Name()] {
+			if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatTaskMarkdown renders a task file. Every field except the title and
+// description goes into the frontmatter, in the order Task declares them.
+func formatTaskMarkdown(task Task) ([]byte, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML, and decoding into a node keeps the field order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	fields := doc.Content[0]
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(fields.Content); i += 2 {
+		switch fields.Content[i].Value {
+		case "title", "description":
+			continue
+		}
+		kept = append(kept, fields.Content[i], fields.Content[i+1])
+	}
+	fields.Content = kept
+	plainStyle(fields)
+
+	front, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	b.WriteString(frontmatterFence + "\n")
+	b.Write(front)
+	b.WriteString(frontmatterFence + "\n")
+	b.WriteString("# " + task.Title + "\n")
+	if task.Description != "" {
+		b.WriteString("\n" + task.Description + "\n")
+	}
+	return b.Bytes(), nil
+}
+
+// plainStyle drops the JSON flow style and quoting from a decoded node,
+// so the frontmatter reads like hand-written YAML
+func plainStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		plainStyle(c)
+	}
+}
+
+// parseTaskMarkdown reads a task file. The first "# " heading is the
+// title and the text after it the description.
+func parseTaskMarkdown(data []byte) (Task, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, frontmatterFence+"\n") {
+		return Task{}, fmt.Errorf("missing frontmatter")
+	}
+	rest := text[len(frontmatterFence)+1:]
+	end := strings.Index(rest, "\n"+frontmatterFence+"\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n"+frontmatterFence) {
+			return Task{}, fmt.Errorf("unterminated frontmatter")
+		}
+		end = len(rest) - len(frontmatterFence) - 1
+	}
+	front := rest[:end]
+	body := ""
+	if end+len(frontmatterFence)+2 <= len(rest) {
+		body = rest[end+len(frontmatterFence)+2:]
+	}
+
+	fields := map[string]any{}
+	if err := yaml.Unmarshal([]byte(front), &fields); err != nil {
+		return Task{}, err
+	}
+
+	body = strings.TrimLeft(body, "\n")
+	if line, after, ok := strings.Cut(body, "\n"); strings.HasPrefix(line, "# ") {
+		fields["title"] = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		if ok {
+			body = after
+		} else {
+			body = ""
+		}
+	}
+	fields["description"] = strings.TrimSpace(body)
+
+	// Round-trip through JSON so Task's own field names and types apply
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return Task{}, err
+	}
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
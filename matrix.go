@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// isImportant reads importance off the priority: the levels above the
+// middle one are important
+func isImportant(task Task) bool {
+	return task.Priority > maxPriority()/2
+}
+
+// quadrant is one cell of the Eisenhower matrix
+type quadrant struct {
+	Title string
+	Hint  string
+	Color lipgloss.TerminalColor
+	Tasks []Task
+}
+
+// eisenhower sorts the open tasks on the board into the four quadrants:
+// do, schedule, delegate and drop
+func (m model) eisenhower() [4]quadrant {
+	q := [4]quadrant{
+		{Title: T("matrix.do"), Hint: T("matrix.do_hint"), Color: colorError},
+		{Title: T("matrix.schedule"), Hint: T("matrix.schedule_hint"), Color: colorInfo},
+		{Title: T("matrix.delegate"), Hint: T("matrix.delegate_hint"), Color: colorWarning},
+		{Title: T("matrix.drop"), Hint: T("matrix.drop_hint"), Color: colorDone},
+	}
+	// Most important first within each quadrant
+	for p := maxPriority(); p >= 0; p-- {
+		for _, task := range m.getTasksInColumn(p) {
+			if task.Completed {
+				continue
+			}
+			i := 0
+			switch {
+			case task.Urgent && isImportant(task):
+			case isImportant(task):
+				i = 1
+			case task.Urgent:
+				i = 2
+			default:
+				i = 3
+			}
+			q[i].Tasks = append(q[i].Tasks, task)
+		}
+	}
+	return q
+}
+
+// toggleUrgent flags or unflags the selected task as urgent
+func (m *model) toggleUrgent() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
+			m.tasks[i].Urgent = !m.tasks[i].Urgent
+			if m.tasks[i].Urgent {
+				m.commit(T("status.urgent"))
+			} else {
+				m.commit(T("status.not_urgent"))
+			}
+			return
+		}
+	}
+}
+
+func (m model) updateMatrix(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Matrix):
+		m.mode = ViewBoard
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m model) viewMatrix() string {
+	q := m.eisenhower()
+
+	width := 38
+	if m.width > 0 {
+		width = max(m.width/2-4, 24)
+	}
+	height := 10
+	if m.height > 0 {
+		height = max((m.height-8)/2-4, 4)
+	}
+
+	cell := func(q quadrant) string {
+		var b strings.Builder
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(q.Color).Render(fmt.Sprintf("%s (%d)", q.Title, len(q.Tasks))) + "\n")
+		b.WriteString(helpStyle.Render(q.Hint) + "\n\n")
+		for i, task := range q.Tasks {
+			if i == height {
+				b.WriteString(helpStyle.Render(fmt.Sprintf(T("matrix.more"), len(q.Tasks)-height)) + "\n")
+				break
+			}
+			name := lipgloss.NewStyle().Foreground(task.Priority.Color()).Render("●")
+			b.WriteString(fmt.Sprintf("%s %s\n", name, truncate(task.Title, width-6)))
+		}
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(q.Color).
+			Padding(0, 1).
+			Width(width).
+			Height(height + 3).
+			Render(b.String())
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(T("matrix.title")) + "\n")
+	header := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(width+4).Align(lipgloss.Center).Render(T("matrix.urgent")),
+		lipgloss.NewStyle().Width(width+4).Align(lipgloss.Center).Render(T("matrix.not_urgent")),
+	)
+	b.WriteString(helpStyle.Render(header) + "\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cell(q[0]), cell(q[1])) + "\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cell(q[2]), cell(q[3])) + "\n")
+	b.WriteString(m.renderFooter())
+	return b.String()
+}
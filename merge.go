@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+// mergeChange is a task that a merge added to or updated on a board
+type mergeChange struct {
+	Task    Task
+	Updated bool // the task existed and the incoming copy was newer
+}
+
+// mergeBoards unions the tasks of other into base by ID. When both boards
+// hold a task, the copy modified last wins; ties keep base. Tasks stay
+// archived or active as the winning copy has them.
+func mergeBoards(base, other TaskList) (TaskList, []mergeChange) {
+	type located struct {
+		task     Task
+		archived bool
+	}
+	incoming := map[string]located{}
+	var order []string
+	for _, list := range []struct {
+		tasks    []Task
+		archived bool
+	}{{other.Tasks, false}, {other.Archive, true}} {
+		for _, task := range list.tasks {
+			if _, dup := incoming[task.ID]; !dup {
+				order = append(order, task.ID)
+			}
+			incoming[task.ID] = located{task, list.archived}
+		}
+	}
+
+	var changes []mergeChange
+	merged := TaskList{Tasks: []Task{}, Columns: base.Columns, Settings: base.Settings}
+	seen := map[string]bool{}
+	place := func(task Task, archived bool) {
+		if archived {
+			merged.Archive = append(merged.Archive, task)
+		} else {
+			merged.Tasks = append(merged.Tasks, task)
+		}
+	}
+	keep := func(task Task, archived bool) {
+		seen[task.ID] = true
+		in, ok := incoming[task.ID]
+		if !ok || !lastModified(in.task).After(lastModified(task)) || sameTask(in.task, task) {
+			place(task, archived)
+			return
+		}
+		place(in.task, in.archived)
+		changes = append(changes, mergeChange{Task: in.task, Updated: true})
+	}
+	for _, task := range base.Tasks {
+		keep(task, false)
+	}
+	for _, task := range base.Archive {
+		keep(task, true)
+	}
+
+	for _, id := range order {
+		if seen[id] {
+			continue
+		}
+		in := incoming[id]
+		place(in.task, in.archived)
+		changes = append(changes, mergeChange{Task: in.task})
+	}
+	return merged, changes
+}
+
+func sameTask(a, b Task) bool {
+	da, _ := json.Marshal(a)
+	db, _ := json.Marshal(b)
+	return bytes.Equal(da, db)
+}
+
+func cmdImport(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	merge := fs.Bool("merge", false, "union the file's tasks into the board, newest copy winning")
+	force := fs.Bool("force", false, "replace a board that already has tasks with the bundle")
+	dryRun := fs.Bool("dry-run", false, "show what would change without touching the board")
+	project := fs.String("project", "", "project for tasks imported from a calendar")
+	past := fs.Bool("past", false, "also import calendar events that are over")
+	reminders := fs.Bool("reminders", false, "import open reminders from the Reminders app (macOS)")
+	list := fs.String("list", "", "only import reminders from this list")
+	columns := fs.Bool("columns", false, "map the columns of any CSV file, as for ClickUp and Asana exports")
+	yes := fs.Bool("yes", false, "take the guessed CSV columns without asking")
+	files := parseInterspersed(fs, args)
+
+	if *reminders && len(files) == 0 {
+		return importReminders(cfg, resolveBoardPath(*global, *local), "", *list, *project, *dryRun)
+	}
+	if len(files) != 1 {
+		return fmt.Errorf("usage: basket import <file> [--merge]")
+	}
+	if isICSFile(files[0]) {
+		return importICS(cfg, resolveBoardPath(*global, *local), files[0], *project, *past, *dryRun)
+	}
+	if isCSVFile(files[0]) && (*columns || isTrackerCSV(files[0])) {
+		return importTrackerCSV(cfg, resolveBoardPath(*global, *local), files[0], *project, *yes, *dryRun)
+	}
+	if isCSVFile(files[0]) {
+		return importReminders(cfg, resolveBoardPath(*global, *local), files[0], "", *project, *dryRun)
+	}
+
+	bundle, isBundle, err := readBundle(files[0])
+	if err != nil {
+		return err
+	}
+	other := bundle.Board
+	if !isBundle {
+		if !*merge {
+			return fmt.Errorf("importing a board file needs --merge")
+		}
+		if other, err = formatFor(files[0]).read(files[0]); err != nil {
+			return err
+		}
+	}
+
+	path := resolveBoardPath(*global, *local)
+	board, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+
+	var merged TaskList
+	var changes []mergeChange
+	if *merge {
+		merged, changes = mergeBoards(board, other)
+	} else {
+		// Recreate the bundled board as it was
+		if len(board.Tasks)+len(board.Archive) > 0 && !*force {
+			return fmt.Errorf("%s already has tasks; use --merge to combine them or --force to replace them", path)
+		}
+		merged = other
+		for _, task := range append(append([]Task{}, other.Tasks...), other.Archive...) {
+			changes = append(changes, mergeChange{Task: task})
+		}
+	}
+
+	added, updated := 0, 0
+	for _, c := range changes {
+		verb := "added  "
+		if c.Updated {
+			verb = "updated"
+			updated++
+		} else {
+			added++
+		}
+		fmt.Printf("%s  %-6s  %s\n", verb, shortID(c.Task.ID), c.Task.Title)
+	}
+
+	if *dryRun {
+		fmt.Printf("would add %d and update %d task(s) in %s\n", added, updated, path)
+		return nil
+	}
+	if isBundle {
+		for _, note := range installBundleSettings(bundle) {
+			fmt.Println(note)
+		}
+	}
+	if len(changes) == 0 && *merge {
+		fmt.Printf("%s is already up to date\n", path)
+		return nil
+	}
+	if err := saveBoard(path, merged); err != nil {
+		return err
+	}
+	afterSave(cfg, path, board.Tasks, merged.Tasks)
+	fmt.Printf("added %d and updated %d task(s) in %s\n", added, updated, path)
+	return nil
+}
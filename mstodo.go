@@ -0,0 +1,539 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// msTodoBackend syncs the board with a Microsoft To Do list through the
+// Graph API, for accounts that live in Microsoft 365. Sign-in uses the
+// device code flow (basket sync login), and the tokens are kept in the
+// data directory.
+//
+// To Do tasks have IDs of their own and no room for comments, assignees
+// and the like, so basket remembers the task each one belongs to, as
+// last synced, and lays To Do's fields over it on pull.
+type msTodoBackend struct {
+	clientID string
+	tenant   string
+	list     string // display name of the list, empty for the default list
+	client   *http.Client
+
+	token  *msToken
+	listID string
+
+	// pulled is the remote state seen by the last Pull, by basket task
+	// ID, so Push only writes what changed
+	pulled map[string]msTodoItem
+	state  msTodoState
+}
+
+type msTodoItem struct {
+	id   string // the To Do task ID
+	task Task
+}
+
+// msTodoState is what basket remembers of the list between runs
+type msTodoState struct {
+	Tasks map[string]Task `json:"tasks"` // as last synced, by To Do task ID
+}
+
+type msToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expires      time.Time `json:"expires"`
+}
+
+const (
+	graphURL    = "https://graph.microsoft.com/v1.0"
+	msLoginURL  = "https://login.microsoftonline.com"
+	msTodoScope = "Tasks.ReadWrite offline_access"
+)
+
+func getMSTokenPath() string {
+	return filepath.Join(getDataDir(), "mstodo-token.json")
+}
+
+func getMSTodoStatePath() string {
+	return filepath.Join(getDataDir(), "mstodo-tasks.json")
+}
+
+func newMSTodoBackend(cfg *SyncConfig) *msTodoBackend {
+	tenant := cfg.Tenant
+	if tenant == "" {
+		tenant = "common"
+	}
+	return &msTodoBackend{
+		clientID: cfg.ClientID,
+		tenant:   tenant,
+		list:     cfg.List,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// msTodoTask is a task as the Graph API has it
+type msTodoTask struct {
+	ID                   string      `json:"id,omitempty"`
+	Title                string      `json:"title"`
+	Body                 *msTodoBody `json:"body,omitempty"`
+	Importance           string      `json:"importance,omitempty"` // low, normal or high
+	Status               string      `json:"status,omitempty"`     // notStarted, completed, ...
+	Categories           []string    `json:"categories"`
+	DueDateTime          *msDateTime `json:"dueDateTime"`
+	CompletedDateTime    *msDateTime `json:"completedDateTime,omitempty"`
+	CreatedDateTime      string      `json:"createdDateTime,omitempty"`
+	LastModifiedDateTime string      `json:"lastModifiedDateTime,omitempty"`
+}
+
+type msTodoBody struct {
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+}
+
+type msDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// time reads a Graph date and time, given without an offset in the
+// named zone
+func (d *msDateTime) time() (time.Time, bool) {
+	if d == nil || d.DateTime == "" {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05.9999999", d.DateTime, loc)
+	return t, err == nil
+}
+
+// msImportance maps a level onto To Do's three
+func msImportance(p Priority) string {
+	switch {
+	case p > defaultPriority():
+		return "high"
+	case p < defaultPriority():
+		return "low"
+	}
+	return "normal"
+}
+
+func fromMSImportance(s string) Priority {
+	switch s {
+	case "high":
+		return clampPriority(defaultPriority() + 1)
+	case "low":
+		return clampPriority(defaultPriority() - 1)
+	}
+	return defaultPriority()
+}
+
+// msTodoFromTask is task as To Do stores it. A due time is dropped, as
+// To Do only keeps the day.
+func msTodoFromTask(task Task) msTodoTask {
+	t := msTodoTask{
+		Title:      task.Title,
+		Body:       &msTodoBody{Content: task.Description, ContentType: "text"},
+		Importance: msImportance(clampPriority(task.Priority)),
+		Status:     "notStarted",
+		Categories: []string{},
+	}
+	if task.Completed {
+		t.Status = "completed"
+	}
+	if task.Project != "" {
+		t.Categories = []string{task.Project}
+	}
+	if task.Due != nil {
+		t.DueDateTime = &msDateTime{DateTime: task.Due.Local().Format("2006-01-02") + "T00:00:00", TimeZone: "UTC"}
+	}
+	return t
+}
+
+// taskFromMSTodo lays the fields of a To Do task over base, the task as
+// basket last synced it; ok is false for a task created in To Do
+func taskFromMSTodo(t msTodoTask, base Task, ok bool) Task {
+	task := base
+	if !ok {
+		task = Task{ID: generateID(), Priority: defaultPriority(), CreatedAt: time.Now()}
+		if created, err := time.Parse(time.RFC3339, t.CreatedDateTime); err == nil {
+			task.CreatedAt = created
+		}
+	}
+	task.Title = t.Title
+	if t.Body != nil && t.Body.ContentType == "text" {
+		task.Description = t.Body.Content
+	}
+	if msImportance(clampPriority(task.Priority)) != t.Importance {
+		task.Priority = fromMSImportance(t.Importance)
+	}
+	task.Project = ""
+	if len(t.Categories) > 0 {
+		task.Project = t.Categories[0]
+	}
+
+	task.Due = nil
+	if due, ok := t.DueDateTime.time(); ok {
+		day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.Local)
+		task.Due = &day
+	}
+	if modified, err := time.Parse(time.RFC3339, t.LastModifiedDateTime); err == nil && modified.After(lastModified(task)) {
+		task.UpdatedAt = &modified
+	}
+
+	done := t.Status == "completed"
+	if done != task.Completed {
+		task.Completed = done
+		task.CompletedAt = nil
+	}
+	if at, ok := t.CompletedDateTime.time(); ok && done && task.CompletedAt == nil {
+		task.CompletedAt = &at
+	}
+	if done && task.CompletedAt == nil {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	return task
+}
+
+// msAuthError is an error from the sign-in endpoints
+type msAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *msAuthError) Error() string {
+	return fmt.Sprintf("microsoft sign-in: %s", cmp.Or(e.Description, e.Code))
+}
+
+// tokenRequest posts form to the token endpoint
+func (b *msTodoBackend) tokenRequest(form url.Values) (*msToken, error) {
+	form.Set("client_id", b.clientID)
+	resp, err := b.client.PostForm(fmt.Sprintf("%s/%s/oauth2/v2.0/token", msLoginURL, url.PathEscape(b.tenant)), form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var r struct {
+		msAuthError
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("microsoft sign-in: %s", resp.Status)
+	}
+	if r.Code != "" {
+		return nil, &r.msAuthError
+	}
+	return &msToken{
+		AccessToken:  r.AccessToken,
+		RefreshToken: r.RefreshToken,
+		Expires:      time.Now().Add(time.Duration(r.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func saveMSToken(token *msToken) error {
+	if err := os.MkdirAll(getDataDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getMSTokenPath(), data, 0600)
+}
+
+// accessToken returns a current access token, refreshing it when it is
+// about to expire
+func (b *msTodoBackend) accessToken() (string, error) {
+	if b.token == nil {
+		data, err := os.ReadFile(getMSTokenPath())
+		if err != nil {
+			return "", fmt.Errorf("not signed in to Microsoft To Do, run basket sync login")
+		}
+		b.token = &msToken{}
+		if err := json.Unmarshal(data, b.token); err != nil {
+			return "", err
+		}
+	}
+	if time.Until(b.token.Expires) > time.Minute {
+		return b.token.AccessToken, nil
+	}
+	token, err := b.tokenRequest(url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {b.token.RefreshToken},
+		"scope":         {msTodoScope},
+	})
+	if err != nil {
+		return "", err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = b.token.RefreshToken
+	}
+	b.token = token
+	if err := saveMSToken(token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// graph calls the Graph API, sending in as JSON and decoding the reply
+// into out. target is a path under graphURL or a full URL.
+func (b *msTodoBackend) graph(method, target string, in, out any) error {
+	token, err := b.accessToken()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(target, "https://") {
+		target = graphURL + target
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("%s %s: %s %s", method, target, resp.Status, e.Error.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// resolveList finds the list the board syncs with, creating it if a
+// named one does not exist yet
+func (b *msTodoBackend) resolveList() error {
+	if b.listID != "" {
+		return nil
+	}
+	var lists struct {
+		Value []struct {
+			ID        string `json:"id"`
+			Name      string `json:"displayName"`
+			WellKnown string `json:"wellknownListName"`
+		} `json:"value"`
+	}
+	if err := b.graph(http.MethodGet, "/me/todo/lists", nil, &lists); err != nil {
+		return err
+	}
+	for _, l := range lists.Value {
+		if (b.list == "" && l.WellKnown == "defaultList") || (b.list != "" && l.Name == b.list) {
+			b.listID = l.ID
+			return nil
+		}
+	}
+	if b.list == "" {
+		return fmt.Errorf("microsoft to do: no default list")
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := b.graph(http.MethodPost, "/me/todo/lists", map[string]string{"displayName": b.list}, &created); err != nil {
+		return err
+	}
+	b.listID = created.ID
+	return nil
+}
+
+func (b *msTodoBackend) tasksURL() string {
+	return "/me/todo/lists/" + url.PathEscape(b.listID) + "/tasks"
+}
+
+func (b *msTodoBackend) loadState() {
+	b.state = msTodoState{Tasks: map[string]Task{}}
+	if data, err := os.ReadFile(getMSTodoStatePath()); err == nil {
+		json.Unmarshal(data, &b.state)
+	}
+	if b.state.Tasks == nil {
+		b.state.Tasks = map[string]Task{}
+	}
+}
+
+func (b *msTodoBackend) saveState() error {
+	if err := os.MkdirAll(getDataDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getMSTodoStatePath(), data, 0644)
+}
+
+func (b *msTodoBackend) Pull() (TaskList, error) {
+	if err := b.resolveList(); err != nil {
+		return TaskList{}, err
+	}
+	var remote []msTodoTask
+	for next := b.tasksURL(); next != ""; {
+		var page struct {
+			Value []msTodoTask `json:"value"`
+			Next  string       `json:"@odata.nextLink"`
+		}
+		if err := b.graph(http.MethodGet, next, nil, &page); err != nil {
+			return TaskList{}, err
+		}
+		remote = append(remote, page.Value...)
+		next = page.Next
+	}
+
+	b.loadState()
+	seen := map[string]bool{}
+	b.pulled = map[string]msTodoItem{}
+	taskList := TaskList{Tasks: []Task{}}
+	for _, t := range remote {
+		base, ok := b.state.Tasks[t.ID]
+		task := taskFromMSTodo(t, base, ok)
+		seen[t.ID] = true
+		b.state.Tasks[t.ID] = task
+		b.pulled[task.ID] = msTodoItem{id: t.ID, task: task}
+		taskList.Tasks = append(taskList.Tasks, task)
+	}
+	for id := range b.state.Tasks {
+		if !seen[id] {
+			delete(b.state.Tasks, id)
+		}
+	}
+	return taskList, b.saveState()
+}
+
+// Push writes the tasks that differ from the last pull and deletes the
+// ones that are gone
+func (b *msTodoBackend) Push(taskList TaskList) error {
+	keep := map[string]bool{}
+	defer b.saveState()
+	for _, task := range taskList.Tasks {
+		keep[task.ID] = true
+		old, ok := b.pulled[task.ID]
+		if ok && sameTask(old.task, task) {
+			continue
+		}
+		if ok {
+			if err := b.graph(http.MethodPatch, b.tasksURL()+"/"+url.PathEscape(old.id), msTodoFromTask(task), nil); err != nil {
+				return err
+			}
+			b.state.Tasks[old.id] = task
+			continue
+		}
+		var created msTodoTask
+		if err := b.graph(http.MethodPost, b.tasksURL(), msTodoFromTask(task), &created); err != nil {
+			return err
+		}
+		b.state.Tasks[created.ID] = task
+	}
+	for id, old := range b.pulled {
+		if keep[id] {
+			continue
+		}
+		if err := b.graph(http.MethodDelete, b.tasksURL()+"/"+url.PathEscape(old.id), nil, nil); err != nil {
+			return err
+		}
+		delete(b.state.Tasks, old.id)
+	}
+	return nil
+}
+
+// msTodoLogin signs in with the device code flow: the user opens a page
+// on any device and enters the code shown, while basket waits
+func msTodoLogin(cfg *SyncConfig) error {
+	if cfg.ClientID == "" {
+		return fmt.Errorf("set sync.client_id to the ID of an app registered in Microsoft Entra")
+	}
+	b := newMSTodoBackend(cfg)
+	resp, err := b.client.PostForm(fmt.Sprintf("%s/%s/oauth2/v2.0/devicecode", msLoginURL, url.PathEscape(b.tenant)), url.Values{
+		"client_id": {b.clientID},
+		"scope":     {msTodoScope},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var code struct {
+		msAuthError
+		DeviceCode string `json:"device_code"`
+		ExpiresIn  int    `json:"expires_in"`
+		Interval   int    `json:"interval"`
+		Message    string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&code); err != nil {
+		return fmt.Errorf("microsoft sign-in: %s", resp.Status)
+	}
+	if code.Code != "" {
+		return &code.msAuthError
+	}
+	fmt.Println(code.Message)
+
+	interval := time.Duration(max(code.Interval, 1)) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+		token, err := b.tokenRequest(url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {code.DeviceCode},
+		})
+		if e, ok := err.(*msAuthError); ok {
+			switch e.Code {
+			case "authorization_pending":
+				continue
+			case "slow_down":
+				interval += 5 * time.Second
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if err := saveMSToken(token); err != nil {
+			return err
+		}
+		fmt.Println("signed in to Microsoft To Do")
+		return nil
+	}
+	return fmt.Errorf("the code expired, run basket sync login again")
+}
+
+// cmdSync manages the sync remote; for now it signs in to Microsoft To Do
+func cmdSync(cfg Config, args []string) error {
+	if len(args) == 0 || args[0] != "login" {
+		return fmt.Errorf("usage: basket sync login")
+	}
+	if cfg.Sync == nil || cfg.Sync.Type != "mstodo" {
+		return fmt.Errorf(`basket sync login is for "type": "mstodo" in the sync config`)
+	}
+	return msTodoLogin(cfg.Sync)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Mutation operations
+const (
+	MutationPut    = "put"    // create or replace the task
+	MutationDelete = "delete" // remove the task
+)
+
+// mutation is a single change to a board's tasks. Changes are recorded as
+// whole-task replacements so they can be replayed onto any copy of the
+// board, regardless of what else changed there.
+type mutation struct {
+	Op   string    `json:"op"`
+	ID   string    `json:"id"`
+	Task *Task     `json:"task,omitempty"`
+	At   time.Time `json:"at"`
+}
+
+// diffTasks returns the mutations that turn old into new
+func diffTasks(old, new []Task) []mutation {
+	now := time.Now()
+	before := make(map[string][]byte, len(old))
+	for _, task := range old {
+		data, _ := json.Marshal(task)
+		before[task.ID] = data
+	}
+
+	var muts []mutation
+	seen := make(map[string]bool, len(new))
+	for _, task := range new {
+		seen[task.ID] = true
+		data, _ := json.Marshal(task)
+		if prev, ok := before[task.ID]; ok && bytes.Equal(prev, data) {
+			continue
+		}
+		t := task
+		muts = append(muts, mutation{Op: MutationPut, ID: task.ID, Task: &t, At: now})
+	}
+	for _, task := range old {
+		if !seen[task.ID] {
+			muts = append(muts, mutation{Op: MutationDelete, ID: task.ID, At: now})
+		}
+	}
+	return muts
+}
+
+// touchTasks stamps the tasks in new that were added or changed since old
+// with now as their modification time
+func touchTasks(old, new []Task, now time.Time) {
+	before := make(map[string][]byte, len(old))
+	for _, task := range old {
+		data, _ := json.Marshal(task)
+		before[task.ID] = data
+	}
+	for i := range new {
+		data, _ := json.Marshal(new[i])
+		if prev, ok := before[new[i].ID]; ok && bytes.Equal(prev, data) {
+			continue
+		}
+		stamp := now
+		new[i].UpdatedAt = &stamp
+	}
+}
+
+// lastModified is when task last changed, as far as it records
+func lastModified(task Task) time.Time {
+	t := task.CreatedAt
+	for _, stamp := range []*time.Time{task.CompletedAt, task.ArchivedAt, task.UpdatedAt} {
+		if stamp != nil && stamp.After(t) {
+			t = *stamp
+		}
+	}
+	return t
+}
+
+// applyMutations replays muts onto tasks in order and returns the result
+func applyMutations(tasks []Task, muts []mutation) []Task {
+	out := make([]Task, len(tasks))
+	copy(out, tasks)
+
+	for _, mut := range muts {
+		idx := -1
+		for i := range out {
+			if out[i].ID == mut.ID {
+				idx = i
+				break
+			}
+		}
+
+		switch mut.Op {
+		case MutationPut:
+			if mut.Task == nil {
+				continue
+			}
+			if idx >= 0 {
+				out[idx] = *mut.Task
+			} else {
+				out = append(out, *mut.Task)
+			}
+		case MutationDelete:
+			if idx >= 0 {
+				out = append(out[:idx], out[idx+1:]...)
+			}
+		}
+	}
+	return out
+}
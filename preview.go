@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewWidth is the preview pane's width, border included
+const previewWidth = 50
+
+// previewEnabled reports whether wide terminals get the preview pane; on
+// unless the config turns it off
+func (c Config) previewEnabled() bool {
+	return c.Preview == nil || *c.Preview
+}
+
+// previewShown reports whether the terminal is wide enough for every
+// column and the preview pane side by side
+func (m model) previewShown() bool {
+	return m.config.previewEnabled() && m.width-previewWidth >= len(m.shownColumns())*preferredColumnWidth+2
+}
+
+// cardDescriptionLines is how much of its description a selected card
+// shows
+const cardDescriptionLines = 3
+
+// cardDescription is the start of task's description, wrapped to width,
+// for its card while selected; empty when the preview pane shows it
+// already, or it is sealed
+func (m model) cardDescription(task Task, width int) string {
+	if (m.config.CardDescription != nil && !*m.config.CardDescription) || m.previewShown() {
+		return ""
+	}
+	text := strings.TrimSpace(task.Description)
+	if text == "" || isSealed(text) {
+		return ""
+	}
+	// Paragraph breaks would waste the few lines there are
+	text = strings.Join(strings.Fields(text), " ")
+	return helpStyle.Render(strings.Join(wrapLines(text, width, cardDescriptionLines), "\n"))
+}
+
+// boardWidth is the width left for the columns
+func (m model) boardWidth() int {
+	if m.previewShown() {
+		return m.width - previewWidth
+	}
+	return m.width
+}
+
+// renderPreview is the pane beside the board showing the selected task,
+// as tall as a column
+func (m model) renderPreview() string {
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorFaint).
+		Padding(1, 2).
+		Width(previewWidth - 2).
+		Height(20)
+	inner := previewWidth - 6
+	lines := 20 - 2
+
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return style.Render(helpStyle.Render(T("preview.empty")))
+	}
+	task := tasksInCol[m.selectedTask]
+
+	body := strings.Split(strings.TrimRight(m.detailBody(task, inner), "\n"), "\n")
+	if len(body) > lines {
+		body = append(body[:lines-1], helpStyle.Render("…"))
+	}
+	return style.BorderForeground(clampPriority(task.Priority).Color()).Render(strings.Join(body, "\n"))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Priority levels
+type Priority int
+
+// The built-in levels, used when the config does not define its own
+const (
+	PriorityLowest Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityHighest
+)
+
+// PriorityLevel is one user-defined priority, and with it one board column
+type PriorityLevel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// priorityLevels are the configured levels, lowest first. When empty the
+// five built-in levels are used.
+var priorityLevels []PriorityLevel
+
+// priorityOverrides rename or recolor single levels, on top of either
+// the built-in or the configured levels
+var priorityOverrides = map[Priority]PriorityLevel{}
+
+// configLevels and configOverrides are the config's levels and overrides,
+// which stay in effect for boards without columns of their own
+var (
+	configLevels    []PriorityLevel
+	configOverrides map[string]PriorityLevel
+)
+
+// setConfigLevels applies the config's levels and overrides
+func setConfigLevels(levels []PriorityLevel, overrides map[string]PriorityLevel) {
+	configLevels, configOverrides = levels, overrides
+	useColumns(nil)
+}
+
+// useColumns switches to a board's own columns, or back to the config's
+// levels when it has none
+func useColumns(columns []PriorityLevel) {
+	priorityLevels = configLevels
+	if len(columns) > 0 {
+		priorityLevels = columns
+	}
+	setPriorityOverrides(configOverrides)
+}
+
+// builtinKeys name the built-in levels in the config
+var builtinKeys = []string{"lowest", "low", "medium", "high", "highest"}
+
+// builtinColors are the colors of the built-in levels, and the palette
+// custom levels without a color cycle through. Each names distinct 256-
+// and 16-color stand-ins, so the columns stay apart on smaller palettes.
+var builtinColors = []lipgloss.CompleteColor{
+	{TrueColor: "#6B7280", ANSI256: "244", ANSI: "8"},
+	{TrueColor: "#3B82F6", ANSI256: "33", ANSI: "12"},
+	{TrueColor: "#8B5CF6", ANSI256: "99", ANSI: "13"},
+	{TrueColor: "#F59E0B", ANSI256: "214", ANSI: "11"},
+	{TrueColor: "#EF4444", ANSI256: "196", ANSI: "9"},
+}
+
+// numPriorities is how many levels, and so columns, the board has
+func numPriorities() int {
+	if len(priorityLevels) > 0 {
+		return len(priorityLevels)
+	}
+	return int(PriorityHighest) + 1
+}
+
+// maxPriority is the highest level
+func maxPriority() Priority {
+	return Priority(numPriorities() - 1)
+}
+
+// defaultPriority is the middle level, where the board opens
+func defaultPriority() Priority {
+	return maxPriority() / 2
+}
+
+// allPriorities lists every level, lowest first
+func allPriorities() []Priority {
+	ps := make([]Priority, numPriorities())
+	for i := range ps {
+		ps[i] = Priority(i)
+	}
+	return ps
+}
+
+// clampPriority moves p into the configured range, so boards written with
+// more levels than are configured still show every task. Only where a
+// task is shown or placed is clamped; the stored level is kept, so fewer
+// levels for a while lose nothing.
+func clampPriority(p Priority) Priority {
+	if p < 0 {
+		return 0
+	}
+	if p > maxPriority() {
+		return maxPriority()
+	}
+	return p
+}
+
+// setPriorityOverrides resolves the config's per-level overrides. A level
+// is addressed by its built-in key ("lowest" ... "highest") or by its
+// name, case-insensitively.
+func setPriorityOverrides(overrides map[string]PriorityLevel) {
+	priorityOverrides = map[Priority]PriorityLevel{}
+	for key, o := range overrides {
+		for _, p := range allPriorities() {
+			builtin := len(priorityLevels) == 0 && int(p) < len(builtinKeys) && strings.EqualFold(key, builtinKeys[p])
+			if builtin || strings.EqualFold(key, p.String()) {
+				priorityOverrides[p] = o
+				break
+			}
+		}
+	}
+}
+
+func (p Priority) String() string {
+	p = clampPriority(p)
+	if o := priorityOverrides[p]; o.Name != "" {
+		return o.Name
+	}
+	if len(priorityLevels) > 0 {
+		if p >= 0 && int(p) < len(priorityLevels) && priorityLevels[p].Name != "" {
+			return priorityLevels[p].Name
+		}
+		return fmt.Sprintf("P%d", p)
+	}
+
+	switch p {
+	case PriorityLowest:
+		return T("priority.lowest")
+	case PriorityLow:
+		return T("priority.low")
+	case PriorityMedium:
+		return T("priority.medium")
+	case PriorityHigh:
+		return T("priority.high")
+	case PriorityHighest:
+		return T("priority.highest")
+	default:
+		return T("priority.medium")
+	}
+}
+
+func (p Priority) Color() lipgloss.TerminalColor {
+	p = clampPriority(p)
+	if o := priorityOverrides[p]; o.Color != "" {
+		return lipgloss.Color(o.Color)
+	}
+	if len(priorityLevels) > 0 && p >= 0 && int(p) < len(priorityLevels) && priorityLevels[p].Color != "" {
+		return lipgloss.Color(priorityLevels[p].Color)
+	}
+	if p < 0 || int(p) >= len(builtinColors) {
+		if len(priorityLevels) > 0 && p >= 0 {
+			return builtinColors[int(p)%len(builtinColors)]
+		}
+		return builtinColors[PriorityMedium]
+	}
+	return builtinColors[p]
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// groupSummary counts the tasks of one project or context
+type groupSummary struct {
+	Name  string // empty for tasks without a project
+	Open  int
+	Total int
+}
+
+// summarizeProjects lists every project on the board by name, with tasks
+// that have no project last
+func summarizeProjects(tasks []Task) []groupSummary {
+	byName := map[string]*groupSummary{}
+	for _, task := range tasks {
+		s, ok := byName[task.Project]
+		if !ok {
+			s = &groupSummary{Name: task.Project}
+			byName[task.Project] = s
+		}
+		s.Total++
+		if !task.Completed {
+			s.Open++
+		}
+	}
+
+	out := make([]groupSummary, 0, len(byName))
+	for _, s := range byName {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].Name == "") != (out[j].Name == "") {
+			return out[j].Name == ""
+		}
+		return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+	})
+	return out
+}
+
+// inProject reports whether task belongs on the board while the project
+// filter is active
+func (m model) inProject(task Task) bool {
+	return !m.projectFilter || task.Project == m.project
+}
+
+// openProjects shows the project picker, with the cursor on the active
+// project
+func (m *model) openProjects() {
+	m.mode = ViewProjects
+	m.projects = summarizeProjects(m.tasks)
+	m.projectCursor = 0
+	if m.projectFilter {
+		for i, p := range m.projects {
+			if p.Name == m.project {
+				m.projectCursor = i + 1
+			}
+		}
+	}
+}
+
+func (m model) updateProjects(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Up):
+		if m.projectCursor > 0 {
+			m.projectCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.projectCursor < len(m.projects) {
+			m.projectCursor++
+		}
+
+	case key.Matches(msg, m.keys.Confirm):
+		// The first entry is every project
+		m.projectFilter = m.projectCursor > 0
+		m.project = ""
+		if m.projectFilter {
+			m.project = m.projects[m.projectCursor-1].Name
+		}
+		m.selectedTask = 0
+		m.scrollOffset = 0
+		m.mode = ViewBoard
+	}
+
+	return m, nil
+}
+
+// projectLabel is how a project is named in the UI
+func projectLabel(name string) string {
+	if name == "" {
+		return T("projects.none")
+	}
+	return name
+}
+
+func (m model) viewProjects() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("projects.title")) + "\n")
+
+	open, total := 0, len(m.tasks)
+	for _, task := range m.tasks {
+		if !task.Completed {
+			open++
+		}
+	}
+	lines := []string{fmt.Sprintf("%-24s %s", T("projects.all"), fmt.Sprintf(T("projects.counts"), open, total))}
+	for _, p := range m.projects {
+		lines = append(lines, fmt.Sprintf("%-24s %s", truncate(projectLabel(p.Name), 24), fmt.Sprintf(T("projects.counts"), p.Open, p.Total)))
+	}
+
+	for i, line := range lines {
+		if i == m.projectCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
+
+// openSetProject prompts for the selected task's project
+func (m *model) openSetProject() tea.Cmd {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return nil
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
+			m.mode = ViewSetProject
+			m.editingTask = &m.tasks[i]
+			m.input.Reset()
+			m.input.Placeholder = T("project.placeholder")
+			m.input.SetValue(m.tasks[i].Project)
+			m.input.CursorEnd()
+			return m.input.Focus()
+		}
+	}
+	return nil
+}
+
+func (m model) updateSetProject(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = ViewBoard
+		m.editingTask = nil
+		return m, nil
+
+	case key.Matches(msg, m.keys.Confirm):
+		if m.editingTask != nil {
+			m.editingTask.Project = strings.TrimSpace(m.input.Value())
+			m.commit(fmt.Sprintf(T("status.project"), projectLabel(m.editingTask.Project)))
+		}
+		m.mode = ViewBoard
+		m.editingTask = nil
+		m.clampSelection()
+		return m, nil
+	}
+
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewSetProject() string {
+	title := T("project.title")
+	if m.editingTask != nil {
+		title = fmt.Sprintf(T("project.task_title"), truncate(m.editingTask.Title, 40))
+	}
+	styledTitle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent).
+		Render(title)
+
+	return fmt.Sprintf(
+		"%s\n\n%s\n\n%s",
+		styledTitle,
+		m.input.View(),
+		m.renderFooter(),
+	)
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+)
+
+// A protected board is stored sealed as a whole, like a sensitive
+// description, so the file reads
+//
+//	basket:sealed:v1:<base64 of the salt, the nonce and the board>
+//
+// Its journal lines and backups are sealed the same way. The key is
+// derived once per board and run, and forgotten when basket locks after
+// sitting idle. Only JSON boards can be protected.
+
+// boardKey is the key a protected board was opened with
+type boardKey struct {
+	passphrase string
+	salt       []byte
+	aead       cipher.AEAD
+}
+
+var (
+	boardKeysMu sync.Mutex
+	boardKeys   = map[string]boardKey{} // by absolute board path
+
+	// envPassphrase opens protected boards without asking, until basket
+	// locks
+	envPassphrase = os.Getenv("BASKET_PASSPHRASE")
+)
+
+// lockedBoardError is returned when reading or writing a protected board
+// whose passphrase has not been given
+type lockedBoardError struct {
+	Path string
+}
+
+func (e *lockedBoardError) Error() string {
+	return fmt.Sprintf("%s is protected, set BASKET_PASSPHRASE to open it", e.Path)
+}
+
+// isLocked reports whether err is about a protected board that could not
+// be opened
+func isLocked(err error) bool {
+	var locked *lockedBoardError
+	return errors.As(err, &locked) || errors.Is(err, errWrongPassphrase)
+}
+
+func keyPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+func lookupBoardKey(path string) (boardKey, bool) {
+	boardKeysMu.Lock()
+	defer boardKeysMu.Unlock()
+	key, ok := boardKeys[keyPath(path)]
+	return key, ok
+}
+
+func storeBoardKey(path string, key boardKey) {
+	boardKeysMu.Lock()
+	defer boardKeysMu.Unlock()
+	boardKeys[keyPath(path)] = key
+}
+
+// forgetBoardKeys locks every protected board again
+func forgetBoardKeys() {
+	boardKeysMu.Lock()
+	defer boardKeysMu.Unlock()
+	boardKeys = map[string]boardKey{}
+	envPassphrase = ""
+}
+
+// boardsUnlocked reports whether any protected board is open
+func boardsUnlocked() bool {
+	boardKeysMu.Lock()
+	defer boardKeysMu.Unlock()
+	return len(boardKeys) > 0
+}
+
+// isProtected reports whether the board at path is stored sealed
+func isProtected(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, len(sealedPrefix))
+	_, err = io.ReadFull(f, head)
+	return err == nil && string(head) == sealedPrefix
+}
+
+// boardLocked reports whether the board at path is protected and has not
+// been opened with its passphrase
+func boardLocked(path string) bool {
+	if !isProtected(path) {
+		return false
+	}
+	_, ok := lookupBoardKey(path)
+	return !ok
+}
+
+// openBoardData decrypts data read for the board at path, the board
+// itself, one of its backups or a journal line. Data in the clear is
+// returned as it is.
+func openBoardData(path string, data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(sealedPrefix)) {
+		return data, nil
+	}
+	salt, err := sealedSalt(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	key, ok := lookupBoardKey(path)
+	if ok && bytes.Equal(key.salt, salt) {
+		return openWith(key.aead, string(data))
+	}
+	pass := envPassphrase
+	if ok {
+		pass = key.passphrase
+	}
+	if pass == "" {
+		return nil, &lockedBoardError{Path: path}
+	}
+	aead, err := sealKey(pass, salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := openWith(aead, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if !ok {
+		storeBoardKey(path, boardKey{passphrase: pass, salt: salt, aead: aead})
+	}
+	return plain, nil
+}
+
+// sealBoardData encrypts data to be written for the board at path if the
+// board is protected. A protected board that is locked is never written.
+func sealBoardData(path string, data []byte) ([]byte, error) {
+	key, ok := lookupBoardKey(path)
+	if !ok {
+		if isProtected(path) {
+			return nil, &lockedBoardError{Path: path}
+		}
+		return data, nil
+	}
+	sealed, err := sealWith(key.aead, key.salt, data)
+	return []byte(sealed), err
+}
+
+// unlockBoard opens the protected board at path with passphrase
+func unlockBoard(path, passphrase string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	salt, err := sealedSalt(string(data))
+	if err != nil {
+		return fmt.Errorf("%s is not protected", path)
+	}
+	aead, err := sealKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	if _, err := openWith(aead, string(data)); err != nil {
+		return err
+	}
+	storeBoardKey(path, boardKey{passphrase: passphrase, salt: salt, aead: aead})
+	return nil
+}
+
+// protectBoard makes the next write of the board at path seal it under
+// passphrase
+func protectBoard(path, passphrase string) error {
+	salt := make([]byte, sealSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := sealKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	storeBoardKey(path, boardKey{passphrase: passphrase, salt: salt, aead: aead})
+	return nil
+}
+
+// readPassphrase asks for a passphrase on the terminal without echoing
+// it, unless BASKET_PASSPHRASE gives it
+func readPassphrase(prompt string) (string, error) {
+	if envPassphrase != "" {
+		return envPassphrase, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", fmt.Errorf("no terminal to ask for the passphrase, set BASKET_PASSPHRASE")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(pass) == 0 {
+		return "", fmt.Errorf("empty passphrase")
+	}
+	return string(pass), nil
+}
+
+// cmdProtect seals a board under a passphrase, or stores it in the clear
+// again with --off
+func cmdProtect(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("protect", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	board := fs.String("board", "", "protect a local board by name or path")
+	off := fs.Bool("off", false, "store the board in the clear again")
+	fs.Parse(args)
+
+	path := pickBoardPath(*global, *local)
+	if *board != "" {
+		var err error
+		if path, err = findBoardByName(*board); err != nil {
+			return err
+		}
+	}
+	if _, ok := formatFor(path).(jsonFormat); !ok {
+		return fmt.Errorf("only JSON boards can be protected (see basket convert)")
+	}
+
+	if *off {
+		if !isProtected(path) {
+			return fmt.Errorf("%s is not protected", path)
+		}
+		pass, err := readPassphrase("Passphrase: ")
+		if err != nil {
+			return err
+		}
+		if err := unlockBoard(path, pass); err != nil {
+			return err
+		}
+		taskList, err := loadBoard(path)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(taskList, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := backupBoard(path); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("%s is no longer protected\n", path)
+		return nil
+	}
+
+	if isProtected(path) {
+		return fmt.Errorf("%s is already protected", path)
+	}
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	pass, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return err
+	}
+	if envPassphrase == "" {
+		again, err := readPassphrase("Again: ")
+		if err != nil {
+			return err
+		}
+		if again != pass {
+			return fmt.Errorf("the passphrases differ")
+		}
+	}
+	if err := protectBoard(path, pass); err != nil {
+		return err
+	}
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	fmt.Printf("%s is protected\n", path)
+	fmt.Printf("earlier backups in %s are still in the clear\n", filepath.Join(getBackupsDir(), backupKey(path)))
+	return nil
+}
+
+// defaultLockAfter is how long protected boards stay open without a key
+// press when the config does not say
+const defaultLockAfter = 10 * time.Minute
+
+// lockAfter is how long protected boards stay open without a key press;
+// 0 keeps them open
+func (c Config) lockAfter() time.Duration {
+	if c.LockAfterMinutes == nil {
+		return defaultLockAfter
+	}
+	return time.Duration(*c.LockAfterMinutes) * time.Minute
+}
+
+// lockTickMsg checks whether basket has sat idle long enough to lock
+type lockTickMsg struct{}
+
+func (m model) lockTick() tea.Cmd {
+	if m.config.lockAfter() <= 0 {
+		return nil
+	}
+	return tea.Tick(30*time.Second, func(time.Time) tea.Msg { return lockTickMsg{} })
+}
+
+// promptBoard asks for the passphrase of the protected board at path,
+// which is opened once it is in
+func (m *model) promptBoard(path string) tea.Cmd {
+	m.mode = ViewPassphrase
+	m.unlockAction, m.lockedPath = "board", path
+	if path == m.globalPath {
+		m.globalBoard = TaskList{Tasks: []Task{}}
+	} else {
+		m.localBoard = TaskList{Tasks: []Task{}}
+	}
+	m.tasks = []Task{}
+	m.detail = ""
+	m.inputErr = ""
+	m.input.Reset()
+	m.input.Placeholder = T("passphrase.board_placeholder")
+	m.input.EchoMode = textinput.EchoPassword
+	return m.input.Focus()
+}
+
+// openUnlocked shows the board the passphrase prompt was for
+func (m *model) openUnlocked() {
+	path := m.lockedPath
+	m.lockedPath = ""
+	if path != m.globalPath {
+		m.switchLocal(path)
+		return
+	}
+	board, err := loadBoard(path)
+	if err != nil {
+		m.fail(fmt.Errorf(T("status.load_failed"), err))
+		return
+	}
+	m.globalBoard = board
+	m.showBoard(false)
+}
+
+// lockBoards forgets every passphrase and the protected boards read with
+// them, then asks again if the board on screen is one of them
+func (m *model) lockBoards() tea.Cmd {
+	forgetBoardKeys()
+	m.vault.passphrase = ""
+	m.vault.opened = map[string]string{}
+	if isProtected(m.globalPath) {
+		m.globalBoard = TaskList{Tasks: []Task{}}
+	}
+	if m.hasLocal && isProtected(m.localPath) {
+		m.localBoard = TaskList{Tasks: []Task{}}
+	}
+	if !isProtected(m.currentPath()) {
+		return nil
+	}
+	return m.promptBoard(m.currentPath())
+}
@@ -0,0 +1,522 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A query selects tasks, as in
+//
+//	priority>=high and tag:infra and due<7d and not completed
+//
+// Terms are joined with and, or and not, and grouped with parentheses;
+// and binds tighter than or, and terms next to each other are and-ed. A
+// term is a field compared to a value, a state word such as completed or
+// overdue, or any other word or "quoted text", which matches the title and
+// description.
+//
+// Dates are YYYY-MM-DD, today, tomorrow, yesterday, or a number of days
+// or weeks from today: 7d and 2w ahead, -7d back.
+
+// queryFields are the fields a term can compare, for error messages
+var queryFields = []string{"priority", "tag", "project", "assignee", "title", "text", "id", "due", "created", "updated", "completed", "planned"}
+
+// queryStates are the words that match a task's state on their own
+var queryStates = []string{"completed", "done", "open", "urgent", "overdue", "blocked", "someday", "habit", "escalated"}
+
+// queryEnv is what a query can see besides the task itself
+type queryEnv struct {
+	tasks []Task // the board, for blocked
+	now   time.Time
+}
+
+type queryMatcher func(task Task, env queryEnv) bool
+
+// taskQuery is a parsed query
+type taskQuery struct {
+	src   string
+	match queryMatcher
+}
+
+// Match reports whether task is selected. tasks is the board task is on.
+func (q *taskQuery) Match(task Task, tasks []Task, now time.Time) bool {
+	return q == nil || q.match(task, queryEnv{tasks: tasks, now: now})
+}
+
+func (q *taskQuery) String() string {
+	if q == nil {
+		return ""
+	}
+	return q.src
+}
+
+// queryError points at the offending part of a query
+type queryError struct {
+	Pos int // byte offset into the query
+	Msg string
+}
+
+func (e *queryError) Error() string {
+	return fmt.Sprintf("query: %s (at column %d)", e.Msg, e.Pos+1)
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokText           // "quoted text"
+	tokOp             // : = != < <= > >=
+	tokOpen
+	tokClose
+	tokEnd
+)
+
+type queryToken struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func lexQuery(src string) ([]queryToken, error) {
+	var toks []queryToken
+	rs := []rune(src)
+	byteAt := func(i int) int { return len(string(rs[:i])) }
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, queryToken{tokOpen, "(", byteAt(i)})
+			i++
+		case r == ')':
+			toks = append(toks, queryToken{tokClose, ")", byteAt(i)})
+			i++
+		case r == '"':
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			if j == len(rs) {
+				return nil, &queryError{byteAt(i), "unterminated quote"}
+			}
+			toks = append(toks, queryToken{tokText, string(rs[i+1 : j]), byteAt(i)})
+			i = j + 1
+		case strings.ContainsRune(":=<>!", r):
+			op := string(r)
+			if i+1 < len(rs) && rs[i+1] == '=' && r != ':' && r != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &queryError{byteAt(i), `"!" must be followed by "=", use not to negate`}
+			}
+			toks = append(toks, queryToken{tokOp, op, byteAt(i)})
+			i += len([]rune(op))
+		default:
+			j := i
+			for j < len(rs) && !unicode.IsSpace(rs[j]) && !strings.ContainsRune(`():=<>!"`, rs[j]) {
+				j++
+			}
+			toks = append(toks, queryToken{tokWord, string(rs[i:j]), byteAt(i)})
+			i = j
+		}
+	}
+	return append(toks, queryToken{tokEnd, "", len(src)}), nil
+}
+
+type queryParser struct {
+	toks []queryToken
+	i    int
+}
+
+func (p *queryParser) peek() queryToken { return p.toks[p.i] }
+
+func (p *queryParser) next() queryToken {
+	t := p.toks[p.i]
+	if t.kind != tokEnd {
+		p.i++
+	}
+	return t
+}
+
+func isKeyword(t queryToken, word string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.val, word)
+}
+
+// parseQuery parses src; an empty query is nil and matches everything
+func parseQuery(src string) (*taskQuery, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	toks, err := lexQuery(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{toks: toks}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEnd {
+		if t.kind == tokClose {
+			return nil, &queryError{t.pos, `")" without "("`}
+		}
+		return nil, &queryError{t.pos, fmt.Sprintf("unexpected %q", t.val)}
+	}
+	return &taskQuery{src: strings.TrimSpace(src), match: match}, nil
+}
+
+func (p *queryParser) parseOr() (queryMatcher, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(task Task, env queryEnv) bool { return l(task, env) || right(task, env) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (queryMatcher, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind == tokEnd || t.kind == tokClose || isKeyword(t, "or") {
+			return left, nil
+		}
+		if isKeyword(t, "and") {
+			p.next()
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(task Task, env queryEnv) bool { return l(task, env) && right(task, env) }
+	}
+}
+
+func (p *queryParser) parseNot() (queryMatcher, error) {
+	if isKeyword(p.peek(), "not") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(task Task, env queryEnv) bool { return !inner(task, env) }, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *queryParser) parseTerm() (queryMatcher, error) {
+	t := p.next()
+	switch t.kind {
+	case tokEnd:
+		return nil, &queryError{t.pos, "the query ends too early"}
+	case tokOpen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokClose {
+			return nil, &queryError{t.pos, `"(" is never closed`}
+		}
+		return inner, nil
+	case tokClose:
+		return nil, &queryError{t.pos, `")" without "("`}
+	case tokOp:
+		return nil, &queryError{t.pos, fmt.Sprintf("%q needs a field before it, one of %s", t.val, strings.Join(queryFields, ", "))}
+	case tokText:
+		return textMatcher(t.val), nil
+	}
+
+	if isKeyword(t, "and") || isKeyword(t, "or") {
+		return nil, &queryError{t.pos, fmt.Sprintf("%q needs a term before it", t.val)}
+	}
+	if op := p.peek(); op.kind == tokOp {
+		p.next()
+		v := p.next()
+		if v.kind != tokWord && v.kind != tokText {
+			return nil, &queryError{op.pos, fmt.Sprintf("expected a value after %s%s", t.val, op.val)}
+		}
+		return fieldMatcher(strings.ToLower(t.val), op, v)
+	}
+	if ctx, ok := strings.CutPrefix(t.val, "@"); ok && ctx != "" {
+		return func(task Task, env queryEnv) bool { return hasContext(task, ctx) }, nil
+	}
+	if m := stateMatcher(strings.ToLower(t.val)); m != nil {
+		return m, nil
+	}
+	return textMatcher(t.val), nil
+}
+
+func textMatcher(text string) queryMatcher {
+	text = strings.ToLower(text)
+	return func(task Task, env queryEnv) bool {
+		return strings.Contains(strings.ToLower(task.Title), text) ||
+			strings.Contains(strings.ToLower(task.Description), text)
+	}
+}
+
+func stateMatcher(word string) queryMatcher {
+	switch word {
+	case "completed", "done":
+		return func(task Task, env queryEnv) bool { return task.Completed }
+	case "open":
+		return func(task Task, env queryEnv) bool { return !task.Completed }
+	case "urgent":
+		return func(task Task, env queryEnv) bool { return task.Urgent }
+	case "overdue":
+		return func(task Task, env queryEnv) bool { return isOverdue(task, env.now) }
+	case "blocked":
+		return func(task Task, env queryEnv) bool {
+			return isBlocked(env.tasks, task) || hasContext(task, "blocked")
+		}
+	case "someday":
+		return func(task Task, env queryEnv) bool { return task.Someday }
+	case "habit":
+		return func(task Task, env queryEnv) bool { return task.Habit }
+	case "escalated":
+		return func(task Task, env queryEnv) bool { return isEscalated(task) }
+	}
+	return nil
+}
+
+func fieldMatcher(field string, op, v queryToken) (queryMatcher, error) {
+	value := v.val
+	onlyEquality := func() error {
+		if op.val != ":" && op.val != "=" && op.val != "!=" {
+			return &queryError{op.pos, fmt.Sprintf("%s can only be compared with : or !=", field)}
+		}
+		return nil
+	}
+	negate := func(m queryMatcher) queryMatcher {
+		if op.val != "!=" {
+			return m
+		}
+		return func(task Task, env queryEnv) bool { return !m(task, env) }
+	}
+
+	switch field {
+	case "priority", "prio", "p":
+		want, ok := parsePriorityName(value)
+		if !ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || Priority(n) > maxPriority() {
+				var names []string
+				for _, p := range allPriorities() {
+					names = append(names, strings.ToLower(p.String()))
+				}
+				return nil, &queryError{v.pos, fmt.Sprintf("unknown priority %q, use one of %s", value, strings.Join(names, ", "))}
+			}
+			want = Priority(n)
+		}
+		return func(task Task, env queryEnv) bool {
+			return compareInts(int(clampPriority(task.Priority)), int(want), op.val)
+		}, nil
+
+	case "tag", "context":
+		if err := onlyEquality(); err != nil {
+			return nil, err
+		}
+		return negate(func(task Task, env queryEnv) bool { return hasContext(task, value) }), nil
+
+	case "project":
+		if err := onlyEquality(); err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(value, "none") {
+			value = ""
+		}
+		return negate(func(task Task, env queryEnv) bool { return strings.EqualFold(task.Project, value) }), nil
+
+	case "assignee":
+		if err := onlyEquality(); err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(value, "none") {
+			value = ""
+		}
+		return negate(func(task Task, env queryEnv) bool { return sameAssignee(task.Assignee, value) }), nil
+
+	case "title":
+		if err := onlyEquality(); err != nil {
+			return nil, err
+		}
+		value = strings.ToLower(value)
+		return negate(func(task Task, env queryEnv) bool { return strings.Contains(strings.ToLower(task.Title), value) }), nil
+
+	case "text":
+		if err := onlyEquality(); err != nil {
+			return nil, err
+		}
+		return negate(textMatcher(value)), nil
+
+	case "id":
+		if err := onlyEquality(); err != nil {
+			return nil, err
+		}
+		value = strings.ToLower(strings.TrimPrefix(value, "#"))
+		return negate(func(task Task, env queryEnv) bool {
+			id := strings.ToLower(task.ID)
+			return strings.HasPrefix(id, value) || strings.HasSuffix(id, value)
+		}), nil
+
+	case "due", "created", "updated", "completed", "planned":
+		get := map[string]func(Task) *time.Time{
+			"due":       func(t Task) *time.Time { return t.Due },
+			"created":   func(t Task) *time.Time { return &t.CreatedAt },
+			"updated":   func(t Task) *time.Time { u := lastModified(t); return &u },
+			"completed": func(t Task) *time.Time { return t.CompletedAt },
+			"planned":   func(t Task) *time.Time { return t.PlannedOn },
+		}[field]
+		if strings.EqualFold(value, "none") {
+			if err := onlyEquality(); err != nil {
+				return nil, err
+			}
+			return negate(func(task Task, env queryEnv) bool { return get(task) == nil }), nil
+		}
+		if _, err := queryDay(value, time.Now()); err != nil {
+			return nil, &queryError{v.pos, err.Error()}
+		}
+		return func(task Task, env queryEnv) bool {
+			t := get(task)
+			if t == nil {
+				return false
+			}
+			day, _ := queryDay(value, env.now)
+			return compareInts(startOfDay(t.Local()).Compare(day), 0, op.val)
+		}, nil
+	}
+
+	return nil, &queryError{v.pos - len(field) - len(op.val), fmt.Sprintf("unknown field %q, use one of %s", field, strings.Join(queryFields, ", "))}
+}
+
+// queryDay resolves a date value to the start of that day
+func queryDay(value string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if len(value) > 1 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil {
+			switch value[len(value)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date, use YYYY-MM-DD, today, tomorrow, yesterday or a number of days like 7d or -2w", value)
+}
+
+// pointAtQueryError adds the query and a caret under the offending part
+// to a parse error, for the terminal
+func pointAtQueryError(src string, err error) error {
+	var qe *queryError
+	if !errors.As(err, &qe) {
+		return err
+	}
+	caret := strings.Repeat(" ", len([]rune(src[:min(qe.Pos, len(src))]))) + "^"
+	return fmt.Errorf("%w\n  %s\n  %s", err, src, caret)
+}
+
+// compareInts applies a query operator to a and b
+func compareInts(a, b int, op string) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "!=":
+		return a != b
+	}
+	return a == b
+}
+
+func (m model) updateQuery(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = ViewBoard
+		return m, nil
+
+	case key.Matches(msg, m.keys.Complete):
+		if tags := tagSuggestions(m.tasks, m.input.Value()); len(tags) > 0 {
+			m.input.SetValue(completeTag(m.input.Value(), tags[0]))
+			m.input.CursorEnd()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Confirm):
+		query, err := parseQuery(m.input.Value())
+		if err != nil {
+			m.inputErr = err.Error()
+			return m, nil
+		}
+		m.query = query
+		m.selectedTask = 0
+		m.scrollOffset = 0
+		m.mode = ViewBoard
+		return m, nil
+	}
+
+	m.inputErr = ""
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewQuery() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent).
+		Render(T("query.title"))
+
+	errLine := helpStyle.Render(T("query.hint"))
+	if m.inputErr != "" {
+		errLine = lipgloss.NewStyle().
+			Foreground(colorError).
+			Render(m.inputErr)
+	}
+
+	return fmt.Sprintf(
+		"%s\n\n%s\n%s\n%s\n%s",
+		title,
+		m.input.View(),
+		errLine,
+		renderTagSuggestions(tagSuggestions(m.tasks, m.input.Value()), m.keys),
+		m.renderFooter(),
+	)
+}
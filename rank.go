@@ -0,0 +1,213 @@
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// A task's rank places it within its column. Ranks are strings of base-36
+// digits compared as text, so a task can always be put between two others
+// by giving it a rank between theirs, without renumbering the rest; and
+// the order travels with the tasks through imports, merges and syncs.
+
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// midRank is the rank halfway between a and b, "" being no bound. It
+// never ends in 0, so there is always room below it.
+func midRank(a, b string) string {
+	var out []byte
+	bounded := b != ""
+	for i := 0; ; i++ {
+		lo, hi := 0, len(rankDigits)
+		if i < len(a) {
+			lo = strings.IndexByte(rankDigits, a[i])
+		}
+		if bounded && i < len(b) {
+			hi = strings.IndexByte(rankDigits, b[i])
+		}
+		if hi-lo > 1 {
+			return string(append(out, rankDigits[(lo+hi)/2]))
+		}
+		out = append(out, rankDigits[lo])
+		if lo < hi {
+			bounded = false
+		}
+	}
+}
+
+// rankBetween is a rank that sorts after a and before b, "" being no
+// bound. At either end it steps by one digit rather than halving, so
+// adding task after task keeps ranks short.
+func rankBetween(a, b string) string {
+	switch {
+	case a == "" && b == "":
+		return midRank("", "")
+	case b == "" || a >= b:
+		for i := 0; i < len(a); i++ {
+			if d := strings.IndexByte(rankDigits, a[i]); d < len(rankDigits)-1 {
+				return a[:i] + string(rankDigits[d+1])
+			}
+		}
+		return a + "1"
+	case a == "":
+		for i := 0; i < len(b); i++ {
+			switch d := strings.IndexByte(rankDigits, b[i]); {
+			case d > 1:
+				return b[:i] + string(rankDigits[d-1])
+			case d == 1:
+				return b[:i] + "0" + midRank("", "")
+			}
+		}
+	}
+	return midRank(a, b)
+}
+
+// spreadRanks is n ranks evenly between a and b, in order
+func spreadRanks(a, b string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	half := n / 2
+	mid := midRank(a, b)
+	out := append(spreadRanks(a, mid, half), mid)
+	return append(out, spreadRanks(mid, b, n-half-1)...)
+}
+
+// ensureRanks ranks the tasks that have none after all the others, in
+// the order they are listed, which is how boards were ordered before
+// ranks
+func ensureRanks(tasks []Task) {
+	last, missing := "", 0
+	for _, task := range tasks {
+		if task.Rank == "" {
+			missing++
+		} else if task.Rank > last {
+			last = task.Rank
+		}
+	}
+	if missing == 0 {
+		return
+	}
+	var ranks []string
+	if missing == 1 {
+		ranks = []string{rankBetween(last, "")}
+	} else {
+		ranks = spreadRanks(last, "", missing)
+	}
+	for i := range tasks {
+		if tasks[i].Rank == "" {
+			tasks[i].Rank, ranks = ranks[0], ranks[1:]
+		}
+	}
+}
+
+// sortByRank orders tasks by rank. Tasks without one, or with the same,
+// keep their order.
+func sortByRank(tasks []Task) {
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		switch {
+		case a.Rank == b.Rank:
+			return 0
+		case a.Rank == "":
+			return 1
+		case b.Rank == "":
+			return -1
+		}
+		return strings.Compare(a.Rank, b.Rank)
+	})
+}
+
+// cardGroup is where in its column a task goes: pinned tasks first, then
+// the rest, then completed ones if they sink
+func cardGroup(task Task, sinkCompleted bool) int {
+	switch {
+	case sinkCompleted && task.Completed:
+		return 2
+	case task.Pinned:
+		return 0
+	}
+	return 1
+}
+
+// togglePinned pins the selected card to the top of its column, or lets
+// it go
+func (m *model) togglePinned() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	id := tasksInCol[m.selectedTask].ID
+	for i := range m.tasks {
+		if m.tasks[i].ID == id {
+			m.tasks[i].Pinned = !m.tasks[i].Pinned
+			if m.tasks[i].Pinned {
+				m.commit(T("status.pinned"))
+			} else {
+				m.commit(T("status.unpinned"))
+			}
+			m.selectTask(id)
+			return
+		}
+	}
+}
+
+// moveInColumn moves the selected card up (by -1) or down (by 1) its
+// column, ranking it past its neighbor
+func (m *model) moveInColumn(by int) {
+	if m.sortUrgency {
+		m.notify(T("status.rank_urgency"))
+		return
+	}
+	tasks := m.getTasksInColumn(Priority(m.selectedCol))
+	from, to := m.selectedTask, m.selectedTask+by
+	if from >= len(tasks) || to < 0 || to >= len(tasks) {
+		return
+	}
+	// Cards only move among their own kind; pin a card to lift it higher
+	group := cardGroup(tasks[from], m.sinkCompleted)
+	if cardGroup(tasks[to], m.sinkCompleted) != group {
+		return
+	}
+	// Tied ranks, as two boards merged may have, leave no room between
+	for i := 1; i < len(tasks); i++ {
+		if tasks[i].Rank <= tasks[i-1].Rank && cardGroup(tasks[i], m.sinkCompleted) == cardGroup(tasks[i-1], m.sinkCompleted) {
+			m.rerankColumn(tasks)
+			tasks = m.getTasksInColumn(Priority(m.selectedCol))
+			break
+		}
+	}
+
+	var rank string
+	if by < 0 {
+		before := ""
+		if to > 0 && cardGroup(tasks[to-1], m.sinkCompleted) == group {
+			before = tasks[to-1].Rank
+		}
+		rank = rankBetween(before, tasks[to].Rank)
+	} else {
+		after := ""
+		if to+1 < len(tasks) && cardGroup(tasks[to+1], m.sinkCompleted) == group {
+			after = tasks[to+1].Rank
+		}
+		rank = rankBetween(tasks[to].Rank, after)
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == tasks[from].ID {
+			m.tasks[i].Rank = rank
+		}
+	}
+	m.selectedTask = to
+	m.commit(T("status.reordered"))
+}
+
+// rerankColumn spreads fresh ranks over tasks, keeping their order
+func (m *model) rerankColumn(tasks []Task) {
+	ranks := spreadRanks("", "", len(tasks))
+	index := map[string]int{}
+	for i := range m.tasks {
+		index[m.tasks[i].ID] = i
+	}
+	for n, task := range tasks {
+		m.tasks[index[task.ID]].Rank = ranks[n]
+	}
+}
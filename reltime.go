@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// relativeTickInterval is how often cards showing relative times are
+// redrawn, so "due in 3h" keeps counting down in a long session
+const relativeTickInterval = time.Minute
+
+// relativeTickMsg redraws the board
+type relativeTickMsg struct{}
+
+// relativeTick schedules the next redraw while relative times are shown
+func (m model) relativeTick() tea.Cmd {
+	if !m.config.RelativeTimes {
+		return nil
+	}
+	return tea.Tick(relativeTickInterval, func(time.Time) tea.Msg { return relativeTickMsg{} })
+}
+
+// formatSpan renders d roughly, in its largest whole unit: "5m", "3h",
+// "2d" or "6w"
+func formatSpan(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(int(d.Minutes()), 1))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%dw", int(d.Hours()/24/7))
+}
+
+// formatAgo renders how long ago t was, e.g. "2d ago"
+func formatAgo(t, now time.Time) string {
+	return fmt.Sprintf(T("card.ago"), formatSpan(now.Sub(t)))
+}
+
+// formatDueIn renders a due date relative to now, e.g. "due in 3h". A
+// date without a time counts in days, as it is due all that day.
+func formatDueIn(due, now time.Time) string {
+	due = due.Local()
+	if due.Equal(startOfDay(due)) {
+		days := int(math.Round(due.Sub(startOfDay(now)).Hours() / 24))
+		switch {
+		case days == 0:
+			return T("card.due_today")
+		case days > 0:
+			return fmt.Sprintf(T("card.due_in"), fmt.Sprintf("%dd", days))
+		}
+		return fmt.Sprintf(T("card.due_ago"), fmt.Sprintf("%dd", -days))
+	}
+	if due.Before(now) {
+		return fmt.Sprintf(T("card.due_ago"), formatSpan(now.Sub(due)))
+	}
+	return fmt.Sprintf(T("card.due_in"), formatSpan(due.Sub(now)))
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Apple Reminders has no export of its own, so reminders travel as the
+// CSV the common export shortcuts and apps write (Title, Notes, Due Date,
+// Priority, Completed, List), or as iCalendar to-dos. On macOS they can
+// also be read straight from the Reminders app.
+
+// remindersColumns are the columns of an exported CSV, in order
+var remindersColumns = []string{"Title", "Notes", "Due Date", "Priority", "Completed", "List"}
+
+// remindersDateFormats are the due dates a Reminders CSV may hold
+var remindersDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"1/2/06, 3:04 PM",
+	"1/2/2006 15:04",
+	"1/2/2006",
+	"02.01.2006 15:04",
+	"02.01.2006",
+}
+
+// isCSVFile reports whether path is a CSV file
+func isCSVFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".csv")
+}
+
+// remindersPriority is the Reminders priority name of a level
+func remindersPriority(p Priority) string {
+	switch n := icalPriority(p); {
+	case n <= 4:
+		return "High"
+	case n == 5:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// fromRemindersPriority reads a priority as a name or a number on the
+// iCalendar scale
+func fromRemindersPriority(s string) Priority {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "high", "!!!":
+		return fromICalPriority(1)
+	case "medium", "!!":
+		return fromICalPriority(5)
+	case "low", "!":
+		return fromICalPriority(9)
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return fromICalPriority(n)
+}
+
+// remindersCSVTasks reads the reminders of a CSV export with a header row.
+// The columns are found by name, so their order does not matter.
+func remindersCSVTasks(r io.Reader, now time.Time) ([]Task, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	col := map[string]int{}
+	for i, name := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "title", "name", "reminder":
+			col["title"] = i
+		case "notes", "note", "description", "body":
+			col["notes"] = i
+		case "due date", "due", "due date and time":
+			col["due"] = i
+		case "priority":
+			col["priority"] = i
+		case "completed", "is completed", "done":
+			col["completed"] = i
+		case "list", "list name":
+			col["list"] = i
+		}
+	}
+	if _, ok := col["title"]; !ok {
+		return nil, fmt.Errorf("no Title column in the header")
+	}
+	field := func(record []string, name string) string {
+		i, ok := col[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var tasks []Task
+	for n, record := range records[1:] {
+		title := field(record, "title")
+		if title == "" {
+			continue
+		}
+		task := Task{
+			ID:          generateID(),
+			Title:       title,
+			Description: field(record, "notes"),
+			Priority:    fromRemindersPriority(field(record, "priority")),
+			Project:     field(record, "list"),
+			CreatedAt:   now,
+		}
+		if s := field(record, "due"); s != "" {
+			due, err := parseRemindersDate(s)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+2, err)
+			}
+			task.Due = &due
+		}
+		switch strings.ToLower(field(record, "completed")) {
+		case "yes", "true", "1", "x":
+			setCompleted(&task, true)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func parseRemindersDate(s string) (time.Time, error) {
+	for _, layout := range remindersDateFormats {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown due date %q", s)
+}
+
+// writeRemindersCSV writes tasks as a Reminders CSV, with the project as
+// the list
+func writeRemindersCSV(w io.Writer, tasks []Task) error {
+	cw := csv.NewWriter(w)
+	cw.Write(remindersColumns)
+	for _, task := range tasks {
+		due := ""
+		if task.Due != nil {
+			if d := task.Due.Local(); d.Equal(startOfDay(d)) {
+				due = d.Format("2006-01-02")
+			} else {
+				due = d.Format("2006-01-02 15:04")
+			}
+		}
+		completed := "No"
+		if task.Completed {
+			completed = "Yes"
+		}
+		cw.Write([]string{task.Title, task.Description, due, remindersPriority(clampPriority(task.Priority)), completed, task.Project})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeTasksICS writes tasks as a calendar of to-dos, which Reminders and
+// other task apps import
+func writeTasksICS(w io.Writer, tasks []Task) error {
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN", "VCALENDAR")
+	writeICalLine(&b, "VERSION", "2.0")
+	writeICalLine(&b, "PRODID", "-//basket//EN")
+	for _, task := range tasks {
+		writeVTODO(&b, task)
+	}
+	writeICalLine(&b, "END", "VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// remindersScript lists the open reminders of the Reminders app as JSON,
+// those of one list if a name is passed
+const remindersScript = `function run(argv) {
+	const want = argv[0] || "";
+	const out = [];
+	Application("Reminders").lists().forEach(list => {
+		if (want && list.name() !== want) return;
+		list.reminders.whose({completed: false})().forEach(r => {
+			const due = r.dueDate();
+			out.push({title: r.name(), notes: r.body() || "", due: due ? due.toISOString() : "",
+				priority: r.priority(), list: list.name()});
+		});
+	});
+	return JSON.stringify(out);
+}`
+
+// appReminders reads the open reminders from the Reminders app on macOS,
+// of one list unless list is empty
+func appReminders(list string, now time.Time) ([]Task, error) {
+	if runtime.GOOS != "darwin" {
+		return nil, fmt.Errorf("reading the Reminders app needs macOS; export a CSV instead")
+	}
+	out, err := exec.Command("osascript", "-l", "JavaScript", "-e", remindersScript, list).Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) > 0 {
+			return nil, fmt.Errorf("reminders: %s", strings.TrimSpace(string(exit.Stderr)))
+		}
+		return nil, err
+	}
+	var reminders []struct {
+		Title    string `json:"title"`
+		Notes    string `json:"notes"`
+		Due      string `json:"due"`
+		Priority int    `json:"priority"`
+		List     string `json:"list"`
+	}
+	if err := json.Unmarshal(out, &reminders); err != nil {
+		return nil, fmt.Errorf("reminders: %w", err)
+	}
+
+	var tasks []Task
+	for _, r := range reminders {
+		task := Task{
+			ID:          generateID(),
+			Title:       strings.TrimSpace(r.Title),
+			Description: strings.TrimSpace(r.Notes),
+			Priority:    fromICalPriority(r.Priority),
+			Project:     r.List,
+			CreatedAt:   now,
+		}
+		if due, err := time.Parse(time.RFC3339, r.Due); err == nil {
+			due = due.Local()
+			task.Due = &due
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// importReminders adds reminders to the board at path, from a CSV export
+// or, with no file, from the Reminders app
+func importReminders(cfg Config, path, file, list, project string, dryRun bool) error {
+	var incoming []Task
+	if file == "" {
+		var err error
+		if incoming, err = appReminders(list, time.Now()); err != nil {
+			return err
+		}
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if incoming, err = remindersCSVTasks(f, time.Now()); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return addImported(cfg, path, incoming, project, dryRun)
+}
+
+// exportableTasks are the tasks other apps get: habits stay behind
+func exportableTasks(tasks []Task) []Task {
+	var out []Task
+	for _, task := range tasks {
+		if !task.Habit {
+			out = append(out, task)
+		}
+	}
+	return out
+}
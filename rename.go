@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// substitution is a search and replace written as in sed or vim,
+// s/old/new/ with the flags g (every match, not just the first) and i
+// (ignore case). old is a regular expression and new may refer to its
+// groups as $1.
+type substitution struct {
+	re      *regexp.Regexp
+	repl    string
+	replAll bool
+}
+
+// parseSubstitution reads s/old/new/flags. Any character after the s is
+// the delimiter, and a backslash escapes it.
+func parseSubstitution(expr string) (substitution, error) {
+	var s substitution
+	if len(expr) < 2 || expr[0] != 's' {
+		return s, fmt.Errorf("%q is not of the form s/old/new/", expr)
+	}
+	delim := expr[1]
+	var parts []string
+	var cur strings.Builder
+	for i := 2; i < len(expr); i++ {
+		switch {
+		case expr[i] == '\\' && i+1 < len(expr) && expr[i+1] == delim:
+			cur.WriteByte(delim)
+			i++
+		case expr[i] == delim:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(expr[i])
+		}
+	}
+	parts = append(parts, cur.String())
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return s, fmt.Errorf("%q is not of the form s/old/new/", expr)
+	}
+
+	pattern := parts[0]
+	if len(parts) == 3 {
+		for _, flag := range parts[2] {
+			switch flag {
+			case 'g':
+				s.replAll = true
+			case 'i':
+				pattern = "(?i)" + pattern
+			default:
+				return s, fmt.Errorf("unknown flag %q in %q", flag, expr)
+			}
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return s, err
+	}
+	s.re, s.repl = re, parts[1]
+	return s, nil
+}
+
+// apply is title with the substitution made
+func (s substitution) apply(title string) string {
+	if s.replAll {
+		return s.re.ReplaceAllString(title, s.repl)
+	}
+	loc := s.re.FindStringSubmatchIndex(title)
+	if loc == nil {
+		return title
+	}
+	out := s.re.ExpandString(nil, s.repl, title, loc)
+	return title[:loc[0]] + string(out) + title[loc[1]:]
+}
+
+func cmdRename(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("rename", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	queryStr := fs.String("query", "", "only rename tasks matching a query")
+	replace := fs.String("replace", "", "the change to make to titles, as s/old/new/ with flags g and i")
+	yes := fs.Bool("yes", false, "rename without asking")
+	dryRun := fs.Bool("dry-run", false, "only show what would be renamed")
+	fs.Parse(args)
+
+	if *replace == "" {
+		return fmt.Errorf("usage: basket rename --replace s/old/new/ [--query q]")
+	}
+	sub, err := parseSubstitution(*replace)
+	if err != nil {
+		return err
+	}
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	query, err := parseQuery(*queryStr)
+	if err != nil {
+		return pointAtQueryError(*queryStr, err)
+	}
+	before := cloneTasks(taskList.Tasks)
+
+	now := time.Now()
+	var renamed []int // indexes into taskList.Tasks
+	titles := map[int]string{}
+	for i, task := range taskList.Tasks {
+		if !query.Match(task, taskList.Tasks, now) {
+			continue
+		}
+		if title := strings.TrimSpace(sub.apply(task.Title)); title != task.Title && title != "" {
+			renamed = append(renamed, i)
+			titles[i] = title
+		}
+	}
+	if len(renamed) == 0 {
+		fmt.Println("no titles match")
+		return nil
+	}
+	for _, i := range renamed {
+		fmt.Printf("%-6s  %s\n        -> %s\n", shortID(taskList.Tasks[i].ID), taskList.Tasks[i].Title, titles[i])
+	}
+
+	if *dryRun {
+		fmt.Printf("would rename %d task(s) in %s\n", len(renamed), path)
+		return nil
+	}
+	if !*yes {
+		fmt.Printf("rename %d task(s)? [y/n] ", len(renamed))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Println("no changes")
+			return nil
+		}
+	}
+	for _, i := range renamed {
+		taskList.Tasks[i].Title = titles[i]
+	}
+	touchTasks(before, taskList.Tasks, now)
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("renamed %d task(s) in %s\n", len(renamed), path)
+	return nil
+}
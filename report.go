@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cmdReport dispatches `basket report <kind>`
+func cmdReport(cfg Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: basket report time|weekly|standup|estimates")
+	}
+	switch args[0] {
+	case "time":
+		return cmdReportTime(cfg, args[1:])
+	case "weekly":
+		return cmdReportWeekly(cfg, args[1:])
+	case "standup":
+		return cmdReportStandup(cfg, args[1:])
+	case "estimates":
+		return cmdReportEstimates(cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown report %q", args[0])
+	}
+}
+
+// timeRow is one line of the time report
+type timeRow struct {
+	ID      string  `json:"id,omitempty"`
+	Title   string  `json:"title,omitempty"`
+	Project string  `json:"project"`
+	Minutes float64 `json:"minutes"`
+	Time    string  `json:"time"`
+}
+
+// cmdReportTime sums the time logged per task or project since a cutoff,
+// archived tasks included
+func cmdReportTime(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("report time", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	since := fs.String("since", "7d", "only count time logged within this long")
+	by := fs.String("by", "task", "group by task or project")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	fs.Parse(args)
+
+	age, err := parseAge(*since)
+	if err != nil {
+		return err
+	}
+	if *by != "task" && *by != "project" {
+		return fmt.Errorf("--by must be task or project")
+	}
+
+	taskList, err := loadBoard(resolveBoardPath(*global, *local))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	from := now.Add(-age)
+
+	spent := map[string]time.Duration{}
+	rows := map[string]*timeRow{}
+	var total time.Duration
+	for _, task := range append(append([]Task{}, taskList.Tasks...), taskList.Archive...) {
+		d := trackedBetween(task, from, now, now)
+		if d == 0 {
+			continue
+		}
+		group := task.ID
+		if *by == "project" {
+			group = task.Project
+		}
+		if rows[group] == nil {
+			rows[group] = &timeRow{Project: task.Project}
+			if *by == "task" {
+				rows[group].ID = task.ID
+				rows[group].Title = task.Title
+			}
+		}
+		spent[group] += d
+		total += d
+	}
+
+	var out []timeRow
+	for group, row := range rows {
+		row.Minutes = spent[group].Round(time.Minute).Minutes()
+		row.Time = formatDuration(spent[group])
+		out = append(out, *row)
+	}
+	// Most time first
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Minutes != out[j].Minutes {
+			return out[i].Minutes > out[j].Minutes
+		}
+		return out[i].Title+out[i].Project < out[j].Title+out[j].Project
+	})
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"since":   from.Format(time.RFC3339),
+			"rows":    out,
+			"minutes": total.Round(time.Minute).Minutes(),
+		})
+	}
+
+	for _, row := range out {
+		if *by == "project" {
+			fmt.Printf("%8s  %s\n", row.Time, projectLabel(row.Project))
+		} else {
+			fmt.Printf("%8s  %-6s  %s\n", row.Time, shortID(row.ID), row.Title)
+		}
+	}
+	fmt.Printf("%8s  total since %s\n", formatDuration(total), from.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+// startOfWeek is midnight on the Monday of the week holding t
+func startOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// weeklyGroups splits tasks by priority, highest first, or by project,
+// alphabetically with tasks without one last
+func weeklyGroups(tasks []Task, byProject bool) ([]string, map[string][]Task) {
+	groups := map[string][]Task{}
+	var names []string
+	if byProject {
+		for _, task := range tasks {
+			if _, ok := groups[task.Project]; !ok {
+				names = append(names, task.Project)
+			}
+			groups[task.Project] = append(groups[task.Project], task)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if (names[i] == "") != (names[j] == "") {
+				return names[j] == ""
+			}
+			return strings.ToLower(names[i]) < strings.ToLower(names[j])
+		})
+		return names, groups
+	}
+	for p := maxPriority(); p >= 0; p-- {
+		for _, task := range tasks {
+			if clampPriority(task.Priority) == p {
+				groups[p.String()] = append(groups[p.String()], task)
+			}
+		}
+		if len(groups[p.String()]) > 0 {
+			names = append(names, p.String())
+		}
+	}
+	return names, groups
+}
+
+// cmdReportWeekly prints a Markdown status update of the week so far:
+// what got done, what came in and what is still open
+func cmdReportWeekly(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("report weekly", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	by := fs.String("by", "priority", "group by priority or project")
+	last := fs.Bool("last", false, "report on last week instead of this one")
+	fs.Parse(args)
+
+	if *by != "priority" && *by != "project" {
+		return fmt.Errorf("--by must be priority or project")
+	}
+
+	taskList, err := loadBoard(resolveBoardPath(*global, *local))
+	if err != nil {
+		return err
+	}
+	from := startOfWeek(time.Now())
+	if *last {
+		from = from.AddDate(0, 0, -7)
+	}
+	to := from.AddDate(0, 0, 7)
+	within := func(t *time.Time) bool {
+		return t != nil && !t.Before(from) && t.Before(to)
+	}
+
+	var completed, added, open []Task
+	for _, task := range append(append([]Task{}, taskList.Tasks...), taskList.Archive...) {
+		if task.Completed && within(task.CompletedAt) {
+			completed = append(completed, task)
+		}
+		if within(&task.CreatedAt) {
+			added = append(added, task)
+		}
+	}
+	for _, task := range taskList.Tasks {
+		if !task.Completed && !offBoard(task) {
+			open = append(open, task)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Week of %s\n", from.Format("January 2, 2006"))
+	section := func(title string, tasks []Task) {
+		fmt.Fprintf(&b, "\n## %s (%d)\n", title, len(tasks))
+		if len(tasks) == 0 {
+			b.WriteString("\n_None_\n")
+			return
+		}
+		names, groups := weeklyGroups(tasks, *by == "project")
+		for _, name := range names {
+			heading := name
+			if *by == "project" {
+				heading = projectLabel(name)
+			}
+			fmt.Fprintf(&b, "\n### %s\n\n", heading)
+			for _, task := range groups[name] {
+				state := " "
+				if task.Completed {
+					state = "x"
+				}
+				fmt.Fprintf(&b, "- [%s] %s\n", state, task.Title)
+			}
+		}
+	}
+	section("Completed", completed)
+	section("Added", added)
+	section("Still open", open)
+
+	_, err = fmt.Print(b.String())
+	return err
+}
+
+// previousWorkday is midnight of the last weekday before t, so Monday's
+// standup covers Friday and the weekend
+func previousWorkday(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// isPlanned reports whether an open task is on today's plan: one of the
+// two highest levels, flagged urgent, or due by today
+func isPlanned(task Task, now time.Time) bool {
+	if task.Completed || offBoard(task) {
+		return false
+	}
+	return task.Priority >= maxPriority()-1 || task.Urgent || isDueToday(task, now) || isOverdue(task, now)
+}
+
+// cmdReportStandup prints what was done from the previous workday up to
+// the start of today, what is planned for today and what is blocked:
+// waiting on another open task, or marked with the @blocked context.
+func cmdReportStandup(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("report standup", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	mine := fs.Bool("mine", false, "only tasks assigned to you")
+	fs.Parse(args)
+
+	taskList, err := loadBoard(resolveBoardPath(*global, *local))
+	if err != nil {
+		return err
+	}
+	user := currentUser(cfg)
+	from, to := previousWorkday(time.Now()), startOfDay(time.Now())
+
+	var done, planned, blocked []Task
+	for _, task := range append(append([]Task{}, taskList.Tasks...), taskList.Archive...) {
+		if *mine && !sameAssignee(task.Assignee, user) {
+			continue
+		}
+		switch {
+		case task.Completed:
+			if task.CompletedAt != nil && !task.CompletedAt.Before(from) && task.CompletedAt.Before(to) {
+				done = append(done, task)
+			}
+		case task.ArchivedAt != nil || offBoard(task):
+			// Off the board, so neither planned nor blocked
+		case hasContext(task, "blocked") || isBlocked(taskList.Tasks, task):
+			blocked = append(blocked, task)
+		case isPlanned(task, time.Now()):
+			planned = append(planned, task)
+		}
+	}
+	sort.SliceStable(planned, func(i, j int) bool {
+		return urgencyScore(planned[i], time.Now()) > urgencyScore(planned[j], time.Now())
+	})
+
+	var b strings.Builder
+	section := func(title string, tasks []Task) {
+		fmt.Fprintf(&b, "**%s**\n", title)
+		if len(tasks) == 0 {
+			b.WriteString("- nothing\n")
+		}
+		for _, task := range tasks {
+			fmt.Fprintf(&b, "- %s\n", task.Title)
+		}
+		b.WriteString("\n")
+	}
+	title := "Yesterday"
+	if from.Weekday() != time.Now().AddDate(0, 0, -1).Weekday() {
+		title = "Since " + from.Format("Monday")
+	}
+	section(title, done)
+	section("Today", planned)
+	section("Blocked", blocked)
+
+	_, err = fmt.Print(strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// basket rpc speaks JSON-RPC 2.0 over stdin and stdout, one message per
+// line, so editor plugins can show and edit boards without the TUI.
+//
+// Methods take a "board" param of "global", "local" or a board's path,
+// defaulting to the board basket would open:
+//
+//	list      {board, query, all}              -> {tasks, columns}
+//	add       {board, title, description, priority, project, due} -> task
+//	update    {board, id, title, description, priority, project, assignee, due} -> task
+//	complete  {board, id, done}                -> task
+//	subscribe {board, diff}                    -> {board, seq, tasks, columns}
+//	unsubscribe {board}                        -> {board}
+//	version   {}                               -> {protocol, methods}
+//
+// After subscribe, a notification is sent whenever the board changes, by
+// basket or anyone else: "changed" with the whole board, or with diff set
+// "diff" with {board, seq, changes} listing only the tasks put or deleted,
+// and the columns when they changed. seq counts the diffs, so a client
+// that sees a gap subscribes again for a fresh copy.
+
+// rpcProtocol is bumped when a change to the protocol breaks clients
+const rpcProtocol = 1
+
+var rpcMethods = []string{"list", "add", "update", "complete", "subscribe", "unsubscribe", "version"}
+
+// rpcPollInterval is how often subscribed boards are checked for changes
+const rpcPollInterval = time.Second
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// JSON-RPC's error codes
+const (
+	rpcParseError     = -32700
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcServerError    = -32000
+)
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcTaskParams are the fields add and update take; a field left out is
+// left alone
+type rpcTaskParams struct {
+	Board       string  `json:"board"`
+	ID          string  `json:"id"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Priority    *string `json:"priority"` // a column name
+	Project     *string `json:"project"`
+	Assignee    *string `json:"assignee"`
+	Due         *string `json:"due"` // a date as queries take it, or "none"
+	Done        *bool   `json:"done"`
+}
+
+// rpcBoardState is what a client sees of a board
+type rpcBoardState struct {
+	Board   string   `json:"board"`
+	Tasks   []Task   `json:"tasks"`
+	Columns []string `json:"columns"`
+}
+
+type rpcServer struct {
+	cfg  Config
+	work sync.Mutex // one board operation at a time, as columns are global
+	mu   sync.Mutex // guards out and subs
+	out  *json.Encoder
+
+	subs map[string]*rpcSub // by board path
+}
+
+// rpcSub is a subscription to a board and what the client last saw of it
+type rpcSub struct {
+	diff    bool
+	seq     int
+	tasks   []Task
+	data    []byte // tasks as JSON, for a cheap comparison
+	columns []string
+}
+
+// rpcDiff is the params of a diff notification
+type rpcDiff struct {
+	Board   string     `json:"board"`
+	Seq     int        `json:"seq"`
+	Changes []mutation `json:"changes"`
+	Columns []string   `json:"columns,omitempty"`
+}
+
+func cmdRPC(cfg Config, args []string) error {
+	s := &rpcServer{cfg: cfg, out: json.NewEncoder(os.Stdout), subs: map[string]*rpcSub{}}
+	go s.watch()
+	return s.serve(os.Stdin)
+}
+
+func (s *rpcServer) send(msg rpcResponse) {
+	msg.JSONRPC = "2.0"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.out.Encode(msg); err != nil {
+		logger.Error("rpc write", "err", err)
+	}
+}
+
+func (s *rpcServer) serve(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.send(rpcResponse{ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, err.Error()}})
+			continue
+		}
+		result, err := s.call(req.Method, req.Params)
+		if req.ID == nil {
+			continue // a notification wants no reply
+		}
+		resp := rpcResponse{ID: req.ID, Result: result}
+		if err != nil {
+			var rerr *rpcError
+			if !errors.As(err, &rerr) {
+				rerr = &rpcError{rpcServerError, err.Error()}
+			}
+			resp.Result, resp.Error = nil, rerr
+		}
+		s.send(resp)
+	}
+	return scanner.Err()
+}
+
+func (s *rpcServer) call(method string, raw json.RawMessage) (any, error) {
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	var params rpcTaskParams
+	var other struct {
+		Board string `json:"board"`
+		Query string `json:"query"`
+		All   bool   `json:"all"`
+		Diff  bool   `json:"diff"`
+	}
+	target := any(&params)
+	if method == "list" || method == "subscribe" {
+		target = &other
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, target); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+	}
+
+	switch method {
+	case "version":
+		return map[string]any{"protocol": rpcProtocol, "methods": rpcMethods}, nil
+	case "list":
+		return s.list(other.Board, other.Query, other.All)
+	case "add":
+		return s.edit(params, true)
+	case "update":
+		return s.edit(params, false)
+	case "complete":
+		if params.Done == nil {
+			done := true
+			params.Done = &done
+		}
+		return s.edit(rpcTaskParams{Board: params.Board, ID: params.ID, Done: params.Done}, false)
+	case "subscribe":
+		path := rpcBoardPath(other.Board)
+		state, err := boardStateOf(path)
+		if err != nil {
+			return nil, err
+		}
+		data, _ := json.Marshal(state.Tasks)
+		s.mu.Lock()
+		s.subs[path] = &rpcSub{diff: other.Diff, tasks: state.Tasks, data: data, columns: state.Columns}
+		s.mu.Unlock()
+		return map[string]any{"board": path, "seq": 0, "tasks": state.Tasks, "columns": state.Columns}, nil
+	case "unsubscribe":
+		path := rpcBoardPath(params.Board)
+		s.mu.Lock()
+		delete(s.subs, path)
+		s.mu.Unlock()
+		return map[string]string{"board": path}, nil
+	}
+	return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("no method %q", method)}
+}
+
+// rpcBoardPath resolves a board param
+func rpcBoardPath(board string) string {
+	switch board {
+	case "":
+		return pickBoardPath(false, false)
+	case "global":
+		return getGlobalTasksPath()
+	case "local":
+		path, _ := getLocalTasksPath()
+		return path
+	}
+	return expandHome(board)
+}
+
+// boardStateOf loads the board at path and switches to its columns
+func boardStateOf(path string) (rpcBoardState, error) {
+	board, err := loadBoard(path)
+	if err != nil {
+		return rpcBoardState{}, err
+	}
+	useColumns(board.Columns)
+	state := rpcBoardState{Board: path, Tasks: board.Tasks}
+	for _, p := range allPriorities() {
+		state.Columns = append(state.Columns, p.String())
+	}
+	return state, nil
+}
+
+func (s *rpcServer) list(board, src string, all bool) (any, error) {
+	state, err := boardStateOf(rpcBoardPath(board))
+	if err != nil {
+		return nil, err
+	}
+	query, err := parseQuery(src)
+	if err != nil {
+		return nil, &rpcError{rpcInvalidParams, err.Error()}
+	}
+	now := time.Now()
+	tasks := []Task{}
+	for _, task := range state.Tasks {
+		if (task.Completed && !all && query == nil) || !query.Match(task, state.Tasks, now) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	state.Tasks = tasks
+	return state, nil
+}
+
+// edit adds a task or changes one, and saves the board
+func (s *rpcServer) edit(params rpcTaskParams, add bool) (any, error) {
+	path := rpcBoardPath(params.Board)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return nil, err
+	}
+	useColumns(taskList.Columns)
+	now := time.Now()
+	before := cloneTasks(taskList.Tasks)
+
+	var task *Task
+	if add {
+		if params.Title == nil || strings.TrimSpace(*params.Title) == "" {
+			return nil, &rpcError{rpcInvalidParams, "add needs a title"}
+		}
+		taskList.Tasks = append(taskList.Tasks, Task{ID: generateID(), Priority: defaultPriority(), CreatedAt: now})
+		task = &taskList.Tasks[len(taskList.Tasks)-1]
+	} else {
+		i, ok := findTask(taskList.Tasks, params.ID)
+		if !ok {
+			return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("no single task matches %q", params.ID)}
+		}
+		task = &taskList.Tasks[i]
+	}
+
+	if params.Title != nil {
+		task.Title = strings.TrimSpace(*params.Title)
+	}
+	if params.Description != nil {
+		task.Description = *params.Description
+	}
+	if params.Priority != nil {
+		p, ok := parsePriorityName(*params.Priority)
+		if !ok {
+			return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("no column %q", *params.Priority)}
+		}
+		task.Priority = p
+	}
+	if params.Project != nil {
+		task.Project = *params.Project
+	}
+	if params.Assignee != nil {
+		task.Assignee = *params.Assignee
+	}
+	if params.Due != nil {
+		if strings.EqualFold(*params.Due, "none") || *params.Due == "" {
+			task.Due = nil
+		} else {
+			due, err := queryDay(*params.Due, now)
+			if err != nil {
+				return nil, &rpcError{rpcInvalidParams, err.Error()}
+			}
+			task.Due = &due
+		}
+	}
+	if params.Done != nil {
+		setCompleted(task, *params.Done)
+	}
+	result := *task
+
+	touchTasks(before, taskList.Tasks, now)
+	if err := saveBoard(path, taskList); err != nil {
+		return nil, err
+	}
+	afterSave(s.cfg, path, before, taskList.Tasks)
+	return result, nil
+}
+
+// watch polls the subscribed boards and notifies the client of changes
+func (s *rpcServer) watch() {
+	for range time.Tick(rpcPollInterval) {
+		s.mu.Lock()
+		paths := make([]string, 0, len(s.subs))
+		for path := range s.subs {
+			paths = append(paths, path)
+		}
+		s.mu.Unlock()
+
+		for _, path := range paths {
+			s.work.Lock()
+			state, err := boardStateOf(path)
+			s.work.Unlock()
+			if err != nil {
+				continue
+			}
+			data, _ := json.Marshal(state.Tasks)
+			s.mu.Lock()
+			sub, ok := s.subs[path]
+			if !ok || (bytes.Equal(sub.data, data) && slices.Equal(sub.columns, state.Columns)) {
+				s.mu.Unlock()
+				continue
+			}
+			msg := rpcResponse{Method: "changed", Params: state}
+			if sub.diff {
+				sub.seq++
+				diff := rpcDiff{Board: path, Seq: sub.seq, Changes: diffTasks(sub.tasks, state.Tasks)}
+				if !slices.Equal(sub.columns, state.Columns) {
+					diff.Columns = state.Columns
+				}
+				msg = rpcResponse{Method: "diff", Params: diff}
+			}
+			sub.tasks, sub.data, sub.columns = state.Tasks, data, state.Columns
+			s.mu.Unlock()
+			s.send(msg)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// todoPattern finds TODO and FIXME comments, optionally naming who is on
+// it as TODO(name): ... after any of the usual comment markers
+var todoPattern = regexp.MustCompile(`(?://|#|--|/\*|<!--|;|^\s*\*)\s*(TODO|FIXME)(?:\(([^)]*)\))?:?\s+(\S.*)`)
+
+// maxScanSize is the largest file scan reads; anything bigger is data,
+// not code
+const maxScanSize = 1 << 20
+
+// codeTodo is a TODO comment found in the code
+type codeTodo struct {
+	File     string // relative to the repository root, with slashes
+	Line     int
+	Title    string // e.g. "FIXME: handle the empty case"
+	Assignee string
+}
+
+// ref is where the comment is, as file:line
+func (c codeTodo) ref() string {
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// todoRefPattern finds the file:line a scanned task points at, on the
+// first line of its description
+var todoRefPattern = regexp.MustCompile(`^(\S+):(\d+)$`)
+
+// todoFile is the file a task found by scan points at, if it is one
+func todoFile(task Task) (string, bool) {
+	first, _, _ := strings.Cut(task.Description, "\n")
+	m := todoRefPattern.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil || !strings.HasPrefix(task.Title, "TODO: ") && !strings.HasPrefix(task.Title, "FIXME: ") {
+		return "", false
+	}
+	return m[1], true
+}
+
+// repoFiles lists the files of the repository at root that git tracks or
+// would (untracked but not ignored)
+func repoFiles(root string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files: %w", err)
+	}
+	var files []string
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// scanTodos finds the TODO comments in the files under root, skipping
+// binary and oversized files and basket's own boards
+func scanTodos(root string, files []string) []codeTodo {
+	var todos []codeTodo
+	for _, file := range files {
+		if strings.HasPrefix(filepath.Base(file), ".basket") || strings.HasPrefix(file, ".basket/") {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(file))
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxScanSize {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, maxScanSize)
+		for n := 1; scanner.Scan(); n++ {
+			m := todoPattern.FindStringSubmatch(scanner.Text())
+			if m == nil {
+				continue
+			}
+			text := strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(m[3]), "*/"), "-->"))
+			if text == "" {
+				continue
+			}
+			todos = append(todos, codeTodo{
+				File:     file,
+				Line:     n,
+				Title:    m[1] + ": " + text,
+				Assignee: strings.TrimSpace(m[2]),
+			})
+		}
+	}
+	return todos
+}
+
+// syncTodos brings the scanned tasks among tasks in line with todos. A
+// comment keeps its task while its text stays the same, wherever it
+// moves in its file (or to another file, as long as it is the only one
+// with that text). New comments get a task in column p, and tasks whose
+// comment is gone are completed. It returns what it did, for printing.
+func syncTodos(tasks []Task, todos []codeTodo, p Priority, now time.Time) ([]Task, []string) {
+	var changes []string
+	matched := make([]bool, len(todos))
+	claimed := map[int]bool{}
+
+	// First in the same file, then anywhere
+	match := func(i int, sameFile bool) {
+		file, _ := todoFile(tasks[i])
+		for j, todo := range todos {
+			if !matched[j] && todo.Title == tasks[i].Title && (!sameFile || todo.File == file) {
+				matched[j], claimed[i] = true, true
+				desc := todo.ref()
+				if _, rest, ok := strings.Cut(tasks[i].Description, "\n"); ok {
+					desc += "\n" + rest
+				}
+				if desc != tasks[i].Description {
+					tasks[i].Description = desc
+					changes = append(changes, "moved    "+todo.ref()+"  "+todo.Title)
+				}
+				return
+			}
+		}
+	}
+	for _, sameFile := range []bool{true, false} {
+		for i := range tasks {
+			if _, ok := todoFile(tasks[i]); ok && !claimed[i] {
+				match(i, sameFile)
+			}
+		}
+	}
+
+	for i := range tasks {
+		if _, ok := todoFile(tasks[i]); ok && !claimed[i] && !tasks[i].Completed {
+			setCompleted(&tasks[i], true)
+			changes = append(changes, "done     "+tasks[i].Title)
+		}
+	}
+	for j, todo := range todos {
+		if matched[j] {
+			continue
+		}
+		tasks = append(tasks, Task{
+			ID:          generateID(),
+			Title:       todo.Title,
+			Description: todo.ref(),
+			Priority:    p,
+			Assignee:    todo.Assignee,
+			CreatedAt:   now,
+		})
+		changes = append(changes, "added    "+todo.ref()+"  "+todo.Title)
+	}
+	return tasks, changes
+}
+
+func cmdScan(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "show what would change without touching the board")
+	fs.Parse(args)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, ok := repoRoot(cwd)
+	if !ok {
+		return fmt.Errorf("scan works in a git repository")
+	}
+	files, err := repoFiles(root)
+	if err != nil {
+		return err
+	}
+	todos := scanTodos(root, files)
+
+	path := resolveBoardPath(false, true)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	before := cloneTasks(taskList.Tasks)
+	now := time.Now()
+	var changes []string
+	taskList.Tasks, changes = syncTodos(taskList.Tasks, todos, settingsOf(taskList).defaultColumn(), now)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("%d comment(s) in %d file(s), %s is up to date\n", len(todos), len(files), path)
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("would make %d change(s) to %s\n", len(changes), path)
+		return nil
+	}
+	touchTasks(before, taskList.Tasks, now)
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("%d comment(s) in %d file(s), made %d change(s) to %s\n", len(todos), len(files), len(changes), path)
+	return nil
+}
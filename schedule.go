@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleRule creates a routine task whenever its schedule comes round
+type ScheduleRule struct {
+	// Every is a cron expression ("0 9 * * 1") or one of the shorthands
+	// "daily 9:00", "weekdays 9:00", "monday,thursday 9:00" and
+	// "monthly 1 9:00"
+	Every       string `json:"every"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"` // level name; the middle level when empty
+	Project     string `json:"project"`
+	Board       string `json:"board"` // "global" (the default) or "local"
+}
+
+// cronSpec is a parsed five-field cron expression; each field holds the
+// values it allows
+type cronSpec struct {
+	minute, hour, dom, month, dow map[int]bool
+	anyDom, anyDow                bool
+}
+
+var weekdayNames = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// parseSchedule parses a rule's Every, translating the shorthands into
+// cron expressions first
+func parseSchedule(every string) (cronSpec, error) {
+	fields := strings.Fields(strings.ToLower(every))
+	if len(fields) == 5 {
+		return parseCron(fields)
+	}
+	if len(fields) < 2 {
+		return cronSpec{}, fmt.Errorf("invalid schedule %q", every)
+	}
+
+	clock := fields[len(fields)-1]
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return cronSpec{}, fmt.Errorf("invalid time %q in schedule %q", clock, every)
+	}
+	var dom, dow string
+	switch fields[0] {
+	case "daily":
+		dom, dow = "*", "*"
+	case "weekdays":
+		dom, dow = "*", "1-5"
+	case "monthly":
+		if len(fields) != 3 {
+			return cronSpec{}, fmt.Errorf("invalid schedule %q, want \"monthly <day> <hh:mm>\"", every)
+		}
+		dom, dow = fields[1], "*"
+	default:
+		var days []string
+		for _, name := range strings.Split(fields[0], ",") {
+			found := false
+			for i, day := range weekdayNames {
+				if name == day || (len(name) >= 3 && strings.HasPrefix(day, name)) {
+					days = append(days, strconv.Itoa(i))
+					found = true
+				}
+			}
+			if !found {
+				return cronSpec{}, fmt.Errorf("unknown day %q in schedule %q", name, every)
+			}
+		}
+		dom, dow = "*", strings.Join(days, ",")
+	}
+	return parseCron([]string{strconv.Itoa(t.Minute()), strconv.Itoa(t.Hour()), dom, "*", dow})
+}
+
+// parseCron parses minute, hour, day of month, month and day of week.
+// Each field is *, a number, a range a-b, a list, or any of those with
+// a /step.
+func parseCron(fields []string) (cronSpec, error) {
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]map[int]bool
+	for i, field := range fields {
+		set := map[int]bool{}
+		for _, part := range strings.Split(field, ",") {
+			rng, stepStr, hasStep := strings.Cut(part, "/")
+			step := 1
+			if hasStep {
+				n, err := strconv.Atoi(stepStr)
+				if err != nil || n < 1 {
+					return cronSpec{}, fmt.Errorf("invalid step in %q", field)
+				}
+				step = n
+			}
+			lo, hi := bounds[i][0], bounds[i][1]
+			if rng != "*" {
+				a, b, isRange := strings.Cut(rng, "-")
+				var err error
+				if lo, err = strconv.Atoi(a); err != nil {
+					return cronSpec{}, fmt.Errorf("invalid cron field %q", field)
+				}
+				hi = lo
+				if isRange {
+					if hi, err = strconv.Atoi(b); err != nil {
+						return cronSpec{}, fmt.Errorf("invalid cron field %q", field)
+					}
+				} else if hasStep {
+					hi = bounds[i][1]
+				}
+			}
+			if lo < bounds[i][0] || hi > bounds[i][1] || lo > hi {
+				return cronSpec{}, fmt.Errorf("cron field %q is out of range", field)
+			}
+			for v := lo; v <= hi; v += step {
+				set[v] = true
+			}
+		}
+		sets[i] = set
+	}
+	// Sunday is both 0 and 7
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return cronSpec{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		anyDom: fields[2] == "*", anyDow: fields[4] == "*",
+	}, nil
+}
+
+// matchesDay applies cron's rule that when both the day of month and the
+// day of week are restricted, either one matching is enough
+func (c cronSpec) matchesDay(t time.Time) bool {
+	if !c.month[int(t.Month())] {
+		return false
+	}
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.anyDom && c.anyDow:
+		return true
+	case c.anyDom:
+		return dow
+	case c.anyDow:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// lastBefore is the latest time the schedule fired at or before now,
+// looking back at most a year
+func (c cronSpec) lastBefore(now time.Time) (time.Time, bool) {
+	day := startOfDay(now)
+	for i := 0; i <= 366; i++ {
+		if c.matchesDay(day) {
+			for h := 23; h >= 0; h-- {
+				if !c.hour[h] {
+					continue
+				}
+				for m := 59; m >= 0; m-- {
+					t := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location())
+					if c.minute[m] && !t.After(now) {
+						return t, true
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}, false
+}
+
+func getSchedulesStatePath() string {
+	return filepath.Join(getDataDir(), "schedules.json")
+}
+
+// scheduleKey identifies a rule on one board in the state file
+func scheduleKey(path string, rule ScheduleRule) string {
+	return path + "\x00" + rule.Every + "\x00" + rule.Title
+}
+
+// runSchedules creates the tasks whose rules came round since they last
+// ran. A rule that has never run starts from its latest occurrence, and
+// no task is created while an open one with the same title exists.
+// It reports whether the board changed.
+func runSchedules(path string, taskList *TaskList, rules []ScheduleRule, board string, now time.Time) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	state := map[string]time.Time{}
+	if data, err := os.ReadFile(getSchedulesStatePath()); err == nil {
+		json.Unmarshal(data, &state)
+	}
+
+	changed, stateChanged := false, false
+	for _, rule := range rules {
+		target := rule.Board
+		if target == "" {
+			target = "global"
+		}
+		if target != board || rule.Title == "" {
+			continue
+		}
+		spec, err := parseSchedule(rule.Every)
+		if err != nil {
+			logger.Error("schedule", "rule", rule.Title, "err", err)
+			continue
+		}
+		fired, ok := spec.lastBefore(now)
+		key := scheduleKey(path, rule)
+		if !ok || !fired.After(state[key]) {
+			continue
+		}
+		state[key] = fired
+		stateChanged = true
+
+		open := false
+		for _, task := range taskList.Tasks {
+			if !task.Completed && strings.EqualFold(task.Title, rule.Title) {
+				open = true
+			}
+		}
+		if open {
+			continue
+		}
+		p, ok := parsePriorityName(rule.Priority)
+		if !ok {
+			p = defaultPriority()
+		}
+		taskList.Tasks = append(taskList.Tasks, Task{
+			ID:          generateID(),
+			Title:       rule.Title,
+			Description: rule.Description,
+			Priority:    p,
+			Project:     rule.Project,
+			CreatedAt:   now,
+		})
+		logger.Info("schedule", "rule", rule.Title, "fired", fired)
+		changed = true
+	}
+
+	if stateChanged {
+		if data, err := json.MarshalIndent(state, "", "  "); err == nil {
+			os.MkdirAll(getDataDir(), 0755)
+			os.WriteFile(getSchedulesStatePath(), data, 0644)
+		}
+	}
+	return changed
+}
+
+// autoSchedule applies the schedules setting to a freshly loaded board
+// and persists the result
+func autoSchedule(path string, taskList *TaskList, rules []ScheduleRule, board string) {
+	if runSchedules(path, taskList, rules, board, time.Now()) {
+		saveBoard(path, *taskList)
+	}
+}
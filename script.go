@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// scriptMaxSteps bounds every script call so a runaway loop cannot hang
+// the UI
+const scriptMaxSteps = 1_000_000
+
+// scriptFilter is a named predicate registered with basket.filter
+type scriptFilter struct {
+	Name string
+	Fn   starlark.Callable
+}
+
+// scriptCommand is a key binding registered with basket.command
+type scriptCommand struct {
+	Name string
+	Fn   starlark.Callable
+}
+
+// scriptEngine holds what the user script registered at startup
+type scriptEngine struct {
+	thread     *starlark.Thread
+	filters    []scriptFilter
+	decorators []starlark.Callable
+	commands   map[string]scriptCommand
+	keys       []string // command keys in registration order
+}
+
+func getScriptPath() string {
+	return filepath.Join(getConfigDir(), "basket.star")
+}
+
+// loadScript runs the user's Starlark script, if there is one. The script
+// extends basket through the predeclared basket module:
+//
+//	basket.filter(name, fn)        fn(task) -> bool, cycled with f
+//	basket.decorate(fn)            fn(task) -> str shown on the card
+//	basket.command(key, name, fn)  fn(task) -> dict of fields to update
+func loadScript(path string) (*scriptEngine, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	e := &scriptEngine{
+		thread: &starlark.Thread{
+			Name: "basket",
+			// The terminal belongs to the board, so print goes to the
+			// debug log
+			Print: func(_ *starlark.Thread, msg string) {
+				logger.Info("script print", "msg", msg)
+			},
+		},
+		commands: map[string]scriptCommand{},
+	}
+
+	module := &starlarkstruct.Module{
+		Name: "basket",
+		Members: starlark.StringDict{
+			"filter":   starlark.NewBuiltin("filter", e.builtinFilter),
+			"decorate": starlark.NewBuiltin("decorate", e.builtinDecorate),
+			"command":  starlark.NewBuiltin("command", e.builtinCommand),
+		},
+	}
+
+	_, err := starlark.ExecFileOptions(&syntax.FileOptions{}, e.thread, path, nil, starlark.StringDict{"basket": module})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return e, nil
+}
+
+func (e *scriptEngine) builtinFilter(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "fn", &fn); err != nil {
+		return nil, err
+	}
+	e.filters = append(e.filters, scriptFilter{Name: name, Fn: fn})
+	return starlark.None, nil
+}
+
+func (e *scriptEngine) builtinDecorate(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "fn", &fn); err != nil {
+		return nil, err
+	}
+	e.decorators = append(e.decorators, fn)
+	return starlark.None, nil
+}
+
+func (e *scriptEngine) builtinCommand(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key, name string
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "key", &key, "name", &name, "fn", &fn); err != nil {
+		return nil, err
+	}
+	if _, ok := e.commands[key]; !ok {
+		e.keys = append(e.keys, key)
+	}
+	e.commands[key] = scriptCommand{Name: name, Fn: fn}
+	return starlark.None, nil
+}
+
+// dropTakenKeys drops the commands bound to keys the board already
+// answers to, which would never run, and returns their keys
+func (e *scriptEngine) dropTakenKeys(taken []key.Binding) []string {
+	used := map[string]bool{}
+	for _, b := range taken {
+		for _, k := range b.Keys() {
+			used[k] = true
+		}
+	}
+	var dropped []string
+	kept := e.keys[:0]
+	for _, k := range e.keys {
+		if used[k] {
+			dropped = append(dropped, k)
+			delete(e.commands, k)
+			continue
+		}
+		kept = append(kept, k)
+	}
+	e.keys = kept
+	return dropped
+}
+
+// call invokes fn with the task, with a fresh step budget
+func (e *scriptEngine) call(fn starlark.Callable, task Task) (starlark.Value, error) {
+	e.thread.Uncancel()
+	e.thread.SetMaxExecutionSteps(e.thread.ExecutionSteps() + scriptMaxSteps)
+	return starlark.Call(e.thread, fn, starlark.Tuple{taskValue(task)}, nil)
+}
+
+// taskValue exposes a task to scripts as a dict
+func taskValue(task Task) *starlark.Dict {
+	d := starlark.NewDict(8)
+	d.SetKey(starlark.String("id"), starlark.String(task.ID))
+	d.SetKey(starlark.String("title"), starlark.String(task.Title))
+	d.SetKey(starlark.String("description"), starlark.String(task.Description))
+	d.SetKey(starlark.String("completed"), starlark.Bool(task.Completed))
+	d.SetKey(starlark.String("priority"), starlark.MakeInt(int(task.Priority)))
+	d.SetKey(starlark.String("priority_name"), starlark.String(task.Priority.String()))
+	d.SetKey(starlark.String("created_at"), starlark.String(task.CreatedAt.Format(time.RFC3339)))
+	d.SetKey(starlark.String("age_days"), starlark.Float(time.Since(task.CreatedAt).Hours()/24))
+	return d
+}
+
+// match reports whether task passes the filter at index i
+func (e *scriptEngine) match(i int, task Task) (bool, error) {
+	v, err := e.call(e.filters[i].Fn, task)
+	if err != nil {
+		return false, err
+	}
+	return bool(v.Truth()), nil
+}
+
+// decorate returns the combined decorations for task
+func (e *scriptEngine) decorate(task Task) (string, error) {
+	var parts []string
+	for _, fn := range e.decorators {
+		v, err := e.call(fn, task)
+		if err != nil {
+			return "", err
+		}
+		if s, ok := starlark.AsString(v); ok && s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// runCommand runs the command bound to key on task and applies the fields
+// it returns
+func (e *scriptEngine) runCommand(key string, task Task) (Task, error) {
+	v, err := e.call(e.commands[key].Fn, task)
+	if err != nil {
+		return task, err
+	}
+	if v == starlark.None {
+		return task, nil
+	}
+	fields, ok := v.(*starlark.Dict)
+	if !ok {
+		return task, fmt.Errorf("command %q must return a dict or None, got %s", e.commands[key].Name, v.Type())
+	}
+
+	for _, item := range fields.Items() {
+		name, _ := starlark.AsString(item[0])
+		switch name {
+		case "title", "description":
+			s, ok := starlark.AsString(item[1])
+			if !ok {
+				return task, fmt.Errorf("%s must be a string", name)
+			}
+			if name == "title" {
+				task.Title = s
+			} else {
+				task.Description = s
+			}
+		case "completed":
+			setCompleted(&task, bool(item[1].Truth()))
+		case "priority":
+			p, err := starlark.AsInt32(item[1])
+			if err != nil {
+				return task, fmt.Errorf("priority must be an int")
+			}
+			if p < 0 || p > int(maxPriority()) {
+				return task, fmt.Errorf("priority %d out of range", p)
+			}
+			task.Priority = Priority(p)
+		default:
+			return task, fmt.Errorf("command %q returned unknown field %q", e.commands[key].Name, name)
+		}
+	}
+	return task, nil
+}
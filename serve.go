@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cmdServe runs basket as a small HTTP server. It serves /metrics, the
+// boards' numbers in Prometheus' text format, and /feed, an Atom feed of
+// the tasks completed last.
+func cmdServe(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:9390", "address to listen on")
+	fs.Parse(args)
+
+	var mu sync.Mutex // columns are global, so one scrape at a time
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, metricBoards(), time.Now())
+	})
+	mux.HandleFunc("GET /feed", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		boards := metricBoards()
+		if name := r.URL.Query().Get("board"); name != "" {
+			var ok bool
+			if boards, ok = feedBoard(boards, name); !ok {
+				http.Error(w, fmt.Sprintf("no board %q", name), http.StatusNotFound)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		if err := writeFeed(w, boards, "http://"+r.Host+r.URL.RequestURI(), cfg, time.Now()); err != nil {
+			logger.Error("feed", "err", err)
+		}
+	})
+
+	fmt.Fprintf(os.Stderr, "serving metrics on http://%s/metrics and a feed on http://%s/feed\n", *addr, *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+// metricBoards are the boards to report on, by label: the global board
+// and every local board basket has opened that still exists
+func metricBoards() map[string]string {
+	boards := map[string]string{"global": getGlobalTasksPath()}
+	for _, path := range knownBoards(loadBoardState()) {
+		if _, err := os.Stat(path); err == nil {
+			boards[path] = path
+		}
+	}
+	return boards
+}
+
+// writeMetrics writes the gauges and counters of each board
+func writeMetrics(w io.Writer, boards map[string]string, now time.Time) {
+	type sample struct {
+		labels string
+		value  int
+	}
+	var open, overdue, completed []sample
+	for _, label := range slices.Sorted(maps.Keys(boards)) {
+		board, err := loadBoard(boards[label])
+		if err != nil {
+			logger.Error("metrics", "board", boards[label], "err", err)
+			continue
+		}
+		useColumns(board.Columns)
+		perLevel := make([]int, numPriorities())
+		late, done := 0, 0
+		for _, task := range board.Tasks {
+			switch {
+			case task.Completed:
+				done++
+			case !offBoard(task):
+				perLevel[clampPriority(task.Priority)]++
+				if isOverdue(task, now) {
+					late++
+				}
+			}
+		}
+		for _, task := range board.Archive {
+			if task.Completed {
+				done++
+			}
+		}
+		b := metricLabel(label)
+		for _, p := range allPriorities() {
+			open = append(open, sample{fmt.Sprintf(`board="%s",priority="%s"`, b, metricLabel(strings.ToLower(p.String()))), perLevel[p]})
+		}
+		overdue = append(overdue, sample{fmt.Sprintf(`board="%s"`, b), late})
+		completed = append(completed, sample{fmt.Sprintf(`board="%s"`, b), done})
+	}
+
+	families := []struct {
+		name, kind, help string
+		samples          []sample
+	}{
+		{"basket_open_tasks", "gauge", "Open tasks on the board, by priority.", open},
+		{"basket_overdue_tasks", "gauge", "Open tasks past their due date.", overdue},
+		{"basket_completed_tasks", "gauge", "Completed tasks, archived ones included.", completed},
+	}
+	for _, f := range families {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind)
+		for _, s := range f.samples {
+			fmt.Fprintf(w, "%s{%s} %d\n", f.name, s.labels, s.value)
+		}
+	}
+}
+
+// metricLabel escapes a label value
+func metricLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BoardSettings are preferences kept in the board file itself, so they
+// travel with a board committed to a repository. Unset fields fall back
+// to the config. Columns are named as on the board, or numbered from 1.
+type BoardSettings struct {
+	DefaultColumn string          `json:"default_column,omitempty"` // where the board opens and tasks are added
+	Sort          string          `json:"sort,omitempty"`           // "rank" or "urgency"
+	SinkCompleted *bool           `json:"sink_completed,omitempty"`
+	HideCompleted *bool           `json:"hide_completed,omitempty"`
+	HiddenColumns []string        `json:"hidden_columns,omitempty"`
+	WIPLimits     map[string]int  `json:"wip_limits,omitempty"` // most open tasks a column should hold
+	GitLab        *GitLabSettings `json:"gitlab,omitempty"`     // issues basket gitlab sync mirrors
+}
+
+// settingsOf is a board's settings, empty when it has none
+func settingsOf(board TaskList) BoardSettings {
+	if board.Settings == nil {
+		return BoardSettings{}
+	}
+	return *board.Settings
+}
+
+// findColumn is the column called name, or numbered so from 1
+func findColumn(name string) (Priority, bool) {
+	if p, ok := parsePriorityName(name); ok {
+		return p, true
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(name)); err == nil && n >= 1 && n <= numPriorities() {
+		return Priority(n - 1), true
+	}
+	return 0, false
+}
+
+// defaultColumn is the column the board opens on and tasks are added to
+func (s BoardSettings) defaultColumn() Priority {
+	if p, ok := findColumn(s.DefaultColumn); ok && !s.hidden(p) {
+		return p
+	}
+	return defaultPriority()
+}
+
+// hidden reports whether column p is left off the board
+func (s BoardSettings) hidden(p Priority) bool {
+	return slices.ContainsFunc(s.HiddenColumns, func(name string) bool {
+		q, ok := findColumn(name)
+		return ok && q == p
+	})
+}
+
+// wipLimit is the most open tasks column p should hold, 0 for no limit
+func (s BoardSettings) wipLimit(p Priority) int {
+	for name, limit := range s.WIPLimits {
+		if q, ok := findColumn(name); ok && q == p {
+			return limit
+		}
+	}
+	return 0
+}
+
+// boardSettings are the settings of the board on screen
+func (m model) boardSettings() BoardSettings {
+	if m.showingLocal {
+		return settingsOf(m.localBoard)
+	}
+	return settingsOf(m.globalBoard)
+}
+
+// shownColumns are the columns on screen, left to right: all of them but
+// the hidden ones, or all of them if every one is hidden
+func (m model) shownColumns() []Priority {
+	settings := m.boardSettings()
+	var shown []Priority
+	for _, p := range allPriorities() {
+		if !settings.hidden(p) {
+			shown = append(shown, p)
+		}
+	}
+	if len(shown) == 0 {
+		return allPriorities()
+	}
+	return shown
+}
+
+// shownIndex is where column p is among the shown columns, or the
+// nearest shown column to its left when p is hidden
+func (m model) shownIndex(p Priority) int {
+	index := 0
+	for i, q := range m.shownColumns() {
+		if q <= p {
+			index = i
+		}
+	}
+	return index
+}
+
+// applyBoardSettings starts the board on screen as its settings say,
+// falling back to the config
+func (m *model) applyBoardSettings() {
+	settings := m.boardSettings()
+	m.selectedCol = int(settings.defaultColumn())
+	m.sinkCompleted = m.config.SinkCompleted
+	if settings.SinkCompleted != nil {
+		m.sinkCompleted = *settings.SinkCompleted
+	}
+	m.hideCompleted = m.config.HideCompleted
+	if settings.HideCompleted != nil {
+		m.hideCompleted = *settings.HideCompleted
+	}
+	switch settings.Sort {
+	case "urgency":
+		m.sortUrgency = true
+	case "rank":
+		m.sortUrgency = false
+	default:
+		m.sortUrgency = m.config.SortByUrgency
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// shareText renders a task as a Markdown snippet that also reads well as
+// plain text, for pasting into chat or email
+func shareText(task Task) string {
+	var b strings.Builder
+
+	state := "☐"
+	if task.Completed {
+		state = "☑"
+	}
+	fmt.Fprintf(&b, "%s **%s**\n", state, task.Title)
+	fmt.Fprintf(&b, "Priority: %s · #%s\n", task.Priority, shortID(task.ID))
+	if task.Due != nil {
+		fmt.Fprintf(&b, "Due: %s\n", formatDue(*task.Due, time.Now()))
+	}
+	if task.Project != "" {
+		fmt.Fprintf(&b, "Project: %s\n", task.Project)
+	}
+	if task.Assignee != "" {
+		fmt.Fprintf(&b, "Assignee: %s\n", task.Assignee)
+	}
+	if desc := strings.TrimSpace(task.Description); desc != "" {
+		b.WriteString("\n" + desc + "\n")
+	}
+	if len(task.Comments) > 0 {
+		b.WriteString("\n")
+		for _, c := range task.Comments {
+			fmt.Fprintf(&b, "- %s: %s\n", c.At.Local().Format(commentTimeFormat), c.Text)
+		}
+	}
+	return b.String()
+}
+
+// shareSelected copies the selected task to the clipboard
+func (m *model) shareSelected() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	if err := clipboard.WriteAll(shareText(tasksInCol[m.selectedTask])); err != nil {
+		m.fail(fmt.Errorf(T("status.share_failed"), err))
+		return
+	}
+	m.notify(T("status.shared"))
+}
+
+func cmdShare(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("share", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	copyIt := fs.Bool("copy", false, "copy to the clipboard instead of printing")
+	refs := parseInterspersed(fs, args)
+	if len(refs) != 1 {
+		return fmt.Errorf("usage: basket share <id> [--copy]")
+	}
+
+	tasks, err := loadTasks(resolveBoardPath(*global, *local))
+	if err != nil {
+		return err
+	}
+	i, ok := findTask(tasks, refs[0])
+	if !ok {
+		return fmt.Errorf("no single task matches %q", refs[0])
+	}
+
+	text := shareText(tasks[i])
+	if *copyIt {
+		return clipboard.WriteAll(text)
+	}
+	fmt.Print(text)
+	return nil
+}
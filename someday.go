@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// somedayTasks lists the ideas parked off the board, in creation order
+func (m model) somedayTasks() []Task {
+	var tasks []Task
+	for _, task := range m.tasks {
+		if task.Someday {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// deferSelected parks the selected task in someday/maybe
+func (m *model) deferSelected() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == tasksInCol[m.selectedTask].ID {
+			m.tasks[i].Someday = true
+			m.commit(T("status.deferred"))
+			m.clampSelection()
+			return
+		}
+	}
+}
+
+func (m *model) openSomeday() {
+	m.mode = ViewSomeday
+	m.somedayCursor = 0
+}
+
+// promoteSomeday moves the idea under the cursor onto the board at p
+func (m *model) promoteSomeday(p Priority) {
+	items := m.somedayTasks()
+	if m.somedayCursor >= len(items) {
+		return
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID == items[m.somedayCursor].ID {
+			m.tasks[i].Someday = false
+			m.tasks[i].Priority = p
+			m.commit(fmt.Sprintf(T("status.promoted"), p))
+			m.selectTask(m.tasks[i].ID)
+			m.mode = ViewBoard
+			return
+		}
+	}
+}
+
+func (m model) updateSomeday(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.somedayTasks()
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Quit):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Up):
+		if m.somedayCursor > 0 {
+			m.somedayCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.somedayCursor < len(items)-1 {
+			m.somedayCursor++
+		}
+
+	case key.Matches(msg, m.keys.Promote):
+		// Columns are numbered from the left, starting at 1
+		p := Priority(msg.String()[0] - '1')
+		if p <= maxPriority() {
+			m.promoteSomeday(p)
+		}
+
+	case key.Matches(msg, m.keys.New):
+		m.mode = ViewAdd
+		m.addingSomeday = true
+		m.addingHabit = false
+		m.textarea.Reset()
+		m.textarea.Placeholder = T("add.placeholder")
+		m.textarea.SetHeight(3)
+		return m, m.textarea.Focus()
+
+	case key.Matches(msg, m.keys.Delete):
+		if m.somedayCursor >= len(items) {
+			break
+		}
+		for i := range m.tasks {
+			if m.tasks[i].ID == items[m.somedayCursor].ID {
+				m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
+				m.commit(T("status.deleted"))
+				break
+			}
+		}
+		if m.somedayCursor > 0 && m.somedayCursor >= len(items)-1 {
+			m.somedayCursor--
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) viewSomeday() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(T("someday.title")) + "\n")
+
+	items := m.somedayTasks()
+	if len(items) == 0 {
+		b.WriteString(helpStyle.Render(T("someday.empty")) + "\n")
+	}
+	for i, task := range items {
+		line := truncate(task.Title, 60)
+		if i == m.somedayCursor {
+			line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+
+	// Show which digit promotes to which column
+	var cols []string
+	for _, p := range allPriorities() {
+		if p > 8 {
+			break
+		}
+		cols = append(cols, lipgloss.NewStyle().Foreground(p.Color()).Render(fmt.Sprintf("%d %s", p+1, p)))
+	}
+	b.WriteString("\n" + helpStyle.Render(T("someday.promote")) + " " + strings.Join(cols, "  ") + "\n")
+
+	b.WriteString("\n" + m.renderFooter())
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// startupOptions say where the board opens, from the flags of plain
+// basket, as in
+//
+//	basket --board api --column high --view today
+type startupOptions struct {
+	Workspace
+	Name   string // of a saved workspace
+	Column string
+}
+
+func parseStartupFlags(cfg Config, args []string) (startupOptions, error) {
+	var opts startupOptions
+	fs := flag.NewFlagSet("basket", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	board := fs.String("board", "", "open a local board by name or path")
+	fs.StringVar(&opts.Column, "column", "", "select a column by name")
+	query := fs.String("query", "", "filter the board with a query")
+	view := fs.String("view", "", strings.Join(workspaceLayouts, ", "))
+	fs.StringVar(&opts.Name, "workspace", "", "start from a saved workspace")
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected %q, commands come before flags", fs.Arg(0))
+	}
+
+	if opts.Name != "" {
+		ws, ok := cfg.Workspaces[opts.Name]
+		if !ok {
+			return opts, fmt.Errorf("no workspace %q (see basket workspace)", opts.Name)
+		}
+		opts.Workspace = ws
+	}
+	switch {
+	case *board != "":
+		path, err := findBoardByName(*board)
+		if err != nil {
+			return opts, err
+		}
+		opts.Board = path
+	case *global:
+		opts.Board = "global"
+	case *local:
+		opts.Board = "local"
+	}
+	if *query != "" {
+		opts.Query = *query
+	}
+	if *view != "" {
+		opts.Layout = *view
+	}
+	return opts, nil
+}
+
+// findBoardByName resolves a board given by path, or by the directory it
+// is in: "api" finds services/api/.basket.json among the boards of the
+// repository and the boards basket has opened before
+func findBoardByName(name string) (string, error) {
+	if info, err := os.Stat(name); err == nil {
+		path := name
+		if info.IsDir() && filepath.Base(name) != ".basket" {
+			path = findBoard(filepath.Join(name, ".basket"))
+		}
+		if _, err := os.Stat(path); err == nil {
+			return filepath.Abs(path)
+		}
+	}
+	var candidates []string
+	for _, choice := range pickerBoards() {
+		candidates = append(candidates, choice.Path)
+	}
+
+	var matches []string
+	for _, path := range candidates {
+		dir := filepath.Dir(path)
+		if filepath.Base(dir) == name || strings.HasSuffix(dir, string(filepath.Separator)+name) {
+			matches = append(matches, path)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no board called %q (see basket boards)", name)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%q could be any of\n  %s", name, strings.Join(matches, "\n  "))
+}
+
+// applyStartup opens the board where opts say
+func (m *model) applyStartup(opts startupOptions) error {
+	if err := m.applyWorkspace(opts.Workspace); err != nil {
+		return err
+	}
+	m.workspace = opts.Name
+	if opts.Column == "" {
+		return nil
+	}
+	p, ok := parsePriorityName(opts.Column)
+	if !ok {
+		var names []string
+		for _, p := range allPriorities() {
+			names = append(names, strings.ToLower(p.String()))
+		}
+		return fmt.Errorf("no column %q, use one of %s", opts.Column, strings.Join(names, ", "))
+	}
+	m.selectedCol = int(p)
+	m.selectedTask = 0
+	m.scrollOffset = 0
+	m.updateHorizontalScroll()
+	return nil
+}
+
+// cmdOpen starts the board with a task selected and shown in full, for
+// IDs copied from basket list or a notification
+func cmdOpen(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	refs := parseInterspersed(fs, args)
+	if len(refs) != 1 {
+		return fmt.Errorf("usage: basket open <id> [--global|--local]")
+	}
+
+	m := initialModel(cfg)
+	if m.mode == ViewCorrupt {
+		return runTUI(m)
+	}
+	var boards []bool // local or not, in the order to search them
+	switch {
+	case *global:
+		boards = []bool{false}
+	case *local:
+		boards = []bool{true}
+	case m.hasLocal:
+		boards = []bool{true, false}
+	default:
+		boards = []bool{false}
+	}
+	for _, onLocal := range boards {
+		tasks := m.globalBoard.Tasks
+		if onLocal {
+			tasks = m.localBoard.Tasks
+		}
+		i, ok := findTask(tasks, refs[0])
+		if !ok {
+			continue
+		}
+		if onLocal != m.showingLocal {
+			m.showBoard(onLocal)
+		}
+		m.selectTask(tasks[i].ID)
+		m.openTask(tasks[i].ID)
+		return runTUI(m)
+	}
+	return fmt.Errorf("no single task matches %q", refs[0])
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusTimeout is how long a confirmation stays in the status bar
+const statusTimeout = 3 * time.Second
+
+// statusClearMsg clears the status bar, unless a newer message took over
+type statusClearMsg struct {
+	seq int
+}
+
+// notify shows a confirmation that clears itself after statusTimeout
+func (m *model) notify(msg string) {
+	if msg == "" {
+		return
+	}
+	m.status = msg
+	m.statusErr = false
+	m.statusSeq++
+	m.statusTimer = true
+}
+
+// fail shows an error that stays until another message replaces it or it
+// is dismissed
+func (m *model) fail(err error) {
+	logger.Error("status", "err", err)
+	m.status = err.Error()
+	m.statusErr = true
+	m.statusSeq++
+	m.statusTimer = false
+}
+
+// statusCmd starts the clear timer for a new confirmation
+func (m *model) statusCmd() tea.Cmd {
+	if !m.statusTimer {
+		return nil
+	}
+	m.statusTimer = false
+	seq := m.statusSeq
+	return tea.Tick(statusTimeout, func(time.Time) tea.Msg { return statusClearMsg{seq: seq} })
+}
+
+// renderStatus is the status bar line, empty when there is nothing to say
+func (m model) renderStatus() string {
+	if m.status == "" {
+		return ""
+	}
+	if m.statusErr {
+		return lipgloss.NewStyle().
+			Bold(true).
+			Foreground(colorError).
+			Render(fmt.Sprintf("✗ %s", m.status))
+	}
+	return lipgloss.NewStyle().
+		Foreground(colorSuccess).
+		Render(fmt.Sprintf("✓ %s", m.status))
+}
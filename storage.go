@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// boardFormat reads and writes one on-disk representation of a board
+type boardFormat interface {
+	read(path string) (TaskList, error)
+	write(path string, taskList TaskList) error
+}
+
+// formatFor picks the format of the board at path. A directory holds one
+// Markdown file per task, .yaml and .yml files are YAML, and anything else
+// is JSON.
+func formatFor(path string) boardFormat {
+	if isMarkdownBoard(path) {
+		return markdownFormat{}
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return yamlFormat{}
+	}
+	return jsonFormat{}
+}
+
+// boardExts are the file extensions of single-file boards, in the order
+// they are looked for
+var boardExts = []string{".json", ".yaml", ".yml"}
+
+func isMarkdownBoard(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// jsonFormat stores the whole board in one JSON document
+type jsonFormat struct{}
+
+func (jsonFormat) read(path string) (TaskList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TaskList{}, err
+	}
+	if data, err = openBoardData(path, data); err != nil {
+		return TaskList{}, err
+	}
+	var taskList TaskList
+	if err := json.Unmarshal(data, &taskList); err != nil {
+		return TaskList{}, newCorruptBoardError(path, data, err)
+	}
+	return taskList, nil
+}
+
+func (jsonFormat) write(path string, taskList TaskList) error {
+	data, err := json.MarshalIndent(taskList, "", "  ")
+	if err != nil {
+		return err
+	}
+	if data, err = sealBoardData(path, data); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func getGlobalTasksPath() string {
+	base, ok := globalBoardBase()
+	if !ok {
+		return "basket-tasks.json"
+	}
+	return findBoard(base)
+}
+
+// globalBoardBase is where the global board lives, without its format:
+// ~/basket-tasks, or on Windows basket-tasks in %APPDATA%\basket next to
+// the config rather than loose in the profile folder
+func globalBoardBase() (string, bool) {
+	if goos == "windows" {
+		if dir, err := userConfigDir(); err == nil {
+			return filepath.Join(dir, "basket", "basket-tasks"), true
+		}
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, "basket-tasks"), true
+}
+
+// findBoard returns the board stored under base in whichever format
+// exists, falling back to a JSON file that has yet to be created
+func findBoard(base string) string {
+	if isMarkdownBoard(base) {
+		return base
+	}
+	for _, ext := range boardExts {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return base + ".json"
+}
+
+// getLocalTasksPath returns the local board of the current directory and
+// whether it exists. A .basket directory of Markdown tasks takes
+// precedence over .basket.json, which takes precedence over .basket.yaml.
+//
+// Inside a git repository the nearest board between the current directory
+// and the repository root is used, so the whole team finds the same board
+// from any subdirectory; a new board goes in the root. A board picked in
+// the current directory before wins over both.
+func getLocalTasksPath() (string, bool) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	if path, ok := lastUsedBoard(cwd); ok {
+		return path, true
+	}
+	root, ok := repoRoot(cwd)
+	if !ok {
+		root = cwd
+	}
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		path := findBoard(filepath.Join(dir, ".basket"))
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+	return findBoard(filepath.Join(root, ".basket")), false
+}
+
+// repoRoot finds the top of the git repository holding dir by walking up
+// to the nearest .git, which is a directory in a clone and a file in a
+// worktree or submodule
+func repoRoot(dir string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// boardBase strips the format from a board path, leaving the name shared
+// by all of its formats
+func boardBase(path string) string {
+	ext := filepath.Ext(path)
+	for _, e := range boardExts {
+		if strings.EqualFold(ext, e) {
+			return strings.TrimSuffix(path, ext)
+		}
+	}
+	return path
+}
+
+// isGlobalPath reports whether path is the global board, in any format
+func isGlobalPath(path string) bool {
+	base, ok := globalBoardBase()
+	if !ok {
+		return path == getGlobalTasksPath()
+	}
+	return boardBase(path) == base
+}
+
+func loadBoard(path string) (TaskList, error) {
+	taskList, err := formatFor(path).read(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// A first edit may have been journaled before the file was created
+			taskList := TaskList{Tasks: []Task{}}
+			err := replayJournal(path, &taskList)
+			return taskList, err
+		}
+		logger.Error("load", "path", path, "err", err)
+		return TaskList{}, err
+	}
+
+	logger.Info("load", "path", path, "tasks", len(taskList.Tasks), "archived", len(taskList.Archive))
+	if taskList.Tasks == nil {
+		taskList.Tasks = []Task{}
+	}
+	ensureRanks(taskList.Tasks)
+
+	// Recover edits that were journaled but never written
+	if err := replayJournal(path, &taskList); err != nil {
+		return TaskList{}, err
+	}
+
+	// Migrate boards with colliding IDs so edits never hit the wrong task
+	if dedupeIDs(taskList.Tasks) {
+		if err := saveBoard(path, taskList); err != nil {
+			return TaskList{}, err
+		}
+	}
+	return taskList, nil
+}
+
+// afterSave follows a command's successful save of the board at path
+// from before to after: the changes are queued for the next sync,
+// completions go to the diary and the hooks run. The change is saved by then, so a failing hook is reported
+// but does not fail the command.
+func afterSave(cfg Config, path string, before, after []Task) {
+	queueBoardSync(cfg, path, before, after)
+	for _, err := range runHooks(cfg.Hooks, savedEvents(path, before, after)) {
+		fmt.Fprintf(os.Stderr, "basket: %v\n", err)
+	}
+}
+
+// savedEvents is what every save of a board shares once it succeeded:
+// completions go to the diary, and the events for the hooks are
+// returned. The board calls it itself, as it
+// keeps its own sync queue and runs the hooks in the background.
+func savedEvents(path string, before, after []Task) []taskEvent {
+	logCompletions(path, before, after)
+	return taskEvents(path, before, after)
+}
+
+// cloneTasks copies tasks so the working list on screen never shares its
+// backing array with a stored board
+func cloneTasks(tasks []Task) []Task {
+	out := make([]Task, len(tasks))
+	copy(out, tasks)
+	return out
+}
+
+// loadTasks returns just the working tasks of the board at path
+func loadTasks(path string) ([]Task, error) {
+	taskList, err := loadBoard(path)
+	return taskList.Tasks, err
+}
+
+func saveBoard(path string, taskList TaskList) error {
+	format := formatFor(path)
+
+	// Never replace a board that fails to parse; it may still hold tasks
+	// that only a person can recover
+	var corrupt *corruptBoardError
+	if _, err := format.read(path); errors.As(err, &corrupt) {
+		logger.Error("save", "path", path, "err", "existing board is damaged")
+		return fmt.Errorf("%s is damaged and was not overwritten", path)
+	}
+	if err := backupBoard(path); err != nil {
+		logger.Error("backup", "path", path, "err", err)
+		return err
+	}
+	ensureRanks(taskList.Tasks)
+	if err := format.write(path, taskList); err != nil {
+		logger.Error("save", "path", path, "err", err)
+		return err
+	}
+	logger.Info("save", "path", path, "tasks", len(taskList.Tasks), "archived", len(taskList.Archive))
+	return nil
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SyncConfig configures the remote the global board is synced to
+type SyncConfig struct {
+	// URL of the remote board document. It is read with GET and written
+	// with PUT, which suits WebDAV shares and simple HTTP stores.
+	URL   string `json:"url"`
+	Token string `json:"token"`
+
+	// Type is "http" (the default) or "caldav", which syncs with a
+	// CalDAV task list; URL is then the collection URL
+	Type     string `json:"type"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// For "mstodo", Microsoft To Do: the app registration to sign in
+	// with, its tenant ("common" unless set) and the list to sync with,
+	// the default list unless set. URL is not used.
+	ClientID string `json:"client_id"`
+	Tenant   string `json:"tenant"`
+	List     string `json:"list"`
+
+	// For "todoist": Token is the API token and Project the project to
+	// sync with, the inbox unless set. URL is not used.
+	Project string `json:"project"`
+}
+
+// syncRetryInterval is how often a failed sync is retried
+const syncRetryInterval = 30 * time.Second
+
+// syncBackend is a remote copy of a board
+type syncBackend interface {
+	Pull() (TaskList, error)
+	Push(TaskList) error
+}
+
+// syncState is what the header reports about the remote
+type syncState int
+
+const (
+	SyncNone syncState = iota // no backend configured
+	SyncSynced
+	SyncPending
+	SyncError
+	SyncOffline
+)
+
+func (s syncState) String() string {
+	switch s {
+	case SyncSynced:
+		return T("sync.synced")
+	case SyncPending:
+		return T("sync.pending")
+	case SyncError:
+		return T("sync.error")
+	case SyncOffline:
+		return T("sync.offline")
+	default:
+		return ""
+	}
+}
+
+func (s syncState) Color() lipgloss.TerminalColor {
+	switch s {
+	case SyncSynced:
+		return colorSuccess
+	case SyncPending:
+		return colorAccent
+	case SyncError:
+		return colorError
+	default:
+		return colorDone
+	}
+}
+
+// newSyncBackend builds the backend described by the config, or nil when
+// sync is not configured
+func newSyncBackend(cfg Config) syncBackend {
+	if cfg.Sync == nil {
+		return nil
+	}
+	switch cfg.Sync.Type {
+	case "mstodo":
+		return newMSTodoBackend(cfg.Sync)
+	case "todoist":
+		return newTodoistBackend(cfg.Sync)
+	}
+	if cfg.Sync.URL == "" {
+		return nil
+	}
+	if cfg.Sync.Type == "caldav" {
+		return newCalDAVBackend(cfg.Sync)
+	}
+	return &httpBackend{
+		url:    cfg.Sync.URL,
+		token:  cfg.Sync.Token,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// httpBackend stores the board as a JSON document at a URL
+type httpBackend struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (h *httpBackend) do(method string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, h.url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, errRemoteMissing
+	}
+	if resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s", method, h.url, resp.Status)
+	}
+	return resp, nil
+}
+
+// errRemoteMissing is returned by do when the remote document does not exist
+var errRemoteMissing = errors.New("remote board does not exist")
+
+func (h *httpBackend) Pull() (TaskList, error) {
+	resp, err := h.do(http.MethodGet, nil)
+	if errors.Is(err, errRemoteMissing) {
+		return TaskList{Tasks: []Task{}}, nil
+	}
+	if err != nil {
+		return TaskList{}, err
+	}
+	defer resp.Body.Close()
+
+	var taskList TaskList
+	if err := json.NewDecoder(resp.Body).Decode(&taskList); err != nil {
+		return TaskList{}, err
+	}
+	return taskList, nil
+}
+
+func (h *httpBackend) Push(taskList TaskList) error {
+	data, err := json.MarshalIndent(taskList, "", "  ")
+	if err != nil {
+		return err
+	}
+	resp, err := h.do(http.MethodPut, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// isOffline reports whether err means the remote could not be reached at
+// all, as opposed to the remote rejecting the request
+func isOffline(err error) bool {
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	return errors.As(err, &opErr) || errors.As(err, &dnsErr) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+func getSyncQueuePath() string {
+	return filepath.Join(getDataDir(), "sync-queue.json")
+}
+
+// getSyncMarkerPath is a file recording that a first sync has completed
+func getSyncMarkerPath() string {
+	return filepath.Join(getDataDir(), "synced")
+}
+
+// loadSyncQueue reads the mutations that have not reached the remote yet.
+// Before the first successful sync every local task counts as unsynced,
+// so enabling sync merges the existing board into the remote instead of
+// replacing it.
+func loadSyncQueue(local []Task) []mutation {
+	if _, err := os.Stat(getSyncMarkerPath()); os.IsNotExist(err) {
+		return diffTasks(nil, local)
+	}
+	data, err := os.ReadFile(getSyncQueuePath())
+	if err != nil {
+		return nil
+	}
+	var queue []mutation
+	json.Unmarshal(data, &queue)
+	return queue
+}
+
+func saveSyncQueue(queue []mutation) error {
+	if len(queue) == 0 {
+		err := os.Remove(getSyncQueuePath())
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.MkdirAll(getDataDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getSyncQueuePath(), data, 0644)
+}
+
+// queueSync records changes to the global board for the next sync. The
+// queue lives on disk, so edits made while the remote is unreachable
+// survive restarts and are replayed once it is back.
+func (m *model) queueSync(muts []mutation) {
+	if m.syncer == nil || len(muts) == 0 {
+		return
+	}
+	m.syncQueue = append(m.syncQueue, muts...)
+	if err := saveSyncQueue(m.syncQueue); err != nil {
+		logger.Error("save sync queue", "err", err)
+	}
+	logger.Debug("sync queue", "added", len(muts), "queued", len(m.syncQueue))
+	m.syncDirty = true
+}
+
+// queueBoardSync queues the changes a command made to the global board,
+// so the next sync pushes them instead of pulling over them
+func queueBoardSync(cfg Config, path string, before, after []Task) {
+	if newSyncBackend(cfg) == nil || !isGlobalPath(path) {
+		return
+	}
+	queue := append(loadSyncQueue(before), diffTasks(before, after)...)
+	if err := saveSyncQueue(queue); err != nil {
+		logger.Error("save sync queue", "err", err)
+	}
+}
+
+// syncResultMsg reports the outcome of a sync run
+type syncResultMsg struct {
+	err     error
+	remote  TaskList
+	flushed int // how many queued mutations reached the remote
+}
+
+// syncRetryMsg asks for another attempt after a failed sync
+type syncRetryMsg struct{}
+
+// runSync replays queued mutations onto the current remote board and
+// uploads the result. Replaying on top of a fresh pull, instead of
+// uploading the local board wholesale, keeps changes made elsewhere.
+func runSync(backend syncBackend, queue []mutation) tea.Cmd {
+	return func() tea.Msg {
+		remote, err := backend.Pull()
+		if err != nil {
+			logger.Error("sync pull", "err", err)
+			return syncResultMsg{err: err}
+		}
+		logger.Debug("sync pull", "tasks", len(remote.Tasks), "queued", len(queue))
+		if len(queue) > 0 {
+			remote.Tasks = applyMutations(remote.Tasks, queue)
+			if err := backend.Push(remote); err != nil {
+				logger.Error("sync push", "err", err)
+				return syncResultMsg{err: err}
+			}
+			logger.Info("sync push", "tasks", len(remote.Tasks), "mutations", len(queue))
+		}
+		return syncResultMsg{remote: remote, flushed: len(queue)}
+	}
+}
+
+// syncCmd starts a sync when there are unsynced changes and no other
+// sync is running
+func (m *model) syncCmd() tea.Cmd {
+	if m.syncer == nil || !m.syncDirty || m.syncBusy {
+		return nil
+	}
+	m.syncDirty = false
+	m.syncBusy = true
+	m.syncState = SyncPending
+
+	queue := make([]mutation, len(m.syncQueue))
+	copy(queue, m.syncQueue)
+	return runSync(m.syncer, queue)
+}
+
+func (m model) handleSyncResult(msg syncResultMsg) (tea.Model, tea.Cmd) {
+	m.syncBusy = false
+	m.syncErr = ""
+
+	if msg.err != nil {
+		m.syncState = SyncError
+		if isOffline(msg.err) {
+			m.syncState = SyncOffline
+		}
+		m.syncErr = msg.err.Error()
+		// Keep the queue and try again later, without blocking the UI
+		return m, tea.Tick(syncRetryInterval, func(time.Time) tea.Msg { return syncRetryMsg{} })
+	}
+
+	m.syncState = SyncSynced
+	m.syncQueue = m.syncQueue[msg.flushed:]
+	saveSyncQueue(m.syncQueue)
+	os.WriteFile(getSyncMarkerPath(), []byte(time.Now().Format(time.RFC3339)), 0644)
+
+	// Adopt the remote board, with edits made while the sync was in
+	// flight layered on top
+	merged := applyMutations(msg.remote.Tasks, m.syncQueue)
+	if len(diffTasks(m.globalBoard.Tasks, merged)) > 0 && !m.isCorrupt(m.globalPath) {
+		m.globalBoard.Tasks = merged
+		if err := saveBoard(m.globalPath, m.globalBoard); err != nil {
+			m.fail(fmt.Errorf(T("status.save_failed"), err))
+		}
+		if !m.showingLocal {
+			m.tasks = cloneTasks(m.globalBoard.Tasks)
+			m.clampSelection()
+			m.refindEditingTask()
+		}
+	}
+
+	if len(m.syncQueue) > 0 {
+		m.syncDirty = true
+		return m, m.syncCmd()
+	}
+	return m, nil
+}
+
+// refindEditingTask points an open form back at its task after the task
+// list was replaced, so saving it does not write to the old list. A form
+// whose task is gone is closed.
+func (m *model) refindEditingTask() {
+	if m.editingTask == nil {
+		return
+	}
+	id := m.editingTask.ID
+	m.editingTask = nil
+	for i := range m.tasks {
+		if m.tasks[i].ID == id {
+			m.editingTask = &m.tasks[i]
+			return
+		}
+	}
+	m.mode = ViewBoard
+	m.notify(T("status.task_gone"))
+}
+
+// renderSyncStatus is the header badge for the sync state
+func (m model) renderSyncStatus() string {
+	if m.syncState == SyncNone {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(m.syncState.Color()).
+		Background(colorHeaderBg).
+		Padding(0, 1).
+		Render(m.syncState.String())
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tagPalette colors tags that have no color in the config, picked by a
+// hash of the name so a tag keeps its color across boards and runs
+var tagPalette = []lipgloss.CompleteColor{
+	{TrueColor: "#F87171", ANSI256: "203", ANSI: "9"},
+	{TrueColor: "#FB923C", ANSI256: "209", ANSI: "3"},
+	{TrueColor: "#FBBF24", ANSI256: "214", ANSI: "11"},
+	{TrueColor: "#A3E635", ANSI256: "148", ANSI: "10"},
+	{TrueColor: "#34D399", ANSI256: "78", ANSI: "2"},
+	{TrueColor: "#22D3EE", ANSI256: "45", ANSI: "14"},
+	{TrueColor: "#60A5FA", ANSI256: "75", ANSI: "12"},
+	{TrueColor: "#A78BFA", ANSI256: "141", ANSI: "13"},
+	{TrueColor: "#F472B6", ANSI256: "211", ANSI: "5"},
+	{TrueColor: "#94A3B8", ANSI256: "110", ANSI: "6"},
+}
+
+// maxTagChips is how many tags a card shows before folding the rest
+const maxTagChips = 3
+
+// tagColor is the color of a tag: the config's, or one from the palette
+func tagColor(tag string, colors map[string]string) lipgloss.TerminalColor {
+	tag = strings.ToLower(tag)
+	for name, color := range colors {
+		if strings.ToLower(strings.TrimPrefix(name, "@")) == tag && color != "" {
+			return lipgloss.Color(color)
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(tag))
+	return tagPalette[h.Sum32()%uint32(len(tagPalette))]
+}
+
+// tagChip renders a tag as a small colored chip
+func tagChip(tag string, colors map[string]string) string {
+	return lipgloss.NewStyle().Foreground(tagColor(tag, colors)).Render("●" + tag)
+}
+
+// cardAccent is the color of a card's left border under the card_accent
+// setting, nil when the card has none
+func (m model) cardAccent(task Task) lipgloss.TerminalColor {
+	switch m.config.CardAccent {
+	case "tag":
+		if tags := taskContexts(task); len(tags) > 0 {
+			return tagColor(tags[0], m.config.TagColors)
+		}
+	case "project":
+		if task.Project != "" {
+			return tagColor(task.Project, m.config.ProjectColors)
+		}
+	}
+	return nil
+}
+
+// renderTagChips is the chip line of a card, empty without tags
+func renderTagChips(task Task, colors map[string]string) string {
+	tags := taskContexts(task)
+	if len(tags) == 0 {
+		return ""
+	}
+	var chips []string
+	for i, tag := range tags {
+		if i == maxTagChips {
+			chips = append(chips, helpStyle.Render(fmt.Sprintf("+%d", len(tags)-maxTagChips)))
+			break
+		}
+		chips = append(chips, tagChip(tag, colors))
+	}
+	return strings.Join(chips, " ")
+}
+
+// renderTagLegend lists the board's tags with their colors and open
+// counts, wrapped to width
+func (m model) renderTagLegend(width int) string {
+	tags := summarizeContexts(m.tasks)
+	if len(tags) == 0 {
+		return helpStyle.Render(T("legend.empty"))
+	}
+	var lines []string
+	line := helpStyle.Render(T("legend.title"))
+	lineWidth := lipgloss.Width(line)
+	for _, s := range tags {
+		entry := tagChip(s.Name, m.config.TagColors) + helpStyle.Render(fmt.Sprintf(" %d", s.Open))
+		w := lipgloss.Width(entry) + 2
+		if width > 0 && lineWidth+w > width {
+			lines = append(lines, line)
+			line, lineWidth = " ", 1
+		}
+		line += "  " + entry
+		lineWidth += w
+	}
+	return strings.Join(append(lines, line), "\n")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxTagSuggestions is how many completions are shown under a form
+const maxTagSuggestions = 5
+
+// fuzzyScore reports whether the letters of pattern appear in s in order,
+// and how well: matches at the start and runs of adjacent letters score
+// higher, so "inf" ranks infra above "pipeline-fix"
+func fuzzyScore(pattern, s string) (int, bool) {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	score, last := 0, -2
+	i := 0
+	for _, r := range pattern {
+		j := strings.IndexRune(s[i:], r)
+		if j < 0 {
+			return 0, false
+		}
+		pos := i + j
+		switch {
+		case pos == 0:
+			score += 3
+		case pos == last+1:
+			score += 2
+		default:
+			score++
+		}
+		last = pos
+		i = pos + utf8.RuneLen(r)
+	}
+	return score*4 - len(s), true
+}
+
+// tagPrefix is the tag being typed at the end of text: the word after a
+// trailing @, or after tag: or context: in a query
+func tagPrefix(text string) (prefix, typed string, ok bool) {
+	start := strings.LastIndexFunc(text, unicode.IsSpace) + 1
+	word := text[start:]
+	word = strings.TrimLeft(word, "(")
+	for _, p := range []string{"@", "tag:", "context:"} {
+		if rest, found := strings.CutPrefix(word, p); found {
+			return p, rest, true
+		}
+	}
+	return "", "", false
+}
+
+// tagSuggestions are the board's tags that fit what is being typed at the
+// end of text, best first
+func tagSuggestions(tasks []Task, text string) []string {
+	_, typed, ok := tagPrefix(text)
+	if !ok {
+		return nil
+	}
+	type scored struct {
+		tag   string
+		score int
+		uses  int
+	}
+	var matches []scored
+	for _, s := range summarizeContexts(tasks) {
+		if s.Name == strings.ToLower(typed) {
+			continue // already complete
+		}
+		if score, ok := fuzzyScore(typed, s.Name); ok {
+			matches = append(matches, scored{s.Name, score, s.Total})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].uses > matches[j].uses
+	})
+
+	var out []string
+	for _, m := range matches {
+		if len(out) == maxTagSuggestions {
+			break
+		}
+		out = append(out, m.tag)
+	}
+	return out
+}
+
+// completeTag replaces the tag being typed at the end of text with tag
+func completeTag(text, tag string) string {
+	prefix, typed, ok := tagPrefix(text)
+	if !ok {
+		return text
+	}
+	return strings.TrimSuffix(text, prefix+typed) + prefix + tag + " "
+}
+
+// renderTagSuggestions is the hint line under a form, empty when there is
+// nothing to complete
+func renderTagSuggestions(tags []string, k keyMap) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return helpStyle.Render(k.Complete.Help().Key + "  @" + strings.Join(tags, "  @"))
+}
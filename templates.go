@@ -0,0 +1,157 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// boardTemplate is a starting point for a new board: its columns and
+// starter tasks. Templates are JSON files, so they can be shared.
+type boardTemplate struct {
+	Description string          `json:"description"`
+	Columns     []PriorityLevel `json:"columns"`
+	Tasks       []templateTask  `json:"tasks"`
+}
+
+// templateTask is a starter task. Priority is a column name, the middle
+// column when empty; contexts are added to the title as @tags.
+type templateTask struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Priority    string   `json:"priority"`
+	Project     string   `json:"project"`
+	Contexts    []string `json:"contexts"`
+	Someday     bool     `json:"someday"`
+}
+
+//go:embed templates/*.json
+var builtinTemplates embed.FS
+
+// getTemplatesDir holds the user's own templates, which shadow the
+// built-in ones of the same name
+func getTemplatesDir() string {
+	return filepath.Join(getConfigDir(), "templates")
+}
+
+// loadTemplate reads the template called name, or the template file at
+// that path
+func loadTemplate(name string) (boardTemplate, error) {
+	var data []byte
+	var err error
+	switch {
+	case strings.HasSuffix(name, ".json"):
+		data, err = os.ReadFile(name)
+	default:
+		data, err = os.ReadFile(filepath.Join(getTemplatesDir(), name+".json"))
+		if os.IsNotExist(err) {
+			data, err = builtinTemplates.ReadFile("templates/" + name + ".json")
+			if err != nil {
+				return boardTemplate{}, fmt.Errorf("no template %q (see basket init --list)", name)
+			}
+		}
+	}
+	if err != nil {
+		return boardTemplate{}, err
+	}
+	var tmpl boardTemplate
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return boardTemplate{}, fmt.Errorf("template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// templateNames lists the built-in and user templates
+func templateNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(file string) {
+		if name, ok := strings.CutSuffix(file, ".json"); ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if entries, err := builtinTemplates.ReadDir("templates"); err == nil {
+		for _, e := range entries {
+			add(e.Name())
+		}
+	}
+	if entries, err := os.ReadDir(getTemplatesDir()); err == nil {
+		for _, e := range entries {
+			add(e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// templateBoard builds the board a template describes
+func templateBoard(tmpl boardTemplate, now time.Time) (TaskList, error) {
+	useColumns(tmpl.Columns)
+	board := TaskList{Tasks: []Task{}, Columns: tmpl.Columns}
+	for _, t := range tmpl.Tasks {
+		p := defaultPriority()
+		if t.Priority != "" {
+			var ok bool
+			if p, ok = parsePriorityName(t.Priority); !ok {
+				return TaskList{}, fmt.Errorf("task %q: no column %q", t.Title, t.Priority)
+			}
+		}
+		title := t.Title
+		for _, ctx := range t.Contexts {
+			title += " @" + strings.TrimPrefix(ctx, "@")
+		}
+		board.Tasks = append(board.Tasks, Task{
+			ID:          generateID(),
+			Title:       title,
+			Description: t.Description,
+			Priority:    p,
+			Project:     t.Project,
+			Someday:     t.Someday,
+			CreatedAt:   now,
+		})
+	}
+	return board, nil
+}
+
+func cmdInit(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	name := fs.String("template", "blank", "template name, or a template .json file")
+	list := fs.Bool("list", false, "list the available templates")
+	fs.Parse(args)
+
+	if *list {
+		for _, n := range templateNames() {
+			desc := ""
+			if tmpl, err := loadTemplate(n); err == nil {
+				desc = tmpl.Description
+			}
+			fmt.Printf("%-12s  %s\n", n, desc)
+		}
+		return nil
+	}
+
+	path, exists := getLocalTasksPath()
+	if exists {
+		return fmt.Errorf("%s already exists", path)
+	}
+	tmpl, err := loadTemplate(*name)
+	if err != nil {
+		return err
+	}
+	board, err := templateBoard(tmpl, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := saveBoard(path, board); err != nil {
+		return err
+	}
+	fmt.Printf("created %s from the %s template with %d task(s)\n", path, *name, len(board.Tasks))
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// The interface's colors, each with a variant for light terminals, where
+// the pale grays and amber meant for dark backgrounds wash out. Every
+// color names its stand-ins for 256- and 16-color terminals, so the
+// palette stays apart there instead of collapsing onto the nearest few
+// ANSI colors. Column and tag colors from the config are left as they
+// are.
+var (
+	colorAccent   = themeColor("#B45309", "130", "3", "#FBBF24", "214", "11")
+	colorWarning  = themeColor("#C2410C", "166", "3", "#F59E0B", "208", "3")
+	colorError    = themeColor("#DC2626", "160", "1", "#EF4444", "196", "9")
+	colorSuccess  = themeColor("#047857", "29", "2", "#10B981", "36", "10")
+	colorInfo     = themeColor("#1D4ED8", "26", "4", "#3B82F6", "33", "12")
+	colorMuted    = themeColor("#4B5563", "240", "8", "#9CA3AF", "248", "7") // secondary text
+	colorDone     = themeColor("#9CA3AF", "248", "8", "#6B7280", "243", "8") // completed tasks
+	colorFaint    = themeColor("#D1D5DB", "252", "7", "#4B5563", "239", "8") // empty columns, dimmed views
+	colorBorder   = themeColor("#D1D5DB", "252", "7", "#374151", "237", "8")
+	colorHeaderBg = themeColor("#E5E7EB", "254", "7", "#1F2937", "235", "0")
+)
+
+// themeColor is a color for light and for dark backgrounds, each as hex,
+// a 256-color index and a 16-color index
+func themeColor(light, light256, light16, dark, dark256, dark16 string) lipgloss.CompleteAdaptiveColor {
+	return lipgloss.CompleteAdaptiveColor{
+		Light: lipgloss.CompleteColor{TrueColor: light, ANSI256: light256, ANSI: light16},
+		Dark:  lipgloss.CompleteColor{TrueColor: dark, ANSI256: dark256, ANSI: dark16},
+	}
+}
+
+// applyTheme settles whether the terminal is light or dark before the
+// TUI starts: "light" or "dark" from the config, otherwise by asking the
+// terminal for its background. Asking later would race bubbletea for
+// the reply on stdin.
+func applyTheme(theme string) {
+	switch theme {
+	case "light":
+		lipgloss.SetHasDarkBackground(false)
+	case "dark":
+		lipgloss.SetHasDarkBackground(true)
+	default:
+		lipgloss.SetHasDarkBackground(lipgloss.HasDarkBackground())
+	}
+}
+
+// applyColors overrides how many colors the terminal is taken to have:
+// "truecolor", "256", "16" or "none". Unset goes by the environment
+// (COLORTERM, TERM and NO_COLOR).
+func applyColors(colors string) {
+	switch colors {
+	case "truecolor":
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	case "256":
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case "16":
+		lipgloss.SetColorProfile(termenv.ANSI)
+	case "none":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeEntry is one stretch of work on a task. End is nil while the timer
+// runs.
+type TimeEntry struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// runningEntry is the open entry of task, or nil when no timer runs
+func runningEntry(task *Task) *TimeEntry {
+	for i := range task.TimeLog {
+		if task.TimeLog[i].End == nil {
+			return &task.TimeLog[i]
+		}
+	}
+	return nil
+}
+
+// stopTimer closes the running entry of task at now and returns how long
+// it ran. The log is copied first, since the saved board shares it.
+func stopTimer(task *Task, now time.Time) (time.Duration, bool) {
+	for i, e := range task.TimeLog {
+		if e.End == nil {
+			task.TimeLog = append([]TimeEntry(nil), task.TimeLog...)
+			task.TimeLog[i].End = &now
+			return now.Sub(e.Start), true
+		}
+	}
+	return 0, false
+}
+
+// trackedBetween is the time logged on task within [from, to). A running
+// timer counts up to now.
+func trackedBetween(task Task, from, to, now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range task.TimeLog {
+		end := now
+		if e.End != nil {
+			end = *e.End
+		}
+		start := e.Start
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// trackedTotal is all the time logged on task
+func trackedTotal(task Task, now time.Time) time.Duration {
+	return trackedBetween(task, time.Time{}, now, now)
+}
+
+// formatDuration renders d as hours and minutes, e.g. "1h05m" or "25m"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// toggleTimer starts the timer on the selected task, or stops it if it is
+// already running. Only one timer runs at a time, so starting one stops
+// any other.
+func (m *model) toggleTimer() {
+	tasksInCol := m.getTasksInColumn(Priority(m.selectedCol))
+	if len(tasksInCol) == 0 || m.selectedTask >= len(tasksInCol) {
+		return
+	}
+	id := tasksInCol[m.selectedTask].ID
+	now := time.Now()
+
+	for i := range m.tasks {
+		if m.tasks[i].ID != id {
+			continue
+		}
+		if d, ok := stopTimer(&m.tasks[i], now); ok {
+			m.commit(fmt.Sprintf(T("status.timed"), formatDuration(d)))
+			return
+		}
+	}
+	for i := range m.tasks {
+		stopTimer(&m.tasks[i], now)
+		if m.tasks[i].ID == id {
+			m.tasks[i].TimeLog = append(m.tasks[i].TimeLog, TimeEntry{Start: now})
+		}
+	}
+	m.commit(T("status.timing"))
+}
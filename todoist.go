@@ -0,0 +1,401 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// todoistBackend syncs the board with a Todoist project through the Sync
+// API, with the API token from Todoist's integration settings. Basket
+// keeps a copy of the project's tasks in the data directory and passes
+// the sync token of the last call, so each pull only brings what changed
+// since.
+//
+// Like To Do, Todoist has no room for comments, timers and the like, so
+// basket remembers the task each item belongs to, as last synced.
+type todoistBackend struct {
+	token   string
+	project string // project name, empty for the inbox
+	client  *http.Client
+
+	// pulled is the remote state seen by the last Pull, by basket task
+	// ID, so Push only writes what changed
+	pulled map[string]todoistPulled
+	state  todoistState
+}
+
+type todoistPulled struct {
+	item todoistItem
+	task Task
+}
+
+// todoistState is the local copy of the project, carried between runs
+type todoistState struct {
+	SyncToken string                 `json:"sync_token"`
+	ProjectID string                 `json:"project_id"`
+	Items     map[string]todoistItem `json:"items"`
+	Tasks     map[string]Task        `json:"tasks"` // as last synced, by item ID
+}
+
+// todoistItem is a task as the Sync API has it
+type todoistItem struct {
+	ID          string      `json:"id"`
+	ProjectID   string      `json:"project_id"`
+	Content     string      `json:"content"`
+	Description string      `json:"description"`
+	Priority    int         `json:"priority"` // 1 (normal) to 4 (urgent)
+	Due         *todoistDue `json:"due"`
+	Checked     bool        `json:"checked"`
+	IsDeleted   bool        `json:"is_deleted"`
+	AddedAt     string      `json:"added_at"`
+	CompletedAt string      `json:"completed_at"`
+	UpdatedAt   string      `json:"updated_at"`
+}
+
+type todoistDue struct {
+	Date string `json:"date"` // 2006-01-02, or with a time
+}
+
+type todoistProject struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Inbox     bool   `json:"inbox_project"`
+	IsDeleted bool   `json:"is_deleted"`
+}
+
+// todoistCommand is one write in a sync call
+type todoistCommand struct {
+	Type   string         `json:"type"`
+	UUID   string         `json:"uuid"`
+	TempID string         `json:"temp_id,omitempty"`
+	Args   map[string]any `json:"args"`
+}
+
+const todoistSyncURL = "https://api.todoist.com/sync/v9/sync"
+
+func getTodoistStatePath() string {
+	return filepath.Join(getDataDir(), "todoist.json")
+}
+
+func newTodoistBackend(cfg *SyncConfig) *todoistBackend {
+	return &todoistBackend{
+		token:   cfg.Token,
+		project: cfg.Project,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// todoistPriority maps a level onto Todoist's 1 (normal) to 4 (urgent).
+// Todoist has nothing below normal, so the default level and those under
+// it are all 1, and the levels above spread over the rest.
+func todoistPriority(p Priority) int {
+	above, span := int(p-defaultPriority()), int(maxPriority()-defaultPriority())
+	if above <= 0 || span == 0 {
+		return 1
+	}
+	return 1 + (above*3+span-1)/span
+}
+
+// fromTodoistPriority is the level nearest to a Todoist priority
+func fromTodoistPriority(n int) Priority {
+	if n < 1 || n > 4 {
+		return defaultPriority()
+	}
+	span := int(maxPriority() - defaultPriority())
+	return defaultPriority() + Priority(((n-1)*span+1)/3)
+}
+
+func (d *todoistDue) time() (time.Time, bool) {
+	if d == nil || d.Date == "" {
+		return time.Time{}, false
+	}
+	if t, err := time.Parse(time.RFC3339, d.Date); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, d.Date, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// todoistDueOf is a task's due date as Todoist takes it, nil for none
+func todoistDueOf(task Task) *todoistDue {
+	if task.Due == nil {
+		return nil
+	}
+	if due := task.Due.Local(); due.Equal(startOfDay(due)) {
+		return &todoistDue{Date: due.Format("2006-01-02")}
+	}
+	return &todoistDue{Date: task.Due.UTC().Format(time.RFC3339)}
+}
+
+// taskFromTodoist lays the fields of an item over base, the task as
+// basket last synced it; ok is false for a task added in Todoist
+func taskFromTodoist(item todoistItem, base Task, ok bool) Task {
+	task := base
+	if !ok {
+		task = Task{ID: generateID(), CreatedAt: time.Now()}
+		if added, err := time.Parse(time.RFC3339, item.AddedAt); err == nil {
+			task.CreatedAt = added
+		}
+	}
+	task.Title = item.Content
+	task.Description = item.Description
+	if !ok || todoistPriority(clampPriority(task.Priority)) != item.Priority {
+		task.Priority = fromTodoistPriority(item.Priority)
+	}
+	task.Due = nil
+	if due, ok := item.Due.time(); ok {
+		task.Due = &due
+	}
+	if updated, err := time.Parse(time.RFC3339, item.UpdatedAt); err == nil && updated.After(lastModified(task)) {
+		task.UpdatedAt = &updated
+	}
+
+	if item.Checked != task.Completed {
+		task.Completed = item.Checked
+		task.CompletedAt = nil
+	}
+	if at, err := time.Parse(time.RFC3339, item.CompletedAt); err == nil && item.Checked && task.CompletedAt == nil {
+		task.CompletedAt = &at
+	}
+	if item.Checked && task.CompletedAt == nil {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	return task
+}
+
+func todoistUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// todoistResponse is the part of a sync reply basket reads
+type todoistResponse struct {
+	SyncToken     string            `json:"sync_token"`
+	FullSync      bool              `json:"full_sync"`
+	Items         []todoistItem     `json:"items"`
+	Projects      []todoistProject  `json:"projects"`
+	SyncStatus    map[string]any    `json:"sync_status"`
+	TempIDMapping map[string]string `json:"temp_id_mapping"`
+}
+
+// call makes one sync call with form
+func (t *todoistBackend) call(form url.Values) (todoistResponse, error) {
+	var r todoistResponse
+	req, err := http.NewRequest(http.MethodPost, todoistSyncURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return r, err
+	}
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return r, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return r, fmt.Errorf("todoist: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return r, fmt.Errorf("todoist: %w", err)
+	}
+	for _, status := range r.SyncStatus {
+		if s, ok := status.(string); !ok || s != "ok" {
+			data, _ := json.Marshal(status)
+			return r, fmt.Errorf("todoist: %s", data)
+		}
+	}
+	return r, nil
+}
+
+// commit sends commands and returns the IDs given to their temp IDs
+func (t *todoistBackend) commit(commands []todoistCommand) (map[string]string, error) {
+	if len(commands) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(commands)
+	if err != nil {
+		return nil, err
+	}
+	r, err := t.call(url.Values{"commands": {string(data)}})
+	return r.TempIDMapping, err
+}
+
+func (t *todoistBackend) loadState() {
+	t.state = todoistState{}
+	if data, err := os.ReadFile(getTodoistStatePath()); err == nil {
+		json.Unmarshal(data, &t.state)
+	}
+	if t.state.SyncToken == "" {
+		t.state.SyncToken = "*"
+	}
+	if t.state.Items == nil {
+		t.state.Items = map[string]todoistItem{}
+	}
+	if t.state.Tasks == nil {
+		t.state.Tasks = map[string]Task{}
+	}
+}
+
+func (t *todoistBackend) saveState() error {
+	if err := os.MkdirAll(getDataDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(getTodoistStatePath(), data, 0644)
+}
+
+// resolveProject finds the project the board syncs with among projects,
+// creating a named one that does not exist yet
+func (t *todoistBackend) resolveProject(projects []todoistProject) error {
+	for _, p := range projects {
+		if p.IsDeleted {
+			continue
+		}
+		if (t.project == "" && p.Inbox) || (t.project != "" && p.Name == t.project) {
+			if p.ID != t.state.ProjectID {
+				// Another project: start over from a full sync
+				t.state = todoistState{SyncToken: "*", ProjectID: p.ID, Items: map[string]todoistItem{}, Tasks: map[string]Task{}}
+			}
+			return nil
+		}
+	}
+	if t.state.ProjectID != "" {
+		return nil
+	}
+	if t.project == "" {
+		return fmt.Errorf("todoist: no inbox project")
+	}
+	temp := todoistUUID()
+	ids, err := t.commit([]todoistCommand{{Type: "project_add", UUID: todoistUUID(), TempID: temp, Args: map[string]any{"name": t.project}}})
+	if err != nil {
+		return err
+	}
+	t.state.ProjectID = ids[temp]
+	return nil
+}
+
+func (t *todoistBackend) Pull() (TaskList, error) {
+	t.loadState()
+	r, err := t.call(url.Values{
+		"sync_token":     {t.state.SyncToken},
+		"resource_types": {`["projects","items"]`},
+	})
+	if err != nil {
+		return TaskList{}, err
+	}
+	if r.FullSync && t.state.SyncToken != "*" {
+		// A full sync leaves out completed items, so keep the ones known
+		for id, item := range t.state.Items {
+			if !item.Checked {
+				delete(t.state.Items, id)
+			}
+		}
+	}
+	if t.state.SyncToken == "*" || len(r.Projects) > 0 {
+		if err := t.resolveProject(r.Projects); err != nil {
+			return TaskList{}, err
+		}
+	}
+	t.state.SyncToken = r.SyncToken
+	for _, item := range r.Items {
+		if item.IsDeleted || item.ProjectID != t.state.ProjectID {
+			delete(t.state.Items, item.ID)
+			continue
+		}
+		t.state.Items[item.ID] = item
+	}
+
+	t.pulled = map[string]todoistPulled{}
+	taskList := TaskList{Tasks: []Task{}}
+	for id, item := range t.state.Items {
+		base, ok := t.state.Tasks[id]
+		task := taskFromTodoist(item, base, ok)
+		t.state.Tasks[id] = task
+		t.pulled[task.ID] = todoistPulled{item: item, task: task}
+		taskList.Tasks = append(taskList.Tasks, task)
+	}
+	for id := range t.state.Tasks {
+		if _, ok := t.state.Items[id]; !ok {
+			delete(t.state.Tasks, id)
+		}
+	}
+	return taskList, t.saveState()
+}
+
+// Push sends the tasks that differ from the last pull, and deletes the
+// ones that are gone, in one sync call
+func (t *todoistBackend) Push(taskList TaskList) error {
+	var commands []todoistCommand
+	temps := map[string]Task{} // new tasks by temp ID
+	keep := map[string]bool{}
+	for _, task := range taskList.Tasks {
+		keep[task.ID] = true
+		old, ok := t.pulled[task.ID]
+		if ok && sameTask(old.task, task) {
+			continue
+		}
+		args := map[string]any{
+			"content":     task.Title,
+			"description": task.Description,
+			"priority":    todoistPriority(clampPriority(task.Priority)),
+			"due":         todoistDueOf(task),
+		}
+		if !ok {
+			temp := todoistUUID()
+			temps[temp] = task
+			args["project_id"] = t.state.ProjectID
+			commands = append(commands, todoistCommand{Type: "item_add", UUID: todoistUUID(), TempID: temp, Args: args})
+			if task.Completed {
+				commands = append(commands, todoistCommand{Type: "item_close", UUID: todoistUUID(), Args: map[string]any{"id": temp}})
+			}
+			continue
+		}
+		args["id"] = old.item.ID
+		commands = append(commands, todoistCommand{Type: "item_update", UUID: todoistUUID(), Args: args})
+		switch {
+		case task.Completed && !old.item.Checked:
+			commands = append(commands, todoistCommand{Type: "item_close", UUID: todoistUUID(), Args: map[string]any{"id": old.item.ID}})
+		case !task.Completed && old.item.Checked:
+			commands = append(commands, todoistCommand{Type: "item_uncomplete", UUID: todoistUUID(), Args: map[string]any{"id": old.item.ID}})
+		}
+		t.state.Tasks[old.item.ID] = task
+	}
+	for id, old := range t.pulled {
+		if !keep[id] {
+			commands = append(commands, todoistCommand{Type: "item_delete", UUID: todoistUUID(), Args: map[string]any{"id": old.item.ID}})
+			delete(t.state.Tasks, old.item.ID)
+		}
+	}
+
+	ids, err := t.commit(commands)
+	if err != nil {
+		return err
+	}
+	// The next pull brings the new items; remember which task each is
+	for temp, task := range temps {
+		if id, ok := ids[temp]; ok {
+			t.state.Tasks[id] = task
+		}
+	}
+	return t.saveState()
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// ClickUp and Asana both export a project as CSV, with a column per field
+// under names of their own. Basket guesses which column holds what and,
+// on a terminal, lets you correct the guess before importing.
+
+// trackerField is a task field a CSV column can be mapped onto
+type trackerField struct {
+	Name    string
+	Aliases []string // header names it is guessed from, lowercased
+}
+
+var trackerFields = []trackerField{
+	{"title", []string{"task name", "name", "title", "task"}},
+	{"description", []string{"task content", "notes", "description", "content"}},
+	{"status", []string{"status", "completed at", "section/column", "section"}},
+	{"priority", []string{"priority"}},
+	{"assignee", []string{"assignees", "assignee", "assignee email"}},
+	{"due", []string{"due date", "due date text", "due"}},
+	{"project", []string{"list name", "list", "projects", "project", "folder name"}},
+	{"tags", []string{"tags", "labels"}},
+	{"created", []string{"date created", "created at", "date created text"}},
+}
+
+// trackerSource names the tool a CSV header comes from, or "" for none
+// basket knows
+func trackerSource(header []string) string {
+	has := map[string]bool{}
+	for _, name := range header {
+		has[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	switch {
+	case has["task name"] && has["task id"]:
+		return "ClickUp"
+	case has["name"] && has["section/column"]:
+		return "Asana"
+	}
+	return ""
+}
+
+// isTrackerCSV reports whether the CSV file at path is a ClickUp or
+// Asana export
+func isTrackerCSV(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header, err := csv.NewReader(f).Read()
+	return err == nil && trackerSource(header) != ""
+}
+
+// guessColumns maps each field onto the first column named like it, -1
+// for none
+func guessColumns(header []string) map[string]int {
+	cols := map[string]int{}
+	for _, f := range trackerFields {
+		cols[f.Name] = -1
+	alias:
+		for _, alias := range f.Aliases {
+			for i, name := range header {
+				if strings.ToLower(strings.TrimSpace(name)) == alias {
+					cols[f.Name] = i
+					break alias
+				}
+			}
+		}
+	}
+	return cols
+}
+
+// promptColumns shows the guessed mapping and asks for each field which
+// column to take it from: enter keeps the guess, a number picks another
+// column and - leaves the field out
+func promptColumns(header []string, cols map[string]int, in *bufio.Reader) error {
+	fmt.Println("columns:")
+	for i, name := range header {
+		fmt.Printf("  %2d  %s\n", i+1, name)
+	}
+	fmt.Println("pick the column for each field (enter keeps it, - for none):")
+	for _, f := range trackerFields {
+		for {
+			guess := "-"
+			if i := cols[f.Name]; i >= 0 {
+				guess = fmt.Sprintf("%d %s", i+1, header[i])
+			}
+			fmt.Printf("  %-11s [%s] ", f.Name, guess)
+			answer, err := in.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if answer == "" {
+				if err != nil && err != io.EOF {
+					return err
+				}
+				break
+			}
+			if answer == "-" {
+				cols[f.Name] = -1
+				break
+			}
+			n, convErr := strconv.Atoi(answer)
+			if convErr == nil && n >= 1 && n <= len(header) {
+				cols[f.Name] = n - 1
+				break
+			}
+			fmt.Printf("  no column %q\n", answer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if cols["title"] < 0 {
+		return fmt.Errorf("no column for the title")
+	}
+	return nil
+}
+
+// trackerDone reports whether a status means the task is done. Asana has
+// no status, only the date a task was completed.
+func trackerDone(status string) bool {
+	switch strings.ToLower(status) {
+	case "complete", "completed", "closed", "done", "resolved", "shipped":
+		return true
+	}
+	_, err := parseTrackerDate(status)
+	return err == nil
+}
+
+// trackerPriority reads a priority as a level name, a ClickUp flag
+// (urgent, high, normal, low or 1 to 4) or an Asana custom field
+func trackerPriority(s string) Priority {
+	s = strings.TrimSpace(s)
+	if p, ok := parsePriorityName(s); ok {
+		return p
+	}
+	switch strings.ToLower(s) {
+	case "urgent", "1", "highest", "critical":
+		return fromICalPriority(1)
+	case "high", "2":
+		return fromICalPriority(3)
+	case "low", "4", "lowest":
+		return fromICalPriority(9)
+	}
+	return fromICalPriority(5)
+}
+
+// ordinalSuffix matches the "th" of "October 16th"
+var ordinalSuffix = regexp.MustCompile(`(\d)(st|nd|rd|th)\b`)
+
+// trackerDateFormats are the dates ClickUp and Asana write, past those of
+// Reminders
+var trackerDateFormats = []string{
+	"Monday, January 2 2006",
+	"Monday, January 2 2006, 3:04:05 pm",
+	"January 2 2006",
+	"01/02/2006, 3:04:05 PM",
+	"01/02/2006",
+}
+
+func parseTrackerDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil && ms > 1e11 {
+		// ClickUp's plain date columns are Unix milliseconds
+		return time.UnixMilli(ms).Local(), nil
+	}
+	if t, err := parseRemindersDate(s); err == nil {
+		return t, nil
+	}
+	s = ordinalSuffix.ReplaceAllString(s, "$1")
+	for _, layout := range trackerDateFormats {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date %q", s)
+}
+
+// trackerTasks reads the tasks of a tracker CSV with cols mapping fields
+// onto columns. Rows without a title are skipped.
+func trackerTasks(records [][]string, cols map[string]int, now time.Time) []Task {
+	var tasks []Task
+	for n, record := range records {
+		field := func(name string) string {
+			i := cols[name]
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		title := field("title")
+		if title == "" {
+			continue
+		}
+		task := Task{
+			ID:          generateID(),
+			Title:       title,
+			Description: field("description"),
+			Priority:    trackerPriority(field("priority")),
+			Project:     field("project"),
+			CreatedAt:   now,
+		}
+		// ClickUp lists assignees as [alice, bob], Asana has one
+		assignees := strings.Trim(field("assignee"), "[]")
+		if name, _, _ := strings.Cut(assignees, ","); name != "" {
+			task.Assignee = strings.TrimSpace(name)
+		}
+		if s := field("due"); s != "" {
+			if due, err := parseTrackerDate(s); err == nil {
+				task.Due = &due
+			} else {
+				fmt.Fprintf(os.Stderr, "line %d: %v\n", n+2, err)
+			}
+		}
+		if created, err := parseTrackerDate(field("created")); err == nil {
+			task.CreatedAt = created
+		}
+		var tags []string
+		for _, tag := range strings.Split(strings.Trim(field("tags"), "[]"), ",") {
+			if tag = strings.Join(strings.Fields(tag), "-"); tag != "" {
+				tags = append(tags, "@"+tag)
+			}
+		}
+		if len(tags) > 0 {
+			task.Description = strings.TrimSpace(task.Description + "\n\n" + strings.Join(tags, " "))
+		}
+		if status := field("status"); status != "" && trackerDone(status) {
+			setCompleted(&task, true)
+			if at, err := parseTrackerDate(status); err == nil {
+				task.CompletedAt = &at
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// importTrackerCSV adds the tasks of a ClickUp or Asana CSV export to the
+// board at path. On a terminal the column mapping is confirmed first,
+// unless yes is set.
+func importTrackerCSV(cfg Config, path, file, project string, yes, dryRun bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("%s is empty", file)
+	}
+
+	header := records[0]
+	cols := guessColumns(header)
+	if source := trackerSource(header); source != "" {
+		fmt.Printf("%s looks like an export from %s\n", file, source)
+	}
+	if !yes && term.IsTerminal(os.Stdin.Fd()) {
+		if err := promptColumns(header, cols, bufio.NewReader(os.Stdin)); err != nil {
+			return err
+		}
+	} else if cols["title"] < 0 {
+		return fmt.Errorf("%s: no title column in the header", file)
+	}
+	return addImported(cfg, path, trackerTasks(records[1:], cols, time.Now()), project, dryRun)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const triagePrompt = `You triage a task list. For every numbered task, pick a priority from the given levels and, if one clearly fits, a project from the given projects (or a short new one). Reply with a JSON array only, one object per task: [{"n": 1, "priority": "...", "project": "..."}]. Leave "project" empty when unsure.`
+
+// triageSuggestion is the model's proposal for one task
+type triageSuggestion struct {
+	N        int    `json:"n"`
+	Priority string `json:"priority"`
+	Project  string `json:"project"`
+}
+
+// isUntriaged reports whether task still needs sorting: open, on the
+// board and not filed under a project
+func isUntriaged(task Task) bool {
+	return !task.Completed && !offBoard(task) && task.Project == ""
+}
+
+// parsePriorityName finds the level called name, case-insensitively
+func parsePriorityName(name string) (Priority, bool) {
+	for _, p := range allPriorities() {
+		if strings.EqualFold(strings.TrimSpace(name), p.String()) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// parseTriageReply reads the suggestions out of a reply, tolerating the
+// code fences some models wrap JSON in
+func parseTriageReply(reply string) ([]triageSuggestion, error) {
+	start, end := strings.Index(reply, "["), strings.LastIndex(reply, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("the model did not reply with a JSON array")
+	}
+	var suggestions []triageSuggestion
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &suggestions); err != nil {
+		return nil, fmt.Errorf("the model's reply is not valid: %w", err)
+	}
+	return suggestions, nil
+}
+
+func cmdTriage(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("triage", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	all := fs.Bool("all", false, "triage every open task, not just those without a project")
+	yes := fs.Bool("yes", false, "accept every suggestion without asking")
+	fs.Parse(args)
+
+	client := newLLMClient(cfg)
+	if client == nil {
+		return fmt.Errorf("triage needs an \"llm\" block with an api_key in %s", getConfigPath())
+	}
+
+	path := resolveBoardPath(*global, *local)
+	taskList, err := loadBoard(path)
+	if err != nil {
+		return err
+	}
+	before := cloneTasks(taskList.Tasks)
+
+	var pending []int // indexes into taskList.Tasks
+	for i, task := range taskList.Tasks {
+		if isUntriaged(task) || (*all && !task.Completed && !offBoard(task)) {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Println("nothing to triage")
+		return nil
+	}
+
+	var levels, projects []string
+	for _, p := range allPriorities() {
+		levels = append(levels, p.String())
+	}
+	for _, s := range summarizeProjects(taskList.Tasks) {
+		if s.Name != "" {
+			projects = append(projects, s.Name)
+		}
+	}
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Priority levels, lowest first: %s\n", strings.Join(levels, ", "))
+	fmt.Fprintf(&prompt, "Projects: %s\n\n", strings.Join(projects, ", "))
+	for n, i := range pending {
+		fmt.Fprintf(&prompt, "%d. %s\n", n+1, taskList.Tasks[i].Title)
+	}
+
+	fmt.Fprintf(os.Stderr, "asking %s about %d task(s)...\n", client.model, len(pending))
+	reply, err := client.chat(triagePrompt, prompt.String())
+	if err != nil {
+		return err
+	}
+	suggestions, err := parseTriageReply(reply)
+	if err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	accepted := 0
+review:
+	for _, s := range suggestions {
+		if s.N < 1 || s.N > len(pending) {
+			continue
+		}
+		task := &taskList.Tasks[pending[s.N-1]]
+		p, ok := parsePriorityName(s.Priority)
+		if !ok {
+			p = task.Priority
+		}
+		project := strings.TrimSpace(s.Project)
+		if project == "" {
+			project = task.Project
+		}
+		if p == task.Priority && project == task.Project {
+			continue
+		}
+
+		fmt.Printf("%-6s  %s\n", shortID(task.ID), task.Title)
+		if p != task.Priority {
+			fmt.Printf("        priority  %s -> %s\n", task.Priority, p)
+		}
+		if project != task.Project {
+			fmt.Printf("        project   %s -> %s\n", projectLabel(task.Project), project)
+		}
+
+		if !*yes {
+			fmt.Print("accept? [y/n/q] ")
+			answer, err := in.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			switch {
+			case answer == "q" || (err != nil && answer == ""):
+				break review
+			case answer != "y" && answer != "yes":
+				continue
+			}
+		}
+		task.Priority = p
+		task.Project = project
+		accepted++
+	}
+
+	if accepted == 0 {
+		fmt.Println("no changes")
+		return nil
+	}
+	touchTasks(before, taskList.Tasks, time.Now())
+	if err := saveBoard(path, taskList); err != nil {
+		return err
+	}
+	afterSave(cfg, path, before, taskList.Tasks)
+	fmt.Printf("updated %d task(s) in %s\n", accepted, path)
+	return nil
+}
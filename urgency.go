@@ -0,0 +1,38 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// urgencyScore ranks how pressing an open task is: its priority, the
+// urgent flag, how close its due date is and how long it has been
+// waiting. Tasks have no blockers yet, so those do not count.
+func urgencyScore(task Task, now time.Time) float64 {
+	if task.Completed {
+		return 0
+	}
+	score := 6 * float64(clampPriority(task.Priority)+1) / float64(numPriorities())
+	if task.Urgent {
+		score += 4
+	}
+	if isEscalated(task) {
+		score += 1
+	}
+	if task.Due != nil {
+		// Up to five points as the due date nears, all of them once overdue
+		days := task.Due.Sub(now).Hours() / 24
+		score += 5 * min(max(1-days/14, 0), 1)
+	}
+	// Half a point per week waiting, capped at two points
+	weeks := now.Sub(task.CreatedAt).Hours() / (24 * 7)
+	score += min(max(weeks, 0)/2, 2)
+	return score
+}
+
+// sortByUrgency orders tasks most urgent first. Ties keep their order.
+func sortByUrgency(tasks []Task, now time.Time) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return urgencyScore(tasks[i], now) > urgencyScore(tasks[j], now)
+	})
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// boardSchema is the published JSON Schema of a board file
+//
+//go:embed schema/board.schema.json
+var boardSchema []byte
+
+// schemaNode is the subset of JSON Schema the board schema uses
+type schemaNode struct {
+	Ref        string                 `json:"$ref"`
+	Type       schemaType             `json:"type"`
+	Required   []string               `json:"required"`
+	Properties map[string]*schemaNode `json:"properties"`
+	Items      *schemaNode            `json:"items"`
+	Minimum    *float64               `json:"minimum"`
+	Maximum    *float64               `json:"maximum"`
+	MinLength  *int                   `json:"minLength"`
+	Format     string                 `json:"format"`
+	Defs       map[string]*schemaNode `json:"$defs"`
+}
+
+// schemaType accepts both "type": "x" and "type": ["x", "y"]
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = schemaType{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// validationError is one place where a board breaks the schema
+type validationError struct {
+	Path string // JSON path such as $.tasks[2].priority
+	Msg  string
+}
+
+func (e validationError) Error() string {
+	return e.Path + ": " + e.Msg
+}
+
+// schemaValidator checks decoded JSON against a schema
+type schemaValidator struct {
+	root *schemaNode
+	errs []validationError
+}
+
+func (v *schemaValidator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, validationError{Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+// resolve follows a local "#/$defs/name" reference
+func (v *schemaValidator) resolve(s *schemaNode) *schemaNode {
+	if s.Ref == "" {
+		return s
+	}
+	if def, ok := v.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]; ok {
+		return def
+	}
+	return s
+}
+
+func jsonType(value any) string {
+	switch x := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
+
+func (v *schemaValidator) check(s *schemaNode, value any, path string) {
+	s = v.resolve(s)
+
+	if len(s.Type) > 0 {
+		actual := jsonType(value)
+		ok := false
+		for _, want := range s.Type {
+			if want == actual || (want == "number" && actual == "integer") {
+				ok = true
+			}
+		}
+		if !ok {
+			v.fail(path, "expected %s, got %s", strings.Join(s.Type, " or "), actual)
+			return
+		}
+	}
+
+	switch x := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := x[name]; !ok {
+				v.fail(path, "missing required field %q", name)
+			}
+		}
+		names := make([]string, 0, len(x))
+		for name := range x {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := s.Properties[name]; ok {
+				v.check(prop, x[name], path+"."+name)
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range x {
+				v.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case json.Number:
+		n, _ := x.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			v.fail(path, "%s is below the minimum of %v", x, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			v.fail(path, "%s is above the maximum of %v", x, *s.Maximum)
+		}
+	case string:
+		if s.MinLength != nil && len(x) < *s.MinLength {
+			v.fail(path, "must not be empty")
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, x); err != nil {
+				v.fail(path, "%q is not an RFC 3339 date-time", x)
+			}
+		}
+	}
+}
+
+// validateBoard checks a board file's contents against the schema, then
+// against what the schema cannot express: unique IDs and the priority
+// range of the current configuration
+func validateBoard(data []byte) ([]validationError, error) {
+	var root schemaNode
+	if err := json.Unmarshal(boardSchema, &root); err != nil {
+		return nil, fmt.Errorf("bad built-in schema: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return []validationError{{Path: "$", Msg: newCorruptBoardError("", data, err).lineMsg()}}, nil
+	}
+
+	v := &schemaValidator{root: &root}
+	v.check(&root, doc, "$")
+
+	obj, _ := doc.(map[string]any)
+	seen := map[string]string{}
+	for _, list := range []string{"tasks", "archive"} {
+		items, _ := obj[list].([]any)
+		for i, item := range items {
+			task, _ := item.(map[string]any)
+			path := fmt.Sprintf("$.%s[%d]", list, i)
+			if id, ok := task["id"].(string); ok && id != "" {
+				if first, dup := seen[id]; dup {
+					v.fail(path+".id", "%q is already used by %s", id, first)
+				} else {
+					seen[id] = path
+				}
+			}
+			if p, ok := task["priority"].(json.Number); ok {
+				if n, err := p.Int64(); err == nil && n > int64(maxPriority()) {
+					v.fail(path+".priority", "%d is above the highest configured level %d (%s)", n, maxPriority(), maxPriority())
+				}
+			}
+		}
+	}
+	return v.errs, nil
+}
+
+func cmdValidate(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	printSchema := fs.Bool("schema", false, "print the board JSON Schema and exit")
+	fs.Parse(args)
+
+	if *printSchema {
+		_, err := os.Stdout.Write(boardSchema)
+		return err
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{resolveBoardPath(false, false)}
+	}
+
+	invalid := 0
+	for _, file := range files {
+		data, err := readBoardJSON(file)
+		if err != nil {
+			var corrupt *corruptBoardError
+			if !errors.As(err, &corrupt) {
+				return err
+			}
+			invalid++
+			fmt.Printf("%s: %v\n", file, corrupt.Err)
+			continue
+		}
+		errs, err := validateBoard(data)
+		if err != nil {
+			return err
+		}
+		if len(errs) == 0 {
+			fmt.Printf("%s: ok\n", file)
+			continue
+		}
+		invalid++
+		for _, e := range errs {
+			fmt.Printf("%s: %s\n", file, e)
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d file(s) failed validation", invalid, len(files))
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Sensitive tasks keep their description sealed on disk with AES-256-GCM,
+// under a key derived from a passphrase with PBKDF2. Only the description
+// is sealed, so titles, columns and dates stay readable and boards merge
+// as before. A sealed description reads
+//
+//	basket:sealed:v1:<base64 of the salt, the nonce and the ciphertext>
+const sealedPrefix = "basket:sealed:v1:"
+
+const (
+	sealSaltSize   = 16
+	sealIterations = 600_000
+)
+
+var errWrongPassphrase = errors.New("wrong passphrase")
+
+// isSealed reports whether a description is sealed
+func isSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
+
+func sealKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	k, err := pbkdf2.Key(sha256.New, passphrase, salt, sealIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealText encrypts text under passphrase, with a fresh salt each time
+func sealText(passphrase, text string) (string, error) {
+	salt := make([]byte, sealSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	aead, err := sealKey(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	return sealWith(aead, salt, []byte(text))
+}
+
+// sealWith encrypts data under a key already derived from salt
+func sealWith(aead cipher.AEAD, salt, data []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out := append(append([]byte{}, salt...), nonce...)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(aead.Seal(out, nonce, data, nil)), nil
+}
+
+// openText decrypts what sealText sealed
+func openText(passphrase, sealed string) (string, error) {
+	salt, err := sealedSalt(sealed)
+	if err != nil {
+		return "", err
+	}
+	aead, err := sealKey(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	text, err := openWith(aead, sealed)
+	return string(text), err
+}
+
+// sealedSalt is the salt the key of sealed text was derived with
+func sealedSalt(sealed string) ([]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil || len(data) < sealSaltSize {
+		return nil, fmt.Errorf("not a sealed description")
+	}
+	return data[:sealSaltSize], nil
+}
+
+// openWith decrypts sealed text with a key already derived from its salt
+func openWith(aead cipher.AEAD, sealed string) ([]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil || len(data) < sealSaltSize+aead.NonceSize() {
+		return nil, fmt.Errorf("not a sealed description")
+	}
+	data = data[sealSaltSize:]
+	text, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return text, nil
+}
+
+// vault holds the passphrase for the session and the descriptions it has
+// opened, as deriving a key takes a moment
+type vault struct {
+	passphrase string
+	opened     map[string]string // plain text by sealed text
+}
+
+// newVault starts locked, unless BASKET_PASSPHRASE is set
+func newVault() *vault {
+	return &vault{passphrase: os.Getenv("BASKET_PASSPHRASE"), opened: map[string]string{}}
+}
+
+func (v *vault) unlocked() bool {
+	return v != nil && v.passphrase != ""
+}
+
+func (v *vault) open(sealed string) (string, error) {
+	if text, ok := v.opened[sealed]; ok {
+		return text, nil
+	}
+	text, err := openText(v.passphrase, sealed)
+	if err != nil {
+		return "", err
+	}
+	v.opened[sealed] = text
+	return text, nil
+}
+
+func (v *vault) seal(text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	sealed, err := sealText(v.passphrase, text)
+	if err == nil {
+		v.opened[sealed] = text
+	}
+	return sealed, err
+}
+
+// description is what to show of a task's description: a sealed one is
+// opened while the vault is unlocked and stands as a lock otherwise
+func (m model) description(task Task) string {
+	if !isSealed(task.Description) {
+		return task.Description
+	}
+	if m.vault.unlocked() {
+		if text, err := m.vault.open(task.Description); err == nil {
+			return text
+		}
+	}
+	return T("sensitive.locked")
+}
+
+// unlockThen runs action on the task once the vault is unlocked, asking
+// for the passphrase first if need be
+func (m *model) unlockThen(action, id string) tea.Cmd {
+	if m.vault.unlocked() {
+		return m.runUnlocked(action, id)
+	}
+	m.mode = ViewPassphrase
+	m.unlockAction, m.unlockTask = action, id
+	m.inputErr = ""
+	m.input.Reset()
+	m.input.Placeholder = T("passphrase.placeholder")
+	m.input.EchoMode = textinput.EchoPassword
+	return m.input.Focus()
+}
+
+func (m *model) runUnlocked(action, id string) tea.Cmd {
+	switch action {
+	case "seal":
+		m.toggleSensitive(id)
+	case "edit":
+		return m.editTask(id)
+	}
+	return nil
+}
+
+// toggleSensitive seals the task's description, or opens it for good
+func (m *model) toggleSensitive(id string) {
+	for i := range m.tasks {
+		task := &m.tasks[i]
+		if task.ID != id {
+			continue
+		}
+		if task.Sensitive {
+			if isSealed(task.Description) {
+				text, err := m.vault.open(task.Description)
+				if err != nil {
+					m.fail(err)
+					return
+				}
+				task.Description = text
+			}
+			task.Sensitive = false
+			m.commit(T("status.unsealed"))
+			return
+		}
+		sealed, err := m.vault.seal(task.Description)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		task.Description = sealed
+		task.Sensitive = true
+		m.commit(T("status.sealed"))
+		return
+	}
+}
+
+func (m model) updatePassphrase(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		if m.unlockAction == "board" {
+			// There is nothing to show without the board
+			return m, tea.Quit
+		}
+		m.mode = ViewBoard
+		m.input.EchoMode = textinput.EchoNormal
+		return m, nil
+
+	case key.Matches(msg, m.keys.Confirm):
+		pass := m.input.Value()
+		if pass == "" {
+			return m, nil
+		}
+		if m.unlockAction == "board" {
+			if err := unlockBoard(m.lockedPath, pass); err != nil {
+				m.inputErr = T("passphrase.board_wrong")
+				m.input.Reset()
+				return m, nil
+			}
+			m.mode = ViewBoard
+			m.unlockAction = ""
+			m.input.EchoMode = textinput.EchoNormal
+			m.input.Reset()
+			m.openUnlocked()
+			return m, nil
+		}
+		// Check it against a description sealed before, so one board
+		// never ends up under two passphrases
+		m.vault.passphrase = pass
+		for _, task := range m.tasks {
+			if !isSealed(task.Description) {
+				continue
+			}
+			if _, err := m.vault.open(task.Description); err != nil {
+				m.vault.passphrase = ""
+				m.inputErr = T("passphrase.wrong")
+				m.input.Reset()
+				return m, nil
+			}
+			break
+		}
+		m.mode = ViewBoard
+		m.input.EchoMode = textinput.EchoNormal
+		m.input.Reset()
+		return m, m.runUnlocked(m.unlockAction, m.unlockTask)
+	}
+
+	m.inputErr = ""
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m model) viewPassphrase() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAccent).
+		Render(T("passphrase.title"))
+	hint := T("passphrase.hint")
+	if m.unlockAction == "board" {
+		title += helpStyle.Render("  " + m.lockedPath)
+		hint = T("passphrase.board_hint")
+	}
+
+	errLine := helpStyle.Render(hint)
+	if m.inputErr != "" {
+		errLine = lipgloss.NewStyle().
+			Foreground(colorError).
+			Render(m.inputErr)
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n%s\n\n%s", title, m.input.View(), errLine, m.renderFooter())
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Webhook posts task events to a URL. The body is rendered from a Go
+// template, so one config can speak to Discord, Mattermost or IFTTT:
+//
+//	{"url": "https://discord.com/api/webhooks/...", "events": ["complete"],
+//	 "template": "{\"content\": {{json (print \"Done: \" .Title)}}}"}
+type Webhook struct {
+	URL         string            `json:"url"`
+	Events      []string          `json:"events"`       // add, complete, delete; empty for all
+	Template    string            `json:"template"`     // unset posts the task as JSON
+	ContentType string            `json:"content_type"` // application/json unless set
+	Headers     map[string]string `json:"headers"`
+}
+
+// webhookData is what a webhook template sees: the task's fields, plus
+// the event and the board
+type webhookData struct {
+	Task
+	Event string
+	Board string
+}
+
+var webhookFuncs = template.FuncMap{
+	// json quotes a value for use inside a JSON body
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"tags": taskContexts,
+	"join": strings.Join,
+}
+
+var webhookClient = &http.Client{Timeout: 15 * time.Second}
+
+// wants reports whether the webhook is for event
+func (h Webhook) wants(event string) bool {
+	return len(h.Events) == 0 || slices.Contains(h.Events, event)
+}
+
+// body renders the webhook's payload for ev
+func (h Webhook) body(ev taskEvent) ([]byte, error) {
+	if h.Template == "" {
+		return json.Marshal(ev.Task)
+	}
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Option("missingkey=error").Parse(h.Template)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, webhookData{Task: ev.Task, Event: ev.Name, Board: ev.Board}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// runWebhook posts ev to the webhook if it is for that event
+func runWebhook(h Webhook, ev taskEvent) error {
+	if !h.wants(ev.Name) {
+		return nil
+	}
+	body, err := h.body(ev)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %v", h.URL, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook %s: %v", h.URL, err)
+	}
+	contentType := h.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range h.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %v", h.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("webhook %s: %s %s", h.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// weekColumns returns the week planner's columns for the week holding now:
+// the open tasks not planned this week, then one column per day from
+// Monday. A task planned for an earlier week and still open counts as
+// unplanned again; tasks planned for later weeks stay out of sight.
+func (m model) weekColumns(now time.Time) [8][]Task {
+	var cols [8][]Task
+	monday := startOfWeek(now)
+	for p := maxPriority(); p >= 0; p-- {
+		for _, task := range m.getTasksInColumn(p) {
+			if task.PlannedOn == nil {
+				if !task.Completed {
+					cols[0] = append(cols[0], task)
+				}
+				continue
+			}
+			day := startOfDay(task.PlannedOn.Local())
+			if day.Before(monday) {
+				if !task.Completed {
+					cols[0] = append(cols[0], task)
+				}
+				continue
+			}
+			for d := 0; d < 7; d++ {
+				if day.Equal(monday.AddDate(0, 0, d)) {
+					cols[d+1] = append(cols[d+1], task)
+				}
+			}
+		}
+	}
+	return cols
+}
+
+func (m *model) openWeek() {
+	m.mode = ViewWeek
+	m.weekCol = (int(time.Now().Weekday())+6)%7 + 1
+	m.weekRow = 0
+}
+
+// weekTask is the task under the planner's cursor
+func (m model) weekTask() (Task, bool) {
+	cols := m.weekColumns(time.Now())
+	if m.weekRow >= len(cols[m.weekCol]) {
+		return Task{}, false
+	}
+	return cols[m.weekCol][m.weekRow], true
+}
+
+// planTask moves the task under the cursor by delta days. Going before
+// Monday unplans it, and the cursor follows the task.
+func (m *model) planTask(delta int) {
+	task, ok := m.weekTask()
+	if !ok {
+		return
+	}
+	col := min(max(m.weekCol+delta, 0), 7)
+	if col == m.weekCol {
+		return
+	}
+	for i := range m.tasks {
+		if m.tasks[i].ID != task.ID {
+			continue
+		}
+		if col == 0 {
+			m.tasks[i].PlannedOn = nil
+			m.commit(T("status.unplanned"))
+		} else {
+			day := startOfWeek(time.Now()).AddDate(0, 0, col-1)
+			m.tasks[i].PlannedOn = &day
+			m.commit(fmt.Sprintf(T("status.planned"), weekdayName(col-1)))
+		}
+		break
+	}
+	m.weekCol = col
+	for i, t := range m.weekColumns(time.Now())[col] {
+		if t.ID == task.ID {
+			m.weekRow = i
+		}
+	}
+}
+
+// weekdayName is the localized short name of a day, Monday being 0
+func weekdayName(i int) string {
+	names := strings.Fields(T("week.days"))
+	if i < 0 || i >= len(names) {
+		return ""
+	}
+	return names[i]
+}
+
+func (m model) updateWeek(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cols := m.weekColumns(time.Now())
+
+	switch {
+	case key.Matches(msg, m.keys.Cancel), key.Matches(msg, m.keys.Week):
+		m.mode = ViewBoard
+
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Left):
+		if m.weekCol > 0 {
+			m.weekCol--
+			m.weekRow = min(m.weekRow, max(len(cols[m.weekCol])-1, 0))
+		}
+
+	case key.Matches(msg, m.keys.Right):
+		if m.weekCol < 7 {
+			m.weekCol++
+			m.weekRow = min(m.weekRow, max(len(cols[m.weekCol])-1, 0))
+		}
+
+	case key.Matches(msg, m.keys.Up):
+		if m.weekRow > 0 {
+			m.weekRow--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.weekRow < len(cols[m.weekCol])-1 {
+			m.weekRow++
+		}
+
+	case key.Matches(msg, m.keys.Earlier):
+		m.planTask(-1)
+
+	case key.Matches(msg, m.keys.Later):
+		m.planTask(1)
+
+	case key.Matches(msg, m.keys.Toggle):
+		task, ok := m.weekTask()
+		if !ok {
+			break
+		}
+		for i := range m.tasks {
+			if m.tasks[i].ID == task.ID {
+				setCompleted(&m.tasks[i], !m.tasks[i].Completed)
+				if m.tasks[i].Completed {
+					m.commit(T("status.completed"))
+				} else {
+					m.commit(T("status.reopened"))
+				}
+				break
+			}
+		}
+		m.weekRow = min(m.weekRow, max(len(m.weekColumns(time.Now())[m.weekCol])-1, 0))
+	}
+
+	return m, nil
+}
+
+func (m model) viewWeek() string {
+	now := time.Now()
+	monday := startOfWeek(now)
+	cols := m.weekColumns(now)
+
+	width := 16
+	if m.width > 0 {
+		width = max(m.width/8-3, 10)
+	}
+	height := 10
+	if m.height > 0 {
+		height = max(m.height-10, 4)
+	}
+	today := (int(now.Weekday())+6)%7 + 1
+
+	var rendered []string
+	for c, tasks := range cols {
+		title := T("week.unplanned")
+		color := colorDone
+		if c > 0 {
+			title = fmt.Sprintf("%s %s", weekdayName(c-1), monday.AddDate(0, 0, c-1).Format(T("week.date")))
+			color = colorInfo
+		}
+		if c == today {
+			color = colorSuccess
+		}
+
+		var b strings.Builder
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(color).Render(title) + "\n")
+		for i, task := range tasks {
+			if i == height {
+				b.WriteString(helpStyle.Render(fmt.Sprintf(T("matrix.more"), len(tasks)-height)) + "\n")
+				break
+			}
+			dot := lipgloss.NewStyle().Foreground(task.Priority.Color()).Render("●")
+			line := truncate(task.Title, width-2)
+			switch {
+			case c == m.weekCol && i == m.weekRow:
+				line = lipgloss.NewStyle().Bold(true).Foreground(colorAccent).Render(line)
+			case task.Completed:
+				line = helpStyle.Strikethrough(true).Render(line)
+			}
+			b.WriteString(dot + " " + line + "\n")
+		}
+
+		border := colorBorder
+		if c == m.weekCol {
+			border = color
+		}
+		rendered = append(rendered, lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(border).
+			Width(width).
+			Height(height+1).
+			Render(b.String()))
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf(T("week.title"), monday.Format(T("week.date")))) + "\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, rendered...) + "\n")
+	b.WriteString(m.renderFooter())
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The platform and the lookups basket's paths are built from, swapped
+// out by the tests to check the paths of other systems
+var (
+	goos          = runtime.GOOS
+	getenv        = os.Getenv
+	userHomeDir   = os.UserHomeDir
+	userConfigDir = os.UserConfigDir
+)
+
+// migrateWindowsStorage moves what older versions kept in the profile
+// folder on Windows to where basket keeps it now: the global board from
+// %USERPROFILE%\basket-tasks.json to %APPDATA%\basket, and the data
+// directory from %USERPROFILE%\.local\share\basket to %LOCALAPPDATA%\basket.
+// Nothing is moved over files already in the new place.
+func migrateWindowsStorage() {
+	if goos != "windows" {
+		return
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return
+	}
+	oldBase := filepath.Join(home, "basket-tasks")
+	if base, ok := globalBoardBase(); ok && base != oldBase {
+		if _, err := os.Stat(getGlobalTasksPath()); os.IsNotExist(err) {
+			old := findBoard(oldBase)
+			if err := moveStorage(old, base+strings.TrimPrefix(old, oldBase)); err != nil {
+				logger.Error("migrate storage", "from", old, "err", err)
+			}
+		}
+	}
+	if getenv("XDG_DATA_HOME") == "" {
+		old := filepath.Join(home, ".local", "share", "basket")
+		if err := moveStorage(old, getDataDir()); err != nil {
+			logger.Error("migrate storage", "from", old, "err", err)
+		}
+	}
+}
+
+// moveStorage moves the file or directory at old to path, unless old is
+// missing or path already exists
+func moveStorage(old, path string) error {
+	if _, err := os.Stat(old); err != nil {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(old, path); err != nil {
+		return err
+	}
+	logger.Info("migrate storage", "from", old, "to", path)
+	return nil
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakePlatform makes basket's paths those of goos, with home as the
+// profile folder and env as the environment, until the test ends
+func fakePlatform(t *testing.T, system, home string, env map[string]string) {
+	t.Helper()
+	oldGOOS, oldGetenv, oldHome, oldConfig := goos, getenv, userHomeDir, userConfigDir
+	t.Cleanup(func() {
+		goos, getenv, userHomeDir, userConfigDir = oldGOOS, oldGetenv, oldHome, oldConfig
+	})
+	goos = system
+	getenv = func(key string) string { return env[key] }
+	userHomeDir = func() (string, error) {
+		if home == "" {
+			return "", errors.New("no home")
+		}
+		return home, nil
+	}
+	userConfigDir = func() (string, error) {
+		if system == "windows" {
+			if dir := env["APPDATA"]; dir != "" {
+				return dir, nil
+			}
+			return "", errors.New("no APPDATA")
+		}
+		if home == "" {
+			return "", errors.New("no home")
+		}
+		return filepath.Join(home, ".config"), nil
+	}
+}
+
+func TestStoragePaths(t *testing.T) {
+	home := filepath.Join("users", "ana")
+	appData := filepath.Join(home, "AppData", "Roaming")
+	localAppData := filepath.Join(home, "AppData", "Local")
+
+	tests := []struct {
+		name      string
+		goos      string
+		home      string
+		env       map[string]string
+		base      string // "" when there is none
+		dataDir   string
+		configDir string
+	}{
+		{
+			name:      "unix",
+			goos:      "linux",
+			home:      home,
+			base:      filepath.Join(home, "basket-tasks"),
+			dataDir:   filepath.Join(home, ".local", "share", "basket"),
+			configDir: filepath.Join(home, ".config", "basket"),
+		},
+		{
+			name:      "unix with XDG_DATA_HOME",
+			goos:      "linux",
+			home:      home,
+			env:       map[string]string{"XDG_DATA_HOME": "xdg"},
+			base:      filepath.Join(home, "basket-tasks"),
+			dataDir:   filepath.Join("xdg", "basket"),
+			configDir: filepath.Join(home, ".config", "basket"),
+		},
+		{
+			name:      "unix ignores LOCALAPPDATA",
+			goos:      "darwin",
+			home:      home,
+			env:       map[string]string{"LOCALAPPDATA": localAppData},
+			base:      filepath.Join(home, "basket-tasks"),
+			dataDir:   filepath.Join(home, ".local", "share", "basket"),
+			configDir: filepath.Join(home, ".config", "basket"),
+		},
+		{
+			name:      "unix without a home",
+			goos:      "linux",
+			dataDir:   ".basket",
+			configDir: ".basket",
+		},
+		{
+			name:      "windows",
+			goos:      "windows",
+			home:      home,
+			env:       map[string]string{"APPDATA": appData, "LOCALAPPDATA": localAppData},
+			base:      filepath.Join(appData, "basket", "basket-tasks"),
+			dataDir:   filepath.Join(localAppData, "basket"),
+			configDir: filepath.Join(appData, "basket"),
+		},
+		{
+			name:      "windows with XDG_DATA_HOME",
+			goos:      "windows",
+			home:      home,
+			env:       map[string]string{"APPDATA": appData, "LOCALAPPDATA": localAppData, "XDG_DATA_HOME": "xdg"},
+			base:      filepath.Join(appData, "basket", "basket-tasks"),
+			dataDir:   filepath.Join("xdg", "basket"),
+			configDir: filepath.Join(appData, "basket"),
+		},
+		{
+			name:      "windows without APPDATA",
+			goos:      "windows",
+			home:      home,
+			base:      filepath.Join(home, "basket-tasks"),
+			dataDir:   filepath.Join(home, ".local", "share", "basket"),
+			configDir: ".basket",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakePlatform(t, tt.goos, tt.home, tt.env)
+			base, ok := globalBoardBase()
+			if ok != (tt.base != "") || base != tt.base {
+				t.Errorf("globalBoardBase() = %q, %v; want %q", base, ok, tt.base)
+			}
+			if got := getDataDir(); got != tt.dataDir {
+				t.Errorf("getDataDir() = %q; want %q", got, tt.dataDir)
+			}
+			if got := getConfigDir(); got != tt.configDir {
+				t.Errorf("getConfigDir() = %q; want %q", got, tt.configDir)
+			}
+		})
+	}
+}
+
+// windowsProfile fakes a Windows profile folder under a temporary
+// directory, returning it
+func windowsProfile(t *testing.T) string {
+	home := t.TempDir()
+	fakePlatform(t, "windows", home, map[string]string{
+		"APPDATA":      filepath.Join(home, "AppData", "Roaming"),
+		"LOCALAPPDATA": filepath.Join(home, "AppData", "Local"),
+	})
+	return home
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func wantFile(t *testing.T, path, data string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return
+	}
+	if string(got) != data {
+		t.Errorf("%s = %q; want %q", path, got, data)
+	}
+}
+
+func wantMissing(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s still exists", path)
+	}
+}
+
+func TestMigrateWindowsStorage(t *testing.T) {
+	home := windowsProfile(t)
+	oldBoard := filepath.Join(home, "basket-tasks.yaml")
+	oldBackup := filepath.Join(home, ".local", "share", "basket", "backups", "global", "1.json")
+	writeFile(t, oldBoard, "tasks: []\n")
+	writeFile(t, oldBackup, "{}")
+
+	migrateWindowsStorage()
+
+	wantFile(t, filepath.Join(home, "AppData", "Roaming", "basket", "basket-tasks.yaml"), "tasks: []\n")
+	wantFile(t, filepath.Join(home, "AppData", "Local", "basket", "backups", "global", "1.json"), "{}")
+	wantMissing(t, oldBoard)
+	wantMissing(t, filepath.Join(home, ".local", "share", "basket"))
+	if got, want := getGlobalTasksPath(), filepath.Join(home, "AppData", "Roaming", "basket", "basket-tasks.yaml"); got != want {
+		t.Errorf("getGlobalTasksPath() = %q; want %q", got, want)
+	}
+}
+
+func TestMigrateWindowsStorageKeepsNewFiles(t *testing.T) {
+	home := windowsProfile(t)
+	oldBoard := filepath.Join(home, "basket-tasks.json")
+	newBoard := filepath.Join(home, "AppData", "Roaming", "basket", "basket-tasks.json")
+	oldState := filepath.Join(home, ".local", "share", "basket", "state.json")
+	newState := filepath.Join(home, "AppData", "Local", "basket", "state.json")
+	writeFile(t, oldBoard, "old")
+	writeFile(t, newBoard, "new")
+	writeFile(t, oldState, "old")
+	writeFile(t, newState, "new")
+
+	migrateWindowsStorage()
+
+	wantFile(t, newBoard, "new")
+	wantFile(t, oldBoard, "old")
+	wantFile(t, newState, "new")
+	wantFile(t, oldState, "old")
+}
+
+func TestMigrateStorageOnlyOnWindows(t *testing.T) {
+	home := t.TempDir()
+	fakePlatform(t, "linux", home, map[string]string{"APPDATA": filepath.Join(home, "AppData", "Roaming")})
+	oldBoard := filepath.Join(home, "basket-tasks.json")
+	writeFile(t, oldBoard, "old")
+
+	migrateWindowsStorage()
+
+	wantFile(t, oldBoard, "old")
+	wantMissing(t, filepath.Join(home, "AppData"))
+}
+
+func TestMoveStorage(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing", func(t *testing.T) {
+		if err := moveStorage(filepath.Join(dir, "none"), filepath.Join(dir, "to")); err != nil {
+			t.Fatal(err)
+		}
+		wantMissing(t, filepath.Join(dir, "to"))
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		old := filepath.Join(dir, "board.json")
+		writeFile(t, old, "old")
+		// The new place is under a file, so it cannot be created
+		blocker := filepath.Join(dir, "blocker")
+		writeFile(t, blocker, "")
+		if err := moveStorage(old, filepath.Join(blocker, "basket", "board.json")); err == nil {
+			t.Fatal("moveStorage succeeded; want an error")
+		}
+		wantFile(t, old, "old")
+	})
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Workspace is a named way to open basket: which board, filtered how and
+// shown in which layout
+type Workspace struct {
+	// Board is "global", "local" or the path of a board; empty keeps the
+	// board basket would open anyway
+	Board string `json:"board,omitempty"`
+
+	Query         string `json:"query,omitempty"`
+	Layout        string `json:"layout,omitempty"` // board, today, matrix, week or graph
+	SortByUrgency bool   `json:"sort_by_urgency,omitempty"`
+}
+
+var workspaceLayouts = []string{"board", "today", "matrix", "week", "graph"}
+
+// todayQuery is what the today layout shows: what is due, planned for
+// today or urgent
+const todayQuery = "open and (due<=today or planned:today or urgent)"
+
+// applyWorkspace opens ws in the running model
+func (m *model) applyWorkspace(ws Workspace) error {
+	switch ws.Board {
+	case "":
+	case "global":
+		m.showBoard(false)
+	case "local":
+		if !m.hasLocal {
+			return fmt.Errorf("there is no local board here")
+		}
+		m.showBoard(true)
+	default:
+		path := expandHome(ws.Board)
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+		m.switchLocal(path)
+	}
+
+	src := ws.Query
+	if ws.Layout == "today" {
+		src = todayQuery
+		if ws.Query != "" {
+			src = "(" + ws.Query + ") and " + todayQuery
+		}
+	}
+	query, err := parseQuery(src)
+	if err != nil {
+		return err
+	}
+	m.query = query
+	m.sortUrgency = ws.SortByUrgency || m.config.SortByUrgency
+
+	switch ws.Layout {
+	case "", "board", "today":
+	case "matrix":
+		m.mode = ViewMatrix
+	case "week":
+		m.openWeek()
+	case "graph":
+		m.openGraph()
+	default:
+		return fmt.Errorf("unknown layout %q, use one of %s", ws.Layout, strings.Join(workspaceLayouts, ", "))
+	}
+	return nil
+}
+
+// expandHome resolves a leading ~/ to the home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// cmdWorkspace opens a workspace, or lists, saves or deletes them:
+//
+//	basket workspace oncall
+//	basket workspace save oncall --board ~/work/.basket.json --query 'tag:ops' --layout matrix --urgency
+func cmdWorkspace(cfg Config, args []string) error {
+	if len(args) == 0 {
+		return listWorkspaces(cfg)
+	}
+	switch args[0] {
+	case "save":
+		return saveWorkspace(cfg, args[1:])
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: basket workspace delete <name>")
+		}
+		if _, ok := cfg.Workspaces[args[1]]; !ok {
+			return fmt.Errorf("no workspace %q", args[1])
+		}
+		delete(cfg.Workspaces, args[1])
+		return setConfigValue("workspaces", cfg.Workspaces)
+	}
+
+	ws, ok := cfg.Workspaces[args[0]]
+	if !ok {
+		return fmt.Errorf("no workspace %q (see basket workspace)", args[0])
+	}
+	m := initialModel(cfg)
+	if err := m.applyStartup(startupOptions{Workspace: ws, Name: args[0]}); err != nil {
+		return fmt.Errorf("workspace %s: %w", args[0], err)
+	}
+	return runTUI(m)
+}
+
+func listWorkspaces(cfg Config) error {
+	if len(cfg.Workspaces) == 0 {
+		fmt.Println("no workspaces yet, add one with basket workspace save <name>")
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Workspaces))
+	for name := range cfg.Workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ws := cfg.Workspaces[name]
+		var parts []string
+		if ws.Board != "" {
+			parts = append(parts, ws.Board)
+		}
+		if ws.Layout != "" {
+			parts = append(parts, ws.Layout)
+		}
+		if ws.SortByUrgency {
+			parts = append(parts, "by urgency")
+		}
+		if ws.Query != "" {
+			parts = append(parts, fmt.Sprintf("%q", ws.Query))
+		}
+		fmt.Printf("%-12s  %s\n", name, strings.Join(parts, ", "))
+	}
+	return nil
+}
+
+func saveWorkspace(cfg Config, args []string) error {
+	fs := flag.NewFlagSet("workspace save", flag.ExitOnError)
+	global, local := boardFlags(fs)
+	board := fs.String("board", "", "path of the board to open")
+	query := fs.String("query", "", "filter the board with a query")
+	layout := fs.String("layout", "", strings.Join(workspaceLayouts, ", "))
+	urgency := fs.Bool("urgency", false, "sort columns by urgency")
+	names := parseInterspersed(fs, args)
+	if len(names) != 1 {
+		return fmt.Errorf("usage: basket workspace save <name> [--global|--local|--board path] [--query q] [--layout name] [--urgency]")
+	}
+
+	ws := Workspace{Query: *query, Layout: *layout, SortByUrgency: *urgency}
+	switch {
+	case *board != "":
+		path, err := filepath.Abs(expandHome(*board))
+		if err != nil {
+			return err
+		}
+		board, err := loadBoard(path)
+		if err != nil {
+			return err
+		}
+		useColumns(board.Columns)
+		ws.Board = path
+	case *global:
+		ws.Board = "global"
+	case *local:
+		ws.Board = "local"
+	}
+	if ws.Board == "global" || ws.Board == "local" || ws.Board == "" {
+		resolveBoardPath(*global, *local) // for the board's column names
+	}
+	if _, err := parseQuery(ws.Query); err != nil {
+		return pointAtQueryError(ws.Query, err)
+	}
+	if ws.Layout != "" && !slices.Contains(workspaceLayouts, ws.Layout) {
+		return fmt.Errorf("unknown layout %q, use one of %s", ws.Layout, strings.Join(workspaceLayouts, ", "))
+	}
+
+	if cfg.Workspaces == nil {
+		cfg.Workspaces = map[string]Workspace{}
+	}
+	cfg.Workspaces[names[0]] = ws
+	if err := setConfigValue("workspaces", cfg.Workspaces); err != nil {
+		return err
+	}
+	fmt.Printf("saved workspace %s, open it with basket workspace %s\n", names[0], names[0])
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlFormat stores the whole board in one YAML document, for boards kept
+// under version control. A comment block at the top of the file is kept
+// across saves.
+type yamlFormat struct{}
+
+func (yamlFormat) read(path string) (TaskList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TaskList{}, err
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return TaskList{}, &corruptBoardError{Path: path, Err: err}
+	}
+	if doc == nil {
+		return TaskList{Tasks: []Task{}}, nil
+	}
+
+	// Round-trip through JSON so TaskList's own field names and types apply
+	data, err = json.Marshal(doc)
+	if err != nil {
+		return TaskList{}, &corruptBoardError{Path: path, Err: err}
+	}
+	var taskList TaskList
+	if err := json.Unmarshal(data, &taskList); err != nil {
+		return TaskList{}, &corruptBoardError{Path: path, Err: err}
+	}
+	return taskList, nil
+}
+
+func (yamlFormat) write(path string, taskList TaskList) error {
+	data, err := json.Marshal(taskList)
+	if err != nil {
+		return err
+	}
+	// JSON is YAML, and decoding into a node keeps the field order
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	plainStyle(&doc)
+
+	if old, err := os.ReadFile(path); err == nil {
+		var prev yaml.Node
+		if yaml.Unmarshal(old, &prev) == nil && len(prev.Content) > 0 {
+			doc.HeadComment = prev.HeadComment
+			doc.Content[0].HeadComment = prev.Content[0].HeadComment
+		}
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	enc.Close()
+	return os.WriteFile(path, out.Bytes(), 0644)
+}